@@ -7,19 +7,29 @@ import (
 	"net/http"
 
 	"github.com/MukizuL/shortener/internal/controller"
+	"github.com/MukizuL/shortener/internal/gateway"
 	"github.com/MukizuL/shortener/internal/interceptor"
 	"github.com/MukizuL/shortener/internal/migration"
 	"go.uber.org/fx/fxevent"
 	"google.golang.org/grpc"
 
+	"github.com/MukizuL/shortener/internal/admin"
+	"github.com/MukizuL/shortener/internal/clicks"
 	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/domain"
 	jwtService "github.com/MukizuL/shortener/internal/jwt"
+	"github.com/MukizuL/shortener/internal/limiter"
 	mw "github.com/MukizuL/shortener/internal/middleware"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/MukizuL/shortener/internal/publicurl"
 	"github.com/MukizuL/shortener/internal/router"
 	"github.com/MukizuL/shortener/internal/server"
+	"github.com/MukizuL/shortener/internal/service"
 	"github.com/MukizuL/shortener/internal/storage"
 	"github.com/MukizuL/shortener/internal/storage/mapstorage"
 	"github.com/MukizuL/shortener/internal/storage/pgstorage"
+	"github.com/MukizuL/shortener/internal/webhook"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -48,7 +58,7 @@ func main() {
 			return &fxevent.ZapLogger{Logger: log}
 		}),
 		createApp(),
-		fx.Invoke(func(*http.Server, *grpc.Server) {}),
+		fx.Invoke(func(*http.Server, *grpc.Server, *webhook.Dispatcher) {}),
 	).Run()
 }
 
@@ -57,11 +67,21 @@ func createApp() fx.Option {
 		config.Provide(),
 		fx.Provide(zap.NewDevelopment),
 		mw.Provide(),
+		service.Provide(),
 		controller.Provide(),
 		router.Provide(),
 		server.Provide(),
 		jwtService.Provide(),
 		interceptor.Provide(),
+		gateway.Provide(),
+		limiter.Provide(),
+		clicks.Provide(),
+		policy.Provide(),
+		proxy.Provide(),
+		publicurl.Provide(),
+		domain.Provide(),
+		admin.Provide(),
+		webhook.Provide(),
 
 		pgstorage.Provide(),
 		mapstorage.Provide(),
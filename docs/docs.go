@@ -43,6 +43,18 @@ const docTemplate = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace to create the link in",
+                        "name": "workspace_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short domain to create the link on, the requested one by default",
+                        "name": "domain",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -64,6 +76,12 @@ const docTemplate = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "No edit access to workspace",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "409": {
                         "description": "URL already exists",
                         "schema": {
@@ -71,7 +89,7 @@ const docTemplate = `{
                         }
                     },
                     "422": {
-                        "description": "Not a URL",
+                        "description": "Not a URL, rejected by URL policy or unknown domain",
                         "schema": {
                             "type": "string"
                         }
@@ -87,6 +105,10 @@ const docTemplate = `{
         },
         "/:id": {
             "get": {
+                "description": "For password protected links serves a password form instead. The form is posted back to the same URL.\nLinks with a click limit answer 410 once all clicks are used up. HEAD requests don't use up clicks.\nRedirects with the status code chosen at creation, 307 by default.\nID with a trailing \"+\" or preview=1 query parameter render a preview page instead of redirecting.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
                 "produces": [
                     "text/html"
                 ],
@@ -108,24 +130,64 @@ const docTemplate = `{
                         "name": "ID",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected link",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 1 to render the preview page",
+                        "name": "preview",
+                        "in": "query"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Password form or preview page",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently"
+                    },
+                    "302": {
+                        "description": "Found"
+                    },
+                    "303": {
+                        "description": "See Other"
+                    },
                     "307": {
                         "description": "Temporary Redirect",
                         "headers": {
+                            "Cache-Control": {
+                                "type": "string",
+                                "description": "Caching policy of the redirect"
+                            },
                             "Location": {
                                 "type": "string",
                                 "description": "Original URL"
                             }
                         }
                     },
+                    "308": {
+                        "description": "Permanent Redirect"
+                    },
                     "400": {
                         "description": "ID is not present",
                         "schema": {
                             "type": "string"
                         }
                     },
+                    "401": {
+                        "description": "Wrong password",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "URL not Found",
                         "schema": {
@@ -133,226 +195,1697 @@ const docTemplate = `{
                         }
                     },
                     "410": {
-                        "description": "URL deleted",
+                        "description": "URL deleted, click limit exhausted or link disabled",
                         "schema": {
                             "type": "string"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "429": {
+                        "description": "Too many failed password attempts",
                         "schema": {
                             "type": "string"
                         }
-                    }
-                }
-            }
-        },
-        "/api/internal/stats": {
-            "get": {
-                "description": "Access only allowed from trusted_subnet.",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "json"
-                ],
-                "summary": "Provides service stats",
-                "responses": {
-                    "200": {
-                        "description": "Stats",
+                    },
+                    "451": {
+                        "description": "Link disabled for legal reasons",
                         "schema": {
-                            "type": "body"
+                            "type": "string"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/api/shorten": {
+            },
             "post": {
-                "description": "If cookie with access token is not provided, creates a new token with new userID.",
+                "description": "For password protected links serves a password form instead. The form is posted back to the same URL.\nLinks with a click limit answer 410 once all clicks are used up. HEAD requests don't use up clicks.\nRedirects with the status code chosen at creation, 307 by default.\nID with a trailing \"+\" or preview=1 query parameter render a preview page instead of redirecting.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
-                    "json"
+                    "default"
                 ],
-                "summary": "Creates short URL",
+                "summary": "Redirects to original URL",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Cookie with access token",
                         "name": "Cookie",
-                        "in": "header"
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "description": "URL to shorten",
-                        "name": "URL",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.Request"
-                        }
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "ID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected link",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 1 to render the preview page",
+                        "name": "preview",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Short url",
+                    "200": {
+                        "description": "Password form or preview page",
                         "schema": {
-                            "type": "body"
-                        },
+                            "type": "string"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently"
+                    },
+                    "302": {
+                        "description": "Found"
+                    },
+                    "303": {
+                        "description": "See Other"
+                    },
+                    "307": {
+                        "description": "Temporary Redirect",
                         "headers": {
-                            "Set-cookie": {
+                            "Cache-Control": {
                                 "type": "string",
-                                "description": "Access token"
+                                "description": "Caching policy of the redirect"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Original URL"
                             }
                         }
                     },
+                    "308": {
+                        "description": "Permanent Redirect"
+                    },
                     "400": {
-                        "description": "Wrong URL schema",
+                        "description": "ID is not present",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong password",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
                         }
                     },
-                    "409": {
-                        "description": "URL already exists",
+                    "404": {
+                        "description": "URL not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
                         }
                     },
-                    "422": {
-                        "description": "Not a URL",
+                    "410": {
+                        "description": "URL deleted, click limit exhausted or link disabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed password attempts",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
+                        }
+                    },
+                    "451": {
+                        "description": "Link disabled for legal reasons",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/api/shorten/batch": {
-            "post": {
-                "description": "If cookie with access token is not provided, creates a new token with new userID.",
+            },
+            "head": {
+                "description": "For password protected links serves a password form instead. The form is posted back to the same URL.\nLinks with a click limit answer 410 once all clicks are used up. HEAD requests don't use up clicks.\nRedirects with the status code chosen at creation, 307 by default.\nID with a trailing \"+\" or preview=1 query parameter render a preview page instead of redirecting.",
                 "consumes": [
-                    "application/json"
+                    "application/x-www-form-urlencoded"
                 ],
                 "produces": [
-                    "application/json"
+                    "text/html"
                 ],
                 "tags": [
-                    "json"
+                    "default"
                 ],
-                "summary": "Creates a batch of short URLs",
+                "summary": "Redirects to original URL",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Cookie with access token",
                         "name": "Cookie",
-                        "in": "header"
+                        "in": "header",
+                        "required": true
                     },
                     {
-                        "description": "URLs to shorten",
-                        "name": "URL",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/dto.BatchRequest"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "ID",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Password of a protected link",
+                        "name": "password",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to 1 to render the preview page",
+                        "name": "preview",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Short urls",
+                    "200": {
+                        "description": "Password form or preview page",
                         "schema": {
-                            "type": "body"
-                        },
+                            "type": "string"
+                        }
+                    },
+                    "301": {
+                        "description": "Moved Permanently"
+                    },
+                    "302": {
+                        "description": "Found"
+                    },
+                    "303": {
+                        "description": "See Other"
+                    },
+                    "307": {
+                        "description": "Temporary Redirect",
                         "headers": {
-                            "Set-cookie": {
+                            "Cache-Control": {
                                 "type": "string",
-                                "description": "Access token"
+                                "description": "Caching policy of the redirect"
+                            },
+                            "Location": {
+                                "type": "string",
+                                "description": "Original URL"
                             }
                         }
                     },
+                    "308": {
+                        "description": "Permanent Redirect"
+                    },
                     "400": {
-                        "description": "Wrong URL schema",
+                        "description": "ID is not present",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong password",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
                         }
                     },
-                    "409": {
-                        "description": "URL already exists",
+                    "404": {
+                        "description": "URL not Found",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
                         }
                     },
-                    "422": {
-                        "description": "Not a URL",
+                    "410": {
+                        "description": "URL deleted, click limit exhausted or link disabled",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "429": {
+                        "description": "Too many failed password attempts",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
+                        }
+                    },
+                    "451": {
+                        "description": "Link disabled for legal reasons",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.Envelope"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/api/user/urls": {
+        "/api/admin/audit": {
             "get": {
+                "description": "For moderators: admin users and clients from the trusted subnet. Every create, delete,\nupdate and moderation is recorded with its actor, address, transport and request ID.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "json"
+                    "admin"
                 ],
-                "summary": "Returns array of user URLs",
+                "summary": "Returns the audit log",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Cookie with access token",
+                        "description": "Cookie with access token of an admin",
                         "name": "Cookie",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User who made the changes",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short URL ID of the changed link",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short domain of the link, any if omitted",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Action, like link.create or link.disable",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start of the time range, RFC 3339, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End of the time range, RFC 3339, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of events, 100 if omitted, at most 1000",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Array of URLs",
+                        "description": "Events, newest first",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/dto.URLPair"
+                                "$ref": "#/definitions/dto.AuditEvent"
                             }
                         }
                     },
+                    "400": {
+                        "description": "Malformed query",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.ResponseWrapper"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/admin/hosts/{host}/disable": {
+            "post": {
+                "description": "For moderators: admin users and clients from the trusted subnet. Links to subdomains\nof the host are disabled too, on every short domain.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Disables every link pointing at a host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Host, like example.com",
+                        "name": "host",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status and reason",
+                        "name": "Takedown",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TakedownRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of disabled links",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TakedownResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed takedown or host",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/links": {
+            "get": {
+                "description": "For moderators: admin users and clients from the trusted subnet. At least one of id, url, host\nand user must be given, all given ones must match.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Finds links of every user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short domain of the link, any if omitted",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Destination, matched as given and in canonical form",
+                        "name": "url",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Host of the destination, subdomains included",
+                        "name": "host",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Creator of the link",
+                        "name": "user",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Links, newest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AdminLink"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "No or malformed query",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/links/{id}/disable": {
+            "post": {
+                "description": "For moderators: admin users and clients from the trusted subnet. Visitors of the link get\nthe takedown status and see the reason instead of being redirected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Disables a link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short domain of the link, the default one if omitted",
+                        "name": "domain",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Status and reason",
+                        "name": "Takedown",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.TakedownRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Malformed takedown or unknown domain",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "For moderators: admin users and clients from the trusted subnet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Enables a disabled link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short domain of the link, the default one if omitted",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Unknown domain",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "Link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/reports": {
+            "get": {
+                "description": "For moderators: admin users and clients from the trusted subnet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Returns the abuse report queue",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending (default), approved or dismissed",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reports, oldest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AbuseReport"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/reports/{reportID}/approve": {
+            "post": {
+                "description": "For moderators: admin users and clients from the trusted subnet. Disables the reported link\nand approves every pending report about it. The reason of the report is shown to visitors\nif the takedown has none. The body may be omitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Approves an abuse report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "reportID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status and reason",
+                        "name": "Takedown",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dto.TakedownRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Malformed takedown",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "Report or link not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "409": {
+                        "description": "Report is already resolved",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/reports/{reportID}/dismiss": {
+            "post": {
+                "description": "For moderators: admin users and clients from the trusted subnet. Dismisses every pending report\nabout the reported link. A link disabled by reports is enabled again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Dismisses an abuse report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Report ID",
+                        "name": "reportID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "Report not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "409": {
+                        "description": "Report is already resolved",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/admin/users/{userID}/links": {
+            "get": {
+                "description": "For moderators: admin users and clients from the trusted subnet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Returns every link created by a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token of an admin",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Links, newest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AdminLink"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed user ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "401": {
+                        "description": "No access token",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not an admin",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/internal/stats": {
+            "get": {
+                "description": "Access only allowed from trusted_subnet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "json"
+                ],
+                "summary": "Provides service stats",
+                "responses": {
+                    "200": {
+                        "description": "Stats",
+                        "schema": {
+                            "type": "body"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/links/{id}": {
+            "get": {
+                "description": "Works without a token. Destination of a password protected link, click counters,\nworkspace and redirect type are only returned to the owner and members of the link's workspace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "json"
+                ],
+                "summary": "Returns information about a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Link information",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LinkInfo"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "410": {
+                        "description": "URL deleted",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/shorten": {
+            "post": {
+                "description": "If cookie with access token is not provided, creates a new token with new userID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "json"
+                ],
+                "summary": "Creates short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "description": "URL to shorten",
+                        "name": "URL",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.Request"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Short url",
+                        "schema": {
+                            "type": "body"
+                        },
+                        "headers": {
+                            "Set-cookie": {
+                                "type": "string",
+                                "description": "Access token"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Wrong URL schema",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "No edit access to workspace",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "409": {
+                        "description": "URL already exists",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "422": {
+                        "description": "Not a URL, rejected by URL policy or unknown domain",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/shorten/batch": {
+            "post": {
+                "description": "If cookie with access token is not provided, creates a new token with new userID.\nEvery item gets a result with status created, existing (with the existing short URL) or invalid (with a reason).\nInvalid and existing items don't prevent the rest from being created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "json"
+                ],
+                "summary": "Creates a batch of short URLs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "description": "URLs to shorten",
+                        "name": "URL",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BatchRequest"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace to create the links in",
+                        "name": "workspace_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short domain to create the links on, the requested one by default",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "All short urls created",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BatchResponse"
+                            }
+                        },
+                        "headers": {
+                            "Set-cookie": {
+                                "type": "string",
+                                "description": "Access token"
+                            }
+                        }
+                    },
+                    "207": {
+                        "description": "Some items are existing or invalid",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.BatchResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "No edit access to workspace",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown domain",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/shorten/stream": {
+            "post": {
+                "description": "Takes one dto.BatchRequest per line and streams back one dto.BatchResponse per line\nas items are committed in chunks of 500. Results have the same statuses as in a batch.\nAn error after the response has started is reported as the last line {\"error\": \"...\"},\nitems before it are created.",
+                "consumes": [
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "json"
+                ],
+                "summary": "Creates short URLs from a stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "description": "URLs to shorten, one per line",
+                        "name": "URL",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace to create the links in",
+                        "name": "workspace_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Short domain to create the links on, the requested one by default",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Results, one per line",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BatchResponse"
+                        },
+                        "headers": {
+                            "Set-cookie": {
+                                "type": "string",
+                                "description": "Access token"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "No edit access to workspace",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "415": {
+                        "description": "Content type is not application/x-ndjson",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown domain",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/events": {
+            "get": {
+                "description": "Server-sent events named link.created, link.clicked and link.deleted, their data is the webhook\ndelivery body. A comment is sent every 15 seconds while idle. A client reconnecting with the\nLast-Event-ID header, or the lastEventId query parameter, first gets the events it missed\namong the latest kept. Slow clients are disconnected and catch up the same way.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "activity"
+                ],
+                "summary": "Streams events about the user's links as they happen",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the last event received",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream of events",
+                        "schema": {
+                            "$ref": "#/definitions/dto.WebhookEvent"
+                        }
+                    },
+                    "503": {
+                        "description": "Link events are not available",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/urls": {
+            "get": {
+                "description": "Includes URLs of all workspaces the user is a member of.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "json"
+                ],
+                "summary": "Returns array of user URLs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lists links on this short domain only",
+                        "name": "domain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Array of URLs",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.URLPair"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown domain",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Accepts array of short URL IDs on the requested domain or full short URLs on any domain.\nWorkspace editors and owners may delete links of their workspaces.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "json"
+                ],
+                "summary": "Deletes user URLs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "URLs to delete",
+                        "name": "URLs",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "401": {
+                        "description": "URL doesn't belong to user",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/webhooks": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Returns the user's webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhooks without secrets, oldest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.Webhook"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No webhooks"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Events are link.created, link.clicked and link.deleted, all of them if none are given.\nDeliveries are POSTed with X-Webhook-Signature: sha256= followed by the hex HMAC-SHA256\nof \"\u003cX-Webhook-Timestamp\u003e.\u003cbody\u003e\" keyed with the secret, which is only returned here.\nFailed deliveries are retried with exponential backoff, then dead-lettered.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Subscribes to events about the user's links",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "description": "URL and events",
+                        "name": "Webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created webhook with its secret",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Malformed URL, private address or unknown event",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "409": {
+                        "description": "Too many webhooks",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/webhooks/{webhookID}": {
             "delete": {
+                "description": "Deliveries still queued for it are dropped.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Deletes a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/webhooks/{webhookID}/deliveries": {
+            "get": {
+                "description": "Dead deliveries failed every attempt and are only sent again by redelivery.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Returns the newest deliveries of a webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, delivered or dead, any if omitted",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries, newest first",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.WebhookDelivery"
+                            }
+                        }
+                    },
+                    "204": {
+                        "description": "No deliveries"
+                    },
+                    "400": {
+                        "description": "Unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver": {
+            "post": {
+                "description": "The delivery is queued with fresh attempts whatever its status, dead ones included.\nReceivers may deduplicate on X-Webhook-Delivery, it stays the same.",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Sends a delivery again",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "webhookID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery ID",
+                        "name": "deliveryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    },
+                    "404": {
+                        "description": "Delivery not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspaces": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Returns workspaces the user is a member of",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Array of workspaces",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.Workspace"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "The user becomes the owner of the new workspace.",
                 "consumes": [
                     "application/json"
                 ],
@@ -360,9 +1893,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "json"
+                    "workspaces"
                 ],
-                "summary": "Deletes user URLs",
+                "summary": "Creates a workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Workspace",
+                        "name": "Workspace",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.WorkspaceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created workspace",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Workspace"
+                        }
+                    },
+                    "400": {
+                        "description": "Empty name",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspaces/{id}/members": {
+            "post": {
+                "description": "Only the workspace owner may add members or change their roles. The last owner can't be demoted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Adds a member to a workspace",
                 "parameters": [
                     {
                         "type": "string",
@@ -372,35 +1957,268 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "URLs to delete",
-                        "name": "URLs",
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Member",
+                        "name": "Member",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "type": "string"
+                            "$ref": "#/definitions/dto.MemberRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Wrong user ID or role",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "403": {
+                        "description": "User is not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "Workspace not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "409": {
+                        "description": "Last owner of the workspace",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/workspaces/{id}/members/{userID}": {
+            "delete": {
+                "description": "Only the workspace owner may remove members. The last owner can't be removed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workspaces"
+                ],
+                "summary": "Removes a member from a workspace",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cookie with access token",
+                        "name": "Cookie",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Workspace ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Member user ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "403": {
+                        "description": "User is not the owner",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "Workspace or member not found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "409": {
+                        "description": "Last owner of the workspace",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    }
+                }
+            }
+        },
+        "/{id}/qr": {
+            "get": {
+                "description": "Encodes the full short URL. Supports conditional requests with If-None-Match.",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "default"
+                ],
+                "summary": "Returns QR code of a short URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "png (default) or svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Width and height in pixels, 64 to 1024, 256 by default",
+                        "name": "size",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Error correction level: L, M (default), Q or H",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of a cached image",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "QR code",
+                        "schema": {
+                            "type": "file"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Version of the image"
                             }
                         }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Invalid format, size or level",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "410": {
+                        "description": "URL deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/{id}/report": {
+            "post": {
+                "description": "Files an abuse report for moderators to review. Accepts a JSON body or a posted HTML form.\nOnce enough distinct visitors report a link within the report window, it is disabled\nuntil a moderator reviews the reports.",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "default"
+                ],
+                "summary": "Reports an abusive link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Short URL ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason and optional contact",
+                        "name": "Report",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.ReportRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Accepted",
+                        "description": "Accepted"
+                    },
+                    "400": {
+                        "description": "No reason, too long reason or contact",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ResponseWrapper"
+                        }
+                    },
+                    "404": {
+                        "description": "URL not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.ResponseWrapper"
                         }
                     },
-                    "401": {
-                        "description": "URL doesn't belong to user",
+                    "410": {
+                        "description": "URL deleted",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.ResponseWrapper"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/dto.ResponseWrapper"
                         }
                     }
                 }
@@ -408,6 +2226,116 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "dto.AbuseReport": {
+            "type": "object",
+            "properties": {
+                "contact": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reporter": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AdminLink": {
+            "type": "object",
+            "properties": {
+                "clicks_left": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "disabled_status": {
+                    "type": "integer"
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_ip": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "before": {
+                    "type": "object",
+                    "additionalProperties": {}
+                },
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                },
+                "transport": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                }
+            }
+        },
         "dto.BatchRequest": {
             "type": "object",
             "properties": {
@@ -419,25 +2347,291 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.Envelope": {
+        "dto.BatchResponse": {
             "type": "object",
-            "additionalProperties": true
+            "properties": {
+                "correlation_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.LinkInfo": {
+            "type": "object",
+            "properties": {
+                "clicks_left": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "boolean"
+                },
+                "protected": {
+                    "type": "boolean"
+                },
+                "redirect_type": {
+                    "type": "integer"
+                },
+                "short_url": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.MemberRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ReportRequest": {
+            "type": "object",
+            "properties": {
+                "contact": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
         },
         "dto.Request": {
             "type": "object",
             "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "max_clicks": {
+                    "type": "integer"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "redirect_type": {
+                    "type": "integer"
+                },
                 "url": {
                     "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.ResponseWrapper": {
+            "type": "object",
+            "additionalProperties": true
+        },
+        "dto.TakedownRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dto.TakedownResponse": {
+            "type": "object",
+            "properties": {
+                "disabled": {
+                    "type": "integer"
                 }
             }
         },
         "dto.URLPair": {
             "type": "object",
             "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "original_url": {
+                    "type": "string"
+                },
+                "short_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WebhookClick": {
+            "type": "object",
+            "properties": {
+                "client_ip": {
+                    "type": "string"
+                },
+                "referer": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "only for pending deliveries",
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WebhookEvent": {
+            "type": "object",
+            "properties": {
+                "click": {
+                    "description": "only for link.clicked",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/dto.WebhookClick"
+                        }
+                    ]
+                },
+                "event": {
+                    "type": "string"
+                },
+                "link": {
+                    "$ref": "#/definitions/dto.WebhookLink"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WebhookLink": {
+            "type": "object",
+            "properties": {
+                "domain": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
                 "original_url": {
                     "type": "string"
                 },
                 "short_url": {
+                    "description": "only for link.created",
+                    "type": "string"
+                },
+                "workspace_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WebhookRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.Workspace": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "dto.WorkspaceRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
                     "type": "string"
                 }
             }
@@ -0,0 +1,77 @@
+// Package admin tells who may use the admin API: users with the admin role and clients from the trusted subnet.
+package admin
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/google/uuid"
+	"go.uber.org/fx"
+)
+
+// Guard holds the admin users and the trusted subnet. A nil Guard trusts nobody.
+type Guard struct {
+	users   map[string]struct{}
+	trusted proxy.Subnets
+}
+
+// New creates a Guard trusting clients from trusted and giving userIDs the admin role.
+func New(trusted proxy.Subnets, userIDs ...string) *Guard {
+	g := &Guard{users: make(map[string]struct{}, len(userIDs)), trusted: trusted}
+	for _, userID := range userIDs {
+		g.users[userID] = struct{}{}
+	}
+
+	return g
+}
+
+func newGuard(cfg *config.Config) (*Guard, error) {
+	trusted, err := proxy.ParsePrefixes(cfg.TrustedCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing trusted subnet: %w", err)
+	}
+
+	var userIDs []string
+	for _, userID := range strings.Split(cfg.AdminUsers, ",") {
+		userID = strings.TrimSpace(userID)
+		if userID == "" {
+			continue
+		}
+
+		if uuid.Validate(userID) != nil {
+			return nil, fmt.Errorf("error parsing admin user %q: %w", userID, errs.ErrInvalidUserID)
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	return New(trusted, userIDs...), nil
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newGuard)
+}
+
+// IsAdmin reports whether userID has the admin role.
+func (g *Guard) IsAdmin(userID string) bool {
+	if g == nil {
+		return false
+	}
+
+	_, ok := g.users[userID]
+	return ok
+}
+
+// Trusted reports whether a client at addr is in the trusted subnet.
+func (g *Guard) Trusted(addr netip.Addr) bool {
+	return g != nil && g.trusted.Contains(addr)
+}
+
+// HasTrustedSubnet reports whether any client may be trusted by address.
+func (g *Guard) HasTrustedSubnet() bool {
+	return g != nil && len(g.trusted) > 0
+}
@@ -0,0 +1,185 @@
+// Package clicks delivers events about links of a user, redirects among them, to subscribers as they happen,
+// and keeps the latest ones so that a subscriber reconnecting after a dropped connection resumes where it stopped.
+package clicks
+
+import (
+	"net/netip"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// BufferSize is the number of events a subscriber may lag behind before it is dropped.
+	// It gets the events it missed from the history when it resubscribes.
+	BufferSize = 64
+	// HistorySize is the number of the latest events of a user kept to resume from.
+	HistorySize = 256
+	// Retention is how long the history of a user is kept after their last subscriber went away.
+	Retention = 10 * time.Minute
+)
+
+// Event is an event about a link, a redirect if Type is models.WebhookLinkClicked.
+type Event struct {
+	// ID grows with every event published, across restarts too, as it starts from the time the Hub was made.
+	ID uint64
+	// Type is one of models.WebhookEvents.
+	Type     string
+	ShortURL string
+	// UserID is the creator of the link.
+	UserID string
+	Time   time.Time
+	// ClientIP is the visitor's address of a redirect, resolved through trusted proxies. It is invalid if unknown.
+	ClientIP  netip.Addr
+	Referer   string
+	UserAgent string
+	// Data is the JSON encoded dto.WebhookEvent.
+	Data []byte
+}
+
+// feed holds the subscribers and history of a user.
+type feed struct {
+	subs map[chan Event]struct{}
+	// history is ordered by ID, oldest first.
+	history []Event
+	// idleSince is when the last subscriber went away, zero while there are subscribers.
+	idleSince time.Time
+}
+
+// Hub fans events out to subscribers of the link creator. Events are only kept for users that subscribed
+// within Retention, as no one else can resume. Publishing never blocks on a slow subscriber.
+// A nil Hub drops every event.
+type Hub struct {
+	users  map[string]*feed
+	lastID uint64
+	m      sync.Mutex
+	logger *zap.Logger
+}
+
+func New(logger *zap.Logger) *Hub {
+	return &Hub{
+		users:  make(map[string]*feed),
+		lastID: uint64(time.Now().UnixMicro()),
+		logger: logger,
+	}
+}
+
+func Provide() fx.Option {
+	return fx.Provide(New)
+}
+
+// Subscribe returns events for links created by userID kept after lastID, oldest first, then a channel of new ones and a function
+// that cancels the subscription. None are kept after a lastID of 0. The channel is closed if the subscriber
+// falls BufferSize events behind.
+func (h *Hub) Subscribe(userID string, lastID uint64) ([]Event, <-chan Event, func()) {
+	ch := make(chan Event, BufferSize)
+
+	h.m.Lock()
+	h.sweep(time.Now())
+
+	f := h.users[userID]
+	if f == nil {
+		f = &feed{subs: make(map[chan Event]struct{})}
+		h.users[userID] = f
+	}
+	f.subs[ch] = struct{}{}
+	f.idleSince = time.Time{}
+
+	var missed []Event
+	if lastID != 0 {
+		for i, e := range f.history {
+			if e.ID > lastID {
+				missed = append(missed, f.history[i:]...)
+				break
+			}
+		}
+	}
+	h.m.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.m.Lock()
+			defer h.m.Unlock()
+
+			_, ok := f.subs[ch]
+			if !ok {
+				// Dropped by Publish.
+				return
+			}
+
+			delete(f.subs, ch)
+			h.leave(f)
+		})
+	}
+
+	return missed, ch, cancel
+}
+
+// Watched reports whether events of userID are streamed or kept, to skip building events no one receives.
+func (h *Hub) Watched(userID string) bool {
+	if h == nil {
+		return false
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	f, ok := h.users[userID]
+
+	return ok && (len(f.subs) > 0 || time.Since(f.idleSince) <= Retention)
+}
+
+// Publish assigns e the next ID, keeps it for resuming and sends it to subscribers of its user.
+func (h *Hub) Publish(e Event) {
+	if h == nil || e.UserID == "" {
+		return
+	}
+
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.lastID++
+	e.ID = h.lastID
+
+	f := h.users[e.UserID]
+	if f == nil {
+		return
+	}
+
+	if len(f.history) == HistorySize {
+		copy(f.history, f.history[1:])
+		f.history = f.history[:HistorySize-1]
+	}
+	f.history = append(f.history, e)
+
+	for ch := range f.subs {
+		select {
+		case ch <- e:
+		default:
+			// Let it resume from the history rather than silently miss events.
+			delete(f.subs, ch)
+			close(ch)
+			h.leave(f)
+			h.logger.Debug("Slow link event subscriber dropped", zap.String("user_id", e.UserID))
+		}
+	}
+}
+
+// leave starts the retention of the history of f once its last subscriber went away. h.m must be held.
+func (h *Hub) leave(f *feed) {
+	if len(f.subs) == 0 {
+		f.idleSince = time.Now()
+	}
+}
+
+// sweep forgets users without subscribers for longer than Retention. h.m must be held.
+func (h *Hub) sweep(now time.Time) {
+	for userID, f := range h.users {
+		if len(f.subs) == 0 && now.Sub(f.idleSince) > Retention {
+			delete(h.users, userID)
+		}
+	}
+}
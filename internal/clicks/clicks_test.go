@@ -0,0 +1,96 @@
+package clicks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHub_Publish(t *testing.T) {
+	h := New(zap.NewNop())
+
+	// Events of users who never subscribed aren't kept.
+	h.Publish(Event{Type: "link.created", UserID: "user1"})
+	assert.False(t, h.Watched("user1"))
+
+	missed, events, cancel := h.Subscribe("user1", 0)
+	assert.Empty(t, missed)
+	_, other, cancelOther := h.Subscribe("user2", 0)
+	defer cancelOther()
+
+	h.Publish(Event{Type: "link.clicked", UserID: "user1"})
+	h.Publish(Event{Type: "link.deleted", UserID: "user1"})
+
+	first, second := <-events, <-events
+	assert.Equal(t, "link.clicked", first.Type)
+	assert.Equal(t, first.ID+1, second.ID)
+	assert.Empty(t, other)
+
+	// A slow subscriber is dropped rather than blocking publishing.
+	for range BufferSize + 1 {
+		h.Publish(Event{Type: "link.clicked", UserID: "user1"})
+	}
+	assert.Len(t, events, BufferSize)
+	for range events {
+	}
+
+	cancel()
+	cancel()
+	assert.True(t, h.Watched("user1"), "history is kept for resuming")
+}
+
+func TestHub_Resume(t *testing.T) {
+	h := New(zap.NewNop())
+
+	_, _, cancel := h.Subscribe("user1", 0)
+	cancel()
+
+	for range HistorySize + 10 {
+		h.Publish(Event{Type: "link.clicked", UserID: "user1"})
+	}
+
+	missed, _, cancel := h.Subscribe("user1", 0)
+	cancel()
+	assert.Empty(t, missed, "a fresh subscriber starts from new events")
+
+	all, _, cancel := h.Subscribe("user1", 1)
+	cancel()
+	require.Len(t, all, HistorySize, "only the latest events are kept")
+
+	last := all[len(all)-1]
+	missed, _, cancel = h.Subscribe("user1", last.ID-2)
+	cancel()
+	require.Len(t, missed, 2)
+	assert.Equal(t, last, missed[1])
+
+	missed, _, cancel = h.Subscribe("user1", last.ID)
+	cancel()
+	assert.Empty(t, missed)
+}
+
+func TestHub_Retention(t *testing.T) {
+	h := New(zap.NewNop())
+
+	_, _, cancel := h.Subscribe("user1", 0)
+	cancel()
+	h.Publish(Event{Type: "link.clicked", UserID: "user1"})
+
+	h.users["user1"].idleSince = time.Now().Add(-Retention - time.Second)
+	assert.False(t, h.Watched("user1"))
+
+	missed, _, cancel := h.Subscribe("user1", 1)
+	defer cancel()
+	assert.Empty(t, missed, "history is forgotten after Retention")
+}
+
+func TestHub_Nil(t *testing.T) {
+	var h *Hub
+
+	assert.NotPanics(t, func() {
+		h.Publish(Event{Type: "link.clicked", UserID: "user1"})
+	})
+	assert.False(t, h.Watched("user1"))
+}
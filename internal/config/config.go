@@ -10,9 +10,11 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MukizuL/shortener/docs"
 	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
 	"github.com/caarlos0/env/v11"
 	"go.uber.org/fx"
 )
@@ -20,12 +22,41 @@ import (
 var ErrMalformedFlags = errors.New("error parsing flags")
 var ErrMalformedAddr = errors.New("address of wrong format")
 var ErrMalformedBase = errors.New("base should be an url")
+var ErrMalformedPublicURL = errors.New("public url should be an absolute http(s) url")
+var ErrMalformedDuration = errors.New("durations must be like 10s or 24h")
+
+// DefaultStreamMaxItems is the default cap on items in one streaming batch request.
+const DefaultStreamMaxItems = 100000
+
+const (
+	// DefaultReportThreshold is the default number of distinct reporters that disable a link.
+	DefaultReportThreshold = 5
+	// DefaultReportWindow is the default period reports are counted over.
+	DefaultReportWindow = 24 * time.Hour
+)
+
+const (
+	// DefaultWebhookMaxAttempts is the default number of attempts to deliver a webhook before it is dead-lettered.
+	DefaultWebhookMaxAttempts = 8
+	// DefaultWebhookTimeout is the default time a webhook receiver has to answer.
+	DefaultWebhookTimeout = 10 * time.Second
+)
 
 // Config holds all application configuration.
 type Config struct {
-	Addr           string `env:"SERVER_ADDRESS" json:"server_address"`
-	Base           string `env:"BASE_URL" json:"base_url"`
-	TrustedCIDR    string `env:"TRUSTED_SUBNET" json:"trusted_subnet"`
+	Addr string `env:"SERVER_ADDRESS" json:"server_address"`
+	Base string `env:"BASE_URL" json:"base_url"`
+	// PublicURL is the base of every generated short URL. If empty, it is BASE_URL when that is an absolute URL,
+	// otherwise the scheme and host of each request.
+	PublicURL string `env:"PUBLIC_URL" json:"public_url"`
+	// TrustedProxies is a comma separated list of CIDRs of reverse proxies whose forwarding headers are believed.
+	TrustedProxies string `env:"TRUSTED_PROXIES" json:"trusted_proxies"`
+	// Domains is a comma separated list of short domains links may be created on. The first one is the default.
+	Domains string `env:"DOMAINS" json:"domains"`
+	// TrustedCIDR is a comma separated list of CIDRs of clients allowed to call internal endpoints.
+	TrustedCIDR string `env:"TRUSTED_SUBNET" json:"trusted_subnet"`
+	// AdminUsers is a comma separated list of user IDs whose access tokens have the admin role.
+	AdminUsers     string `env:"ADMIN_USERS" json:"admin_users"`
 	Config         string `env:"CONFIG" json:"config"`
 	Filepath       string `env:"FILE_STORAGE_PATH" json:"file_storage_path"`
 	DSN            string `env:"DATABASE_DSN" json:"database_dsn"`
@@ -35,7 +66,36 @@ type Config struct {
 	Cert  string `env:"CERT_PATH" json:"cert_path"`
 	PK    string `env:"PK_PATH" json:"pk_path"`
 
-	GRPCPort string `env:"GRPC_PORT" json:"grpc_port"`
+	GRPCPort       string `env:"GRPC_PORT" json:"grpc_port"`
+	GRPCReflection bool   `env:"GRPC_REFLECTION" json:"grpc_reflection"`
+	// GRPCClientCA is a PEM bundle of CAs that sign client certificates. Setting it turns on mutual TLS.
+	GRPCClientCA string `env:"GRPC_CLIENT_CA_PATH" json:"grpc_client_ca_path"`
+	// GRPCTrustedClients is a comma separated list of client certificate names (CN or DNS SAN) that are trusted
+	// like the trusted subnet. If empty, every client certificate signed by GRPCClientCA is trusted.
+	GRPCTrustedClients string `env:"GRPC_TRUSTED_CLIENTS" json:"grpc_trusted_clients"`
+	// GRPCDrainDelay is how long the gRPC server reports NOT_SERVING on shutdown before it stops taking calls,
+	// so that load balancers polling health checks move traffic away first.
+	GRPCDrainDelay time.Duration `env:"GRPC_DRAIN_DELAY" json:"grpc_drain_delay"`
+
+	DenyList  string `env:"DENY_LIST_PATH" json:"deny_list_path"`
+	AllowList string `env:"ALLOW_LIST_PATH" json:"allow_list_path"`
+
+	SortQuery     bool `env:"CANONICAL_SORT_QUERY" json:"canonical_sort_query"`
+	StripTracking bool `env:"CANONICAL_STRIP_TRACKING" json:"canonical_strip_tracking"`
+
+	DedupeScope string `env:"DEDUPE_SCOPE" json:"dedupe_scope"`
+
+	StreamMaxItems int `env:"STREAM_MAX_ITEMS" json:"stream_max_items"`
+
+	// ReportThreshold is the number of distinct reporters within ReportWindow that disable a link until a moderator
+	// reviews the reports. Zero, like unset, means DefaultReportThreshold, so links can always be disabled by reports.
+	ReportThreshold int           `env:"REPORT_THRESHOLD" json:"report_threshold"`
+	ReportWindow    time.Duration `env:"REPORT_WINDOW" json:"report_window"`
+
+	WebhookMaxAttempts int           `env:"WEBHOOK_MAX_ATTEMPTS" json:"webhook_max_attempts"`
+	WebhookTimeout     time.Duration `env:"WEBHOOK_TIMEOUT" json:"webhook_timeout"`
+	// WebhookAllowPrivate lets webhooks point at loopback and private addresses, like a CRM inside the network.
+	WebhookAllowPrivate bool `env:"WEBHOOK_ALLOW_PRIVATE" json:"webhook_allow_private"`
 
 	Debug bool `env:"DEBUG" json:"debug"`
 }
@@ -98,9 +158,21 @@ func checkParams(cfg *Config) error {
 			return ErrMalformedBase
 		}
 
+		// Only the path is needed for routing, a full URL also tells how links are reached.
+		if cfg.PublicURL == "" && parsedURL.Host != "" {
+			cfg.PublicURL = cfg.Base
+		}
+
 		cfg.Base = strings.TrimSuffix(parsedURL.RequestURI(), "/")
 	}
 
+	if cfg.PublicURL != "" {
+		parsedURL, err := url.Parse(cfg.PublicURL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			return ErrMalformedPublicURL
+		}
+	}
+
 	if !filepath.IsAbs(cfg.Filepath) {
 		temp, err := filepath.Abs(cfg.Filepath)
 		if err != nil {
@@ -139,6 +211,60 @@ func checkParams(cfg *Config) error {
 		}
 	}
 
+	if cfg.GRPCDrainDelay < 0 {
+		return errors.New("grpc drain delay must not be negative")
+	}
+
+	if cfg.GRPCClientCA != "" {
+		if !cfg.HTTPS {
+			return errs.ErrClientCAWithoutTLS
+		}
+
+		if _, err := os.Stat(cfg.GRPCClientCA); err != nil {
+			return fmt.Errorf("error reading client CA: %w", err)
+		}
+	}
+
+	if cfg.DedupeScope == "" {
+		cfg.DedupeScope = string(models.DedupeGlobal)
+	}
+
+	if !models.DedupeScope(cfg.DedupeScope).Valid() {
+		return errs.ErrUnknownDedupeScope
+	}
+
+	if cfg.StreamMaxItems == 0 {
+		cfg.StreamMaxItems = DefaultStreamMaxItems
+	}
+
+	if cfg.StreamMaxItems < 0 {
+		return errors.New("stream max items must be positive")
+	}
+
+	if cfg.ReportThreshold == 0 {
+		cfg.ReportThreshold = DefaultReportThreshold
+	}
+
+	if cfg.ReportWindow == 0 {
+		cfg.ReportWindow = DefaultReportWindow
+	}
+
+	if cfg.ReportThreshold < 0 || cfg.ReportWindow < 0 {
+		return errors.New("report threshold and window must be positive")
+	}
+
+	if cfg.WebhookMaxAttempts == 0 {
+		cfg.WebhookMaxAttempts = DefaultWebhookMaxAttempts
+	}
+
+	if cfg.WebhookTimeout == 0 {
+		cfg.WebhookTimeout = DefaultWebhookTimeout
+	}
+
+	if cfg.WebhookMaxAttempts < 0 || cfg.WebhookTimeout < 0 {
+		return errors.New("webhook max attempts and timeout must be positive")
+	}
+
 	//if cfg.MasterPassword == "" {
 	//	return fmt.Errorf("missing private key")
 	//}
@@ -183,7 +309,15 @@ func flagConfig() (*Config, error) {
 
 	flag.StringVar(&cfg.Base, "b", "", "Sets server URL base. Example: http(s)://address:port/your/base")
 
-	flag.StringVar(&cfg.TrustedCIDR, "t", "", "Sets server URL trusted CIDR")
+	flag.StringVar(&cfg.PublicURL, "public-url", "", "Sets the base of generated short URLs. Example: https://sho.rt/. Defaults to base if it is an absolute URL, otherwise to the request scheme and host.")
+
+	flag.StringVar(&cfg.TrustedProxies, "trusted-proxies", "", "Sets comma separated CIDRs of reverse proxies allowed to set Forwarded and X-Forwarded-* headers.")
+
+	flag.StringVar(&cfg.Domains, "domains", "", "Sets comma separated short domains links may be created on, the first one is the default. Example: sho.rt,brand.ly")
+
+	flag.StringVar(&cfg.TrustedCIDR, "t", "", "Sets comma separated CIDRs of clients trusted with internal endpoints")
+
+	flag.StringVar(&cfg.AdminUsers, "admin-users", "", "Sets comma separated user IDs whose access tokens have the admin role.")
 
 	flag.StringVar(&cfg.Filepath, "r", "./storage.json", "Sets server storage file path.")
 
@@ -199,6 +333,36 @@ func flagConfig() (*Config, error) {
 
 	flag.StringVar(&cfg.GRPCPort, "grpc-port", "", "Sets GRPC server port (e.g.: :8081). If unset, GRPC server is off.")
 
+	flag.BoolVar(&cfg.GRPCReflection, "grpc-reflection", false, "Registers GRPC server reflection for tools like grpcurl.")
+
+	flag.StringVar(&cfg.GRPCClientCA, "grpc-client-ca", "", "Sets path of a PEM bundle of CAs for GRPC client certificates. Turns on mutual TLS, requires HTTPS.")
+
+	flag.StringVar(&cfg.GRPCTrustedClients, "grpc-trusted-clients", "", "Sets comma separated names of trusted GRPC client certificates. If empty, every verified client certificate is trusted.")
+
+	flag.DurationVar(&cfg.GRPCDrainDelay, "grpc-drain-delay", 0, "Sets how long the GRPC server reports NOT_SERVING on shutdown before it stops taking calls.")
+
+	flag.StringVar(&cfg.DenyList, "deny-list", "", "Sets path of a file with denied destination domains, one per line.")
+
+	flag.StringVar(&cfg.AllowList, "allow-list", "", "Sets path of a file with allowed destination domains. If set, all other domains are rejected.")
+
+	flag.BoolVar(&cfg.SortQuery, "sort-query", false, "Sorts query parameters when looking for duplicate URLs.")
+
+	flag.BoolVar(&cfg.StripTracking, "strip-tracking", false, "Ignores tracking query parameters (utm_*, gclid, fbclid...) when looking for duplicate URLs.")
+
+	flag.StringVar(&cfg.DedupeScope, "dedupe-scope", "", "Sets whose existing link is returned for an already shortened URL: global (default), per-user or none.")
+
+	flag.IntVar(&cfg.StreamMaxItems, "stream-max-items", 0, "Sets the maximum number of items in one streaming batch request. Default is 100000.")
+
+	flag.IntVar(&cfg.ReportThreshold, "report-threshold", 0, "Sets the number of distinct reporters that disable a link until a moderator reviews it. Default is 5.")
+
+	flag.DurationVar(&cfg.ReportWindow, "report-window", 0, "Sets the period abuse reports are counted over. Default is 24h.")
+
+	flag.IntVar(&cfg.WebhookMaxAttempts, "webhook-max-attempts", 0, "Sets the number of attempts to deliver a webhook before it is dead-lettered. Default is 8.")
+
+	flag.DurationVar(&cfg.WebhookTimeout, "webhook-timeout", 0, "Sets the time a webhook receiver has to answer. Default is 10s.")
+
+	flag.BoolVar(&cfg.WebhookAllowPrivate, "webhook-allow-private", false, "Allows webhooks to loopback and private addresses.")
+
 	flag.BoolVar(&cfg.Debug, "debug", false, "Sets server debug mode.")
 
 	flag.Parse()
@@ -222,6 +386,43 @@ func fileConfig(name string) (*Config, error) {
 	return &cfg, nil
 }
 
+// UnmarshalJSON reads durations of a config file as strings like "24h", the way env and flags are parsed.
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type plain Config
+	aux := struct {
+		*plain
+		ReportWindow   string `json:"report_window"`
+		WebhookTimeout string `json:"webhook_timeout"`
+		GRPCDrainDelay string `json:"grpc_drain_delay"`
+	}{plain: (*plain)(c)}
+
+	err := json.Unmarshal(b, &aux)
+	if err != nil {
+		return err
+	}
+
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{name: "report_window", value: aux.ReportWindow, dst: &c.ReportWindow},
+		{name: "webhook_timeout", value: aux.WebhookTimeout, dst: &c.WebhookTimeout},
+		{name: "grpc_drain_delay", value: aux.GRPCDrainDelay, dst: &c.GRPCDrainDelay},
+	} {
+		if d.value == "" {
+			continue
+		}
+
+		*d.dst, err = time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrMalformedDuration, d.name)
+		}
+	}
+
+	return nil
+}
+
 func mergeConfig(dst, src *Config) {
 	if src == nil {
 		return
@@ -233,9 +434,21 @@ func mergeConfig(dst, src *Config) {
 	if src.Base != "" {
 		dst.Base = src.Base
 	}
+	if src.PublicURL != "" {
+		dst.PublicURL = src.PublicURL
+	}
+	if src.TrustedProxies != "" {
+		dst.TrustedProxies = src.TrustedProxies
+	}
+	if src.Domains != "" {
+		dst.Domains = src.Domains
+	}
 	if src.TrustedCIDR != "" {
 		dst.TrustedCIDR = src.TrustedCIDR
 	}
+	if src.AdminUsers != "" {
+		dst.AdminUsers = src.AdminUsers
+	}
 	if src.Config != "" {
 		dst.Config = src.Config
 	}
@@ -257,6 +470,39 @@ func mergeConfig(dst, src *Config) {
 	if src.GRPCPort != "" {
 		dst.GRPCPort = src.GRPCPort
 	}
+	if src.GRPCClientCA != "" {
+		dst.GRPCClientCA = src.GRPCClientCA
+	}
+	if src.GRPCTrustedClients != "" {
+		dst.GRPCTrustedClients = src.GRPCTrustedClients
+	}
+	if src.GRPCDrainDelay != 0 {
+		dst.GRPCDrainDelay = src.GRPCDrainDelay
+	}
+	if src.DenyList != "" {
+		dst.DenyList = src.DenyList
+	}
+	if src.AllowList != "" {
+		dst.AllowList = src.AllowList
+	}
+	if src.DedupeScope != "" {
+		dst.DedupeScope = src.DedupeScope
+	}
+	if src.StreamMaxItems != 0 {
+		dst.StreamMaxItems = src.StreamMaxItems
+	}
+	if src.ReportThreshold != 0 {
+		dst.ReportThreshold = src.ReportThreshold
+	}
+	if src.ReportWindow != 0 {
+		dst.ReportWindow = src.ReportWindow
+	}
+	if src.WebhookMaxAttempts != 0 {
+		dst.WebhookMaxAttempts = src.WebhookMaxAttempts
+	}
+	if src.WebhookTimeout != 0 {
+		dst.WebhookTimeout = src.WebhookTimeout
+	}
 	// Booleans: only overwrite if true to preserve priority
 	if src.HTTPS {
 		dst.HTTPS = true
@@ -264,6 +510,18 @@ func mergeConfig(dst, src *Config) {
 	if src.Debug {
 		dst.Debug = true
 	}
+	if src.SortQuery {
+		dst.SortQuery = true
+	}
+	if src.WebhookAllowPrivate {
+		dst.WebhookAllowPrivate = true
+	}
+	if src.StripTracking {
+		dst.StripTracking = true
+	}
+	if src.GRPCReflection {
+		dst.GRPCReflection = true
+	}
 }
 
 func Provide() fx.Option {
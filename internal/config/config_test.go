@@ -0,0 +1,52 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *Config
+		wantErr error
+	}{
+		{
+			name:    "Durations",
+			content: `{"server_address":":8080","report_window":"24h","webhook_timeout":"10s","grpc_drain_delay":"5s"}`,
+			want:    &Config{Addr: ":8080", ReportWindow: 24 * time.Hour, WebhookTimeout: 10 * time.Second, GRPCDrainDelay: 5 * time.Second},
+		},
+		{
+			name:    "Durations omitted",
+			content: `{"server_address":":8080"}`,
+			want:    &Config{Addr: ":8080"},
+		},
+		{
+			name:    "Malformed duration",
+			content: `{"report_window":"a day"}`,
+			wantErr: ErrMalformedDuration,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), "config.json")
+			require.NoError(t, os.WriteFile(name, []byte(tt.content), 0o600))
+
+			cfg, err := fileConfig(name)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, cfg)
+		})
+	}
+}
@@ -1,6 +1,70 @@
 package context
 
+import (
+	"context"
+	"net/netip"
+)
+
 type ContextKey string
 
-// UserIDContextKey used as key for storing and fetching value from context.
-const UserIDContextKey = ContextKey("userID")
+const (
+	// PrincipalContextKey used as key for storing and fetching the caller from context.
+	PrincipalContextKey = ContextKey("principal")
+	// ClientIPContextKey used as key for storing and fetching the client address from context.
+	ClientIPContextKey = ContextKey("client_ip")
+	// RequestContextKey used as key for storing and fetching the request ID and transport from context.
+	RequestContextKey = ContextKey("request")
+)
+
+// Transports a request may come over.
+const (
+	TransportHTTP = "http"
+	TransportGRPC = "grpc"
+)
+
+// Request identifies a request across logs and the audit log.
+type Request struct {
+	ID        string
+	Transport string // TransportHTTP or TransportGRPC, calls from the REST gateway are HTTP
+}
+
+// Principal is the authenticated caller of a request. The HTTP middleware and the gRPC interceptors
+// set the same type, so the service layer doesn't depend on the transport.
+type Principal struct {
+	UserID string
+	// AccessToken is the token the client should keep using, it is a new one if the request had none.
+	AccessToken string
+}
+
+// WithPrincipal returns a copy of ctx carrying p.
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, PrincipalContextKey, p)
+}
+
+// PrincipalFrom returns the caller stored in ctx. ok is false for anonymous requests.
+func PrincipalFrom(ctx context.Context) (p Principal, ok bool) {
+	p, ok = ctx.Value(PrincipalContextKey).(Principal)
+	return p, ok
+}
+
+// WithClientIP returns a copy of ctx carrying the resolved address of the client.
+func WithClientIP(ctx context.Context, addr netip.Addr) context.Context {
+	return context.WithValue(ctx, ClientIPContextKey, addr)
+}
+
+// ClientIPFrom returns the client address stored in ctx. It is invalid if none was stored.
+func ClientIPFrom(ctx context.Context) netip.Addr {
+	addr, _ := ctx.Value(ClientIPContextKey).(netip.Addr)
+	return addr
+}
+
+// WithRequest returns a copy of ctx carrying req.
+func WithRequest(ctx context.Context, req Request) context.Context {
+	return context.WithValue(ctx, RequestContextKey, req)
+}
+
+// RequestFrom returns the request stored in ctx, the zero Request if none was stored.
+func RequestFrom(ctx context.Context) Request {
+	req, _ := ctx.Value(RequestContextKey).(Request)
+	return req
+}
@@ -0,0 +1,117 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/clicks"
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"go.uber.org/zap"
+)
+
+const (
+	// heartbeatInterval is how often an idle activity stream sends a comment, so that proxies
+	// and the client don't take it for a dead connection.
+	heartbeatInterval = 15 * time.Second
+	// retryDelay is how long EventSource waits before reconnecting, in milliseconds.
+	retryDelay = 3000
+)
+
+const eventStreamContentType = "text/event-stream"
+
+// StreamActivity godoc
+//
+//	@Summary		Streams events about the user's links as they happen
+//	@Description	Server-sent events named link.created, link.clicked and link.deleted, their data is the webhook
+//	@Description	delivery body. A comment is sent every 15 seconds while idle. A client reconnecting with the
+//	@Description	Last-Event-ID header, or the lastEventId query parameter, first gets the events it missed
+//	@Description	among the latest kept. Slow clients are disconnected and catch up the same way.
+//	@Tags			activity
+//	@Produce		text/event-stream
+//	@Param			Cookie			header		string				true	"Cookie with access token"
+//	@Param			Last-Event-ID	header		string				false	"ID of the last event received"
+//	@Param			lastEventId		query		string				false	"ID of the last event received"
+//	@Success		200				{object}	dto.WebhookEvent	"Stream of events"
+//	@Failure		503				{object}	dto.ResponseWrapper	"Link events are not available"
+//	@Router			/api/user/events [get]
+func (c Controller) StreamActivity(w http.ResponseWriter, r *http.Request) {
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("lastEventId")
+	}
+
+	// A malformed ID can't be resumed from, the stream starts from new events.
+	lastID, _ := strconv.ParseUint(lastEventID, 10, 64)
+
+	missed, events, unsubscribe, err := c.service.SubscribeClicks(p.UserID, lastID)
+	if err != nil {
+		if errors.Is(err, errs.ErrClicksUnavailable) {
+			helpers.WriteJSON(w, http.StatusServiceUnavailable, dto.ResponseWrapper{"error": err.Error()})
+			return
+		}
+
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	defer unsubscribe()
+
+	rc := http.NewResponseController(w)
+	c.extendDeadlines(rc)
+
+	w.Header().Set("Content-Type", eventStreamContentType)
+	w.Header().Set("Cache-Control", "no-cache")
+	// Tells nginx not to buffer the stream.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	_, err = fmt.Fprintf(w, "retry: %d\n\n", retryDelay)
+	for _, e := range missed {
+		if err != nil {
+			break
+		}
+
+		err = writeEvent(w, e)
+	}
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for err == nil {
+		err = rc.Flush()
+		if err != nil {
+			break
+		}
+		c.extendDeadlines(rc)
+
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-events:
+			if !ok {
+				c.logger.Debug("Activity stream dropped for falling behind", zap.String("user_id", p.UserID))
+				return
+			}
+
+			err = writeEvent(w, e)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(w, ": heartbeat\n\n")
+		}
+	}
+
+	c.logger.Debug("Activity stream aborted", zap.Error(err))
+}
+
+// writeEvent writes e in the event stream format. Data is JSON without newlines, so it fits one data line.
+func writeEvent(w http.ResponseWriter, e clicks.Event) error {
+	_, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, e.Data)
+
+	return err
+}
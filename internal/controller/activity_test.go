@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/clicks"
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestApplication_StreamActivity(t *testing.T) {
+	tests := []struct {
+		name        string
+		unavailable bool
+		seeded      int // events published before connecting
+		resume      bool
+		statusCode  int
+	}{
+		{
+			name:       "New events",
+			seeded:     2,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Resumed",
+			seeded:     3,
+			resume:     true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:        "Unavailable",
+			unavailable: true,
+			statusCode:  http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hub := clicks.New(zap.NewNop())
+
+			p := service.Params{Clicks: hub}
+			if tt.unavailable {
+				p.Clicks = nil
+			}
+			c := newTestController(p)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				c.StreamActivity(w, r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "user1"})))
+			}))
+			defer srv.Close()
+
+			_, events, cancel := hub.Subscribe("user1", 0)
+			var seeded []clicks.Event
+			for i := range tt.seeded {
+				hub.Publish(clicks.Event{Type: "link.clicked", UserID: "user1", Data: []byte(fmt.Sprintf(`{"n":%d}`, i))})
+				seeded = append(seeded, <-events)
+			}
+			cancel()
+
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			require.NoError(t, err)
+			if tt.resume {
+				// Got the first event before the connection dropped.
+				req.Header.Set("Last-Event-ID", fmt.Sprint(seeded[0].ID))
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+
+			require.Equal(t, tt.statusCode, resp.StatusCode)
+			if tt.statusCode != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+			var want []string
+			if tt.resume {
+				for _, e := range seeded[1:] {
+					want = append(want, fmt.Sprintf("id: %d\nevent: %s\ndata: %s", e.ID, e.Type, e.Data))
+				}
+			}
+
+			body := bufio.NewReader(resp.Body)
+			next := func() string {
+				var lines []string
+				for {
+					line, err := body.ReadString('\n')
+					require.NoError(t, err)
+
+					line = strings.TrimSuffix(line, "\n")
+					if line == "" {
+						return strings.Join(lines, "\n")
+					}
+					lines = append(lines, line)
+				}
+			}
+
+			assert.Equal(t, "retry: 3000", next())
+			for _, w := range want {
+				assert.Equal(t, w, next())
+			}
+
+			// Published once the stream is flushed, it comes live.
+			hub.Publish(clicks.Event{Type: "link.deleted", UserID: "user1", Data: []byte(`{"event":"link.deleted"}`)})
+			hub.Publish(clicks.Event{Type: "link.deleted", UserID: "user2", Data: []byte(`{"event":"link.deleted"}`)})
+
+			live := next()
+			assert.True(t, strings.HasSuffix(live, "\nevent: link.deleted\ndata: {\"event\":\"link.deleted\"}"), live)
+		})
+	}
+}
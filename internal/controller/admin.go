@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/go-chi/chi/v5"
+)
+
+// FindLinks godoc
+//
+//	@Summary		Finds links of every user
+//	@Description	For moderators: admin users and clients from the trusted subnet. At least one of id, url, host
+//	@Description	and user must be given, all given ones must match.
+//	@Tags			admin
+//	@Produce		application/json
+//	@Param			Cookie	header		string				false	"Cookie with access token of an admin"
+//	@Param			id		query		string				false	"Short URL ID"
+//	@Param			domain	query		string				false	"Short domain of the link, any if omitted"
+//	@Param			url		query		string				false	"Destination, matched as given and in canonical form"
+//	@Param			host	query		string				false	"Host of the destination, subdomains included"
+//	@Param			user	query		string				false	"Creator of the link"
+//	@Success		200		{object}	[]dto.AdminLink		"Links, newest first"
+//	@Failure		400		{object}	dto.ResponseWrapper	"No or malformed query"
+//	@Failure		401		{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403		{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/links [get]
+func (c Controller) FindLinks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	query := r.URL.Query()
+	q := models.LinkQuery{
+		ShortURL:    query.Get("id"),
+		Destination: query.Get("url"),
+		Host:        query.Get("host"),
+		UserID:      query.Get("user"),
+	}
+
+	if domain := query.Get("domain"); domain != "" {
+		q.Domains = []string{domain}
+	}
+
+	links, err := c.service.FindLinks(ctx, q)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, c.adminLinks(c.urls.Origin(r), links))
+}
+
+// GetUserLinks godoc
+//
+//	@Summary		Returns every link created by a user
+//	@Description	For moderators: admin users and clients from the trusted subnet.
+//	@Tags			admin
+//	@Produce		application/json
+//	@Param			Cookie	header		string				false	"Cookie with access token of an admin"
+//	@Param			userID	path		string				true	"User ID"
+//	@Success		200		{object}	[]dto.AdminLink		"Links, newest first"
+//	@Failure		400		{object}	dto.ResponseWrapper	"Malformed user ID"
+//	@Failure		401		{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403		{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/users/{userID}/links [get]
+func (c Controller) GetUserLinks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	links, err := c.service.UserLinks(ctx, chi.URLParam(r, "userID"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, c.adminLinks(c.urls.Origin(r), links))
+}
+
+// DisableLink godoc
+//
+//	@Summary		Disables a link
+//	@Description	For moderators: admin users and clients from the trusted subnet. Visitors of the link get
+//	@Description	the takedown status and see the reason instead of being redirected.
+//	@Tags			admin
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			Cookie		header	string				false	"Cookie with access token of an admin"
+//	@Param			id			path	string				true	"Short URL ID"
+//	@Param			domain		query	string				false	"Short domain of the link, the default one if omitted"
+//	@Param			Takedown	body	dto.TakedownRequest	true	"Status and reason"
+//	@Success		204
+//	@Failure		400	{object}	dto.ResponseWrapper	"Malformed takedown or unknown domain"
+//	@Failure		401	{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403	{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		404	{object}	dto.ResponseWrapper	"Link not found"
+//	@Failure		500	{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/links/{id}/disable [post]
+func (c Controller) DisableLink(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	var req dto.TakedownRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	err = c.service.DisableLink(ctx, r.URL.Query().Get("domain"), chi.URLParam(r, "id"),
+		models.Takedown{Status: req.Status, Reason: req.Reason})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EnableLink godoc
+//
+//	@Summary		Enables a disabled link
+//	@Description	For moderators: admin users and clients from the trusted subnet.
+//	@Tags			admin
+//	@Produce		application/json
+//	@Param			Cookie	header	string	false	"Cookie with access token of an admin"
+//	@Param			id		path	string	true	"Short URL ID"
+//	@Param			domain	query	string	false	"Short domain of the link, the default one if omitted"
+//	@Success		204
+//	@Failure		400	{object}	dto.ResponseWrapper	"Unknown domain"
+//	@Failure		401	{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403	{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		404	{object}	dto.ResponseWrapper	"Link not found"
+//	@Failure		500	{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/links/{id}/disable [delete]
+func (c Controller) EnableLink(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	err := c.service.EnableLink(ctx, r.URL.Query().Get("domain"), chi.URLParam(r, "id"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DisableHost godoc
+//
+//	@Summary		Disables every link pointing at a host
+//	@Description	For moderators: admin users and clients from the trusted subnet. Links to subdomains
+//	@Description	of the host are disabled too, on every short domain.
+//	@Tags			admin
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			Cookie		header		string					false	"Cookie with access token of an admin"
+//	@Param			host		path		string					true	"Host, like example.com"
+//	@Param			Takedown	body		dto.TakedownRequest		true	"Status and reason"
+//	@Success		200			{object}	dto.TakedownResponse	"Number of disabled links"
+//	@Failure		400			{object}	dto.ResponseWrapper		"Malformed takedown or host"
+//	@Failure		401			{object}	dto.ResponseWrapper		"No access token"
+//	@Failure		403			{object}	dto.ResponseWrapper		"User is not an admin"
+//	@Failure		500			{object}	dto.ResponseWrapper		"Internal Server Error"
+//	@Router			/api/admin/hosts/{host}/disable [post]
+func (c Controller) DisableHost(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	var req dto.TakedownRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	n, err := c.service.DisableHost(ctx, chi.URLParam(r, "host"), models.Takedown{Status: req.Status, Reason: req.Reason})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, dto.TakedownResponse{Disabled: n})
+}
+
+// adminLinks describes links for moderators.
+func (c Controller) adminLinks(o proxy.Origin, links []models.Link) []dto.AdminLink {
+	result := make([]dto.AdminLink, 0, len(links))
+	for _, link := range links {
+		result = append(result, dto.AdminLink{
+			ShortURL:       c.service.ShortURL(o, link),
+			Domain:         c.service.DomainName(link),
+			OriginalURL:    link.OriginalURL,
+			UserID:         link.UserID,
+			WorkspaceID:    link.WorkspaceID,
+			CreatedAt:      link.CreatedAt,
+			Protected:      link.Protected(),
+			MaxClicks:      link.MaxClicks,
+			ClicksLeft:     link.ClicksLeft,
+			DisabledStatus: link.Takedown.Status,
+			DisabledReason: link.Takedown.Reason,
+		})
+	}
+
+	return result
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errs.ErrEmptyLinkQuery), errors.Is(err, errs.ErrInvalidUserID),
+		errors.Is(err, errs.ErrUnknownDomain), errors.Is(err, errs.ErrMalformedHost),
+		errors.Is(err, errs.ErrInvalidTakedownStatus), errors.Is(err, errs.ErrInvalidTimeRange),
+		errors.Is(err, errs.ErrInvalidLimit):
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": err.Error()})
+	case errors.Is(err, errs.ErrURLNotFound):
+		helpers.WriteJSON(w, http.StatusNotFound, dto.ResponseWrapper{"error": http.StatusText(http.StatusNotFound)})
+	default:
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+	}
+}
@@ -0,0 +1,213 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/service"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApplication_FindLinks(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		mockSetup  func(m *mockstorage.MockRepo)
+		statusCode int
+		wantLinks  int
+	}{
+		{
+			name:       "Empty query",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "Malformed user ID",
+			query:      "?user=admin",
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:  "By host",
+			query: "?host=Example.COM.",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().FindLinks(gomock.Any(), models.LinkQuery{Domains: []string{}, Host: "example.com"}).
+					Return([]models.Link{
+						{ShortURL: "qxDvSD", OriginalURL: "https://example.com/a"},
+						{ShortURL: "qxDvSE", OriginalURL: "https://www.example.com/b", Takedown: models.Takedown{Status: 410}},
+					}, nil)
+			},
+			statusCode: http.StatusOK,
+			wantLinks:  2,
+		},
+		{
+			name:  "By destination in canonical form",
+			query: "?url=HTTPS://Example.com/a",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().FindLinks(gomock.Any(), models.LinkQuery{Domains: []string{}, Destination: "https://example.com/a"}).
+					Return(nil, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodGet, "/api/admin/links"+tt.query, nil)
+			w := httptest.NewRecorder()
+			app.FindLinks(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+
+			if tt.statusCode == http.StatusOK {
+				var links []dto.AdminLink
+				require.NoError(t, json.NewDecoder(result.Body).Decode(&links))
+				assert.Len(t, links, tt.wantLinks)
+			}
+		})
+	}
+}
+
+func TestApplication_DisableLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		mockSetup  func(m *mockstorage.MockRepo)
+		statusCode int
+	}{
+		{
+			name: "Default status",
+			body: `{"reason":" Phishing "}`,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{ShortURL: "qxDvSD"}, nil)
+				m.EXPECT().DisableLinks(gomock.Any(), models.LinkQuery{ShortURL: "qxDvSD", Domains: []string{""}},
+					models.Takedown{Status: http.StatusGone, Reason: "Phishing"}).Return(1, nil)
+			},
+			statusCode: http.StatusNoContent,
+		},
+		{
+			name:       "Wrong status",
+			body:       `{"status":404,"reason":"Phishing"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name: "Not found",
+			body: `{"status":451,"reason":"Court order"}`,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{}, errs.ErrURLNotFound)
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodPost, "/api/admin/links/qxDvSD/disable", strings.NewReader(tt.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "qxDvSD")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			app.DisableLink(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestApplication_DisableHost(t *testing.T) {
+	tests := []struct {
+		name       string
+		host       string
+		mockSetup  func(m *mockstorage.MockRepo)
+		statusCode int
+		want       string
+	}{
+		{
+			name: "Host",
+			host: "Evil.example",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().DisableLinks(gomock.Any(), models.LinkQuery{Host: "evil.example"},
+					models.Takedown{Status: http.StatusGone, Reason: "Malware"}).Return(3, nil)
+			},
+			statusCode: http.StatusOK,
+			want:       `{"disabled":3}`,
+		},
+		{
+			name:       "Top-level domain",
+			host:       "com",
+			statusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodPost, "/api/admin/hosts/"+tt.host+"/disable", strings.NewReader(`{"reason":"Malware"}`))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("host", tt.host)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			app.DisableHost(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+
+			if tt.want != "" {
+				body, err := io.ReadAll(result.Body)
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.want, string(body))
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/proxy"
+)
+
+// GetAuditLog godoc
+//
+//	@Summary		Returns the audit log
+//	@Description	For moderators: admin users and clients from the trusted subnet. Every create, delete,
+//	@Description	update and moderation is recorded with its actor, address, transport and request ID.
+//	@Tags			admin
+//	@Produce		application/json
+//	@Param			Cookie	header		string				false	"Cookie with access token of an admin"
+//	@Param			actor	query		string				false	"User who made the changes"
+//	@Param			id		query		string				false	"Short URL ID of the changed link"
+//	@Param			domain	query		string				false	"Short domain of the link, any if omitted"
+//	@Param			action	query		string				false	"Action, like link.create or link.disable"
+//	@Param			from	query		string				false	"Start of the time range, RFC 3339, inclusive"
+//	@Param			to		query		string				false	"End of the time range, RFC 3339, exclusive"
+//	@Param			limit	query		int					false	"Maximum number of events, 100 if omitted, at most 1000"
+//	@Success		200		{object}	[]dto.AuditEvent	"Events, newest first"
+//	@Failure		400		{object}	dto.ResponseWrapper	"Malformed query"
+//	@Failure		401		{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403		{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/audit [get]
+func (c Controller) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	q, err := auditQuery(r)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": err.Error()})
+		return
+	}
+
+	events, err := c.service.AuditLog(ctx, q)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, c.auditEvents(c.urls.Origin(r), events))
+}
+
+// auditQuery parses the query of GetAuditLog.
+func auditQuery(r *http.Request) (models.AuditQuery, error) {
+	query := r.URL.Query()
+	q := models.AuditQuery{
+		ActorID:  query.Get("actor"),
+		ShortURL: query.Get("id"),
+		Action:   query.Get("action"),
+	}
+
+	if domain := query.Get("domain"); domain != "" {
+		q.Domains = []string{domain}
+	}
+
+	var err error
+	if from := query.Get("from"); from != "" {
+		q.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			return q, errs.ErrMalformedTime
+		}
+	}
+
+	if to := query.Get("to"); to != "" {
+		q.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			return q, errs.ErrMalformedTime
+		}
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		q.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return q, errs.ErrInvalidLimit
+		}
+	}
+
+	return q, nil
+}
+
+// auditEvents describes audit events for moderators.
+func (c Controller) auditEvents(o proxy.Origin, events []models.AuditEvent) []dto.AuditEvent {
+	result := make([]dto.AuditEvent, 0, len(events))
+	for _, e := range events {
+		event := dto.AuditEvent{
+			ID:          e.ID,
+			Time:        e.Time,
+			Action:      e.Action,
+			ActorID:     e.ActorID,
+			ActorIP:     e.ActorIP,
+			Transport:   e.Transport,
+			RequestID:   e.RequestID,
+			WorkspaceID: e.WorkspaceID,
+			Before:      e.Before,
+			After:       e.After,
+		}
+
+		if e.ShortURL != "" {
+			link := models.Link{ShortURL: e.ShortURL, Domain: e.Domain}
+			event.ShortURL = c.service.ShortURL(o, link)
+			event.Domain = c.service.DomainName(link)
+		}
+
+		result = append(result, event)
+	}
+
+	return result
+}
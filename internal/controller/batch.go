@@ -0,0 +1,18 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MukizuL/shortener/internal/dto"
+)
+
+// batchStatusCode is 201 when every item was created and 207 when some were existing or invalid.
+func batchStatusCode(results []dto.BatchResponse) int {
+	for _, result := range results {
+		if result.Status != dto.BatchStatusCreated {
+			return http.StatusMultiStatus
+		}
+	}
+
+	return http.StatusCreated
+}
@@ -1,25 +1,33 @@
 package controller
 
 import (
-	"github.com/MukizuL/shortener/internal/storage"
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/publicurl"
+	"github.com/MukizuL/shortener/internal/service"
 	pb "github.com/MukizuL/shortener/proto"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
 
+// Controller adapts HTTP and gRPC requests to the service.
 type Controller struct {
-	storage storage.Repo
-	logger  *zap.Logger
+	service *service.Service
+	urls    *publicurl.Builder
+	// streamMaxItems caps items in one streaming batch request, zero means no cap.
+	streamMaxItems int
+	logger         *zap.Logger
 	pb.UnimplementedShortenerServer
 }
 
-func newController(storage storage.Repo, logger *zap.Logger) *Controller {
+func newController(cfg *config.Config, service *service.Service, urls *publicurl.Builder, logger *zap.Logger) *Controller {
 	return &Controller{
-		storage: storage,
-		logger:  logger,
+		service:        service,
+		urls:           urls,
+		streamMaxItems: cfg.StreamMaxItems,
+		logger:         logger,
 	}
 }
 
 func Provide() fx.Option {
-	return fx.Provide(newController)
+	return fx.Provide(newController, newAdminServer)
 }
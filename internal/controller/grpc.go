@@ -7,10 +7,15 @@ import (
 	contextI "github.com/MukizuL/shortener/internal/context"
 	"github.com/MukizuL/shortener/internal/dto"
 	"github.com/MukizuL/shortener/internal/errs"
-	"github.com/MukizuL/shortener/internal/helpers"
-	"github.com/MukizuL/shortener/internal/interceptor"
+	"github.com/MukizuL/shortener/internal/gateway"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/MukizuL/shortener/internal/qr"
 	pb "github.com/MukizuL/shortener/proto"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
@@ -18,28 +23,33 @@ func (c Controller) CreateGRPC(
 	ctx context.Context,
 	in *pb.CreateShortURLRequest) (*pb.CreateShortURLResponse, error) {
 	var response pb.CreateShortURLResponse
-	url, err := helpers.CheckURL([]byte(in.OriginalUrl))
+
+	p, err := grpcPrincipal(ctx)
 	if err != nil {
-		return nil, status.Errorf(codes.InvalidArgument, "original url is not a url")
+		return nil, err
 	}
 
-	pair, ok := ctx.Value(contextI.UserIDContextKey).(interceptor.TokenPair)
-	if !ok {
-		return nil, status.Error(codes.FailedPrecondition, "user id not found in context")
+	req := dto.Request{
+		FullURL:      in.OriginalUrl,
+		WorkspaceID:  in.WorkspaceId,
+		Password:     in.Password,
+		MaxClicks:    int(in.MaxClicks),
+		RedirectType: int(in.RedirectType),
+		Domain:       in.Domain,
 	}
 
-	shortURL, err := c.storage.CreateShortURL(ctx, pair.UserID, "", url)
+	shortURL, err := c.service.CreateShortURL(ctx, p.UserID, grpcOrigin(ctx), req)
 	if err != nil {
 		if errors.Is(err, errs.ErrDuplicate) {
 			response.ShortUrl = shortURL
-			return &response, status.Error(codes.AlreadyExists, err.Error())
+			return &response, serviceStatus(err)
 		}
 
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceStatus(err)
 	}
 
 	response.ShortUrl = shortURL
-	response.AccessToken = pair.AccessToken
+	response.AccessToken = p.AccessToken
 
 	return &response, nil
 }
@@ -52,10 +62,6 @@ func (c Controller) CreateBatchGRPC(
 	var req []dto.BatchRequest
 
 	for _, v := range in.Batch {
-		_, err := helpers.CheckURL([]byte(v.OriginalUrl))
-		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "%s is not a url", v.OriginalUrl)
-		}
 		temp := dto.BatchRequest{
 			CorrelationID: v.CorrelationId,
 			OriginalURL:   v.OriginalUrl,
@@ -64,18 +70,16 @@ func (c Controller) CreateBatchGRPC(
 		req = append(req, temp)
 	}
 
-	pair, ok := ctx.Value(contextI.UserIDContextKey).(interceptor.TokenPair)
-	if !ok {
-		return nil, status.Error(codes.FailedPrecondition, "user id not found in context")
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	resp, err := c.storage.BatchCreateShortURL(ctx, pair.UserID, "", req)
-	if err != nil {
-		if errors.Is(err, errs.ErrDuplicate) {
-			return nil, status.Error(codes.AlreadyExists, "some urls already exist")
-		}
+	opts := models.URLOptions{WorkspaceID: in.WorkspaceId, Domain: in.Domain}
 
-		return nil, status.Error(codes.Internal, err.Error())
+	resp, err := c.service.CreateBatch(ctx, p.UserID, grpcOrigin(ctx), req, opts)
+	if err != nil {
+		return nil, serviceStatus(err)
 	}
 
 	var batch []*pb.BatchResponse
@@ -83,13 +87,15 @@ func (c Controller) CreateBatchGRPC(
 		temp := pb.BatchResponse{
 			CorrelationId: v.CorrelationID,
 			ShortUrl:      v.ShortURL,
+			Status:        v.Status,
+			Error:         v.Error,
 		}
 
 		batch = append(batch, &temp)
 	}
 
 	response.Batch = batch
-	response.AccessToken = pair.AccessToken
+	response.AccessToken = p.AccessToken
 
 	return &response, nil
 }
@@ -99,20 +105,29 @@ func (c Controller) GetOriginalURLGRPC(
 	in *pb.GetOriginalURLRequest) (*pb.GetOriginalURLResponse, error) {
 	var response pb.GetOriginalURLResponse
 
-	fullURL, err := c.storage.GetLongURL(ctx, in.ShortUrl)
+	link, err := c.service.Resolve(ctx, grpcOrigin(ctx).Host, in.ShortUrl)
 	if err != nil {
-		if errors.Is(err, errs.ErrURLNotFound) {
-			return nil, status.Error(codes.NotFound, err.Error())
+		if errors.Is(err, errs.ErrDisabled) && link.Takedown.Reason != "" {
+			return nil, status.Errorf(codes.FailedPrecondition, "%s: %s", err.Error(), link.Takedown.Reason)
 		}
 
-		if errors.Is(err, errs.ErrGone) {
-			return nil, status.Error(codes.NotFound, err.Error())
+		return nil, serviceStatus(err)
+	}
+
+	if link.Protected() {
+		err = c.service.Unlock(link, in.Password, contextI.ClientIPFrom(ctx))
+		if err != nil {
+			return nil, serviceStatus(err)
 		}
+	}
 
-		return nil, status.Error(codes.Internal, err.Error())
+	err = c.service.Visit(ctx, link, contextI.ClientIPFrom(ctx), "", grpcMetadata(ctx, "user-agent"))
+	if err != nil {
+		return nil, serviceStatus(err)
 	}
 
-	response.OriginalUrl = fullURL
+	response.OriginalUrl = link.OriginalURL
+	response.RedirectType = int32(link.StatusCode())
 
 	return &response, nil
 }
@@ -122,18 +137,14 @@ func (c Controller) GetUserURLsGRPC(
 	in *pb.GetUserURLRequest) (*pb.GetUserURLResponse, error) {
 	var response pb.GetUserURLResponse
 
-	var (
-		pair interceptor.TokenPair
-		ok   bool
-	)
-
-	if pair, ok = ctx.Value(contextI.UserIDContextKey).(interceptor.TokenPair); !ok {
-		return nil, status.Error(codes.FailedPrecondition, "user id not found in context")
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := c.storage.GetUserURLs(ctx, pair.UserID)
+	data, err := c.service.UserURLs(ctx, p.UserID, in.Domain)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceStatus(err)
 	}
 
 	var pairs []*pb.URLPair
@@ -141,13 +152,14 @@ func (c Controller) GetUserURLsGRPC(
 		temp := pb.URLPair{
 			OriginalUrl: v.OriginalURL,
 			ShortUrl:    v.ShortURL,
+			Domain:      v.Domain,
 		}
 
 		pairs = append(pairs, &temp)
 	}
 
 	response.Pairs = pairs
-	response.AccessToken = pair.AccessToken
+	response.AccessToken = p.AccessToken
 
 	return &response, nil
 }
@@ -157,21 +169,17 @@ func (c Controller) DeleteGRPC(
 	in *pb.DeleteShortURLRequest) (*pb.DeleteShortURLResponse, error) {
 	var response pb.DeleteShortURLResponse
 
-	pair, ok := ctx.Value(contextI.UserIDContextKey).(interceptor.TokenPair)
-	if !ok {
-		return nil, status.Error(codes.FailedPrecondition, "user id not found in context")
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	err := c.storage.DeleteURLs(ctx, pair.UserID, in.ShortUrls)
+	err = c.service.DeleteURLs(ctx, p.UserID, grpcOrigin(ctx).Host, in.ShortUrls)
 	if err != nil {
-		if errors.Is(err, errs.ErrUserMismatch) {
-			return nil, status.Error(codes.FailedPrecondition, err.Error())
-		}
-
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceStatus(err)
 	}
 
-	response.AccessToken = pair.AccessToken
+	response.AccessToken = p.AccessToken
 
 	return &response, nil
 }
@@ -181,9 +189,9 @@ func (c Controller) GetStatsGRPC(
 	in *pb.GetStatsRequest) (*pb.GetStatsResponse, error) {
 	var response pb.GetStatsResponse
 
-	urls, users, err := c.storage.GetStats(ctx)
+	urls, users, err := c.service.Stats(ctx)
 	if err != nil {
-		return nil, status.Error(codes.Internal, err.Error())
+		return nil, serviceStatus(err)
 	}
 
 	response.Urls = int32(urls)
@@ -191,3 +199,219 @@ func (c Controller) GetStatsGRPC(
 
 	return &response, nil
 }
+
+func (c Controller) CreateWorkspaceGRPC(
+	ctx context.Context,
+	in *pb.CreateWorkspaceRequest) (*pb.CreateWorkspaceResponse, error) {
+	var response pb.CreateWorkspaceResponse
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := c.service.CreateWorkspace(ctx, p.UserID, in.Name)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	response.WorkspaceId = ID
+	response.AccessToken = p.AccessToken
+
+	return &response, nil
+}
+
+func (c Controller) AddWorkspaceMemberGRPC(
+	ctx context.Context,
+	in *pb.AddWorkspaceMemberRequest) (*pb.AddWorkspaceMemberResponse, error) {
+	var response pb.AddWorkspaceMemberResponse
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.service.AddWorkspaceMember(ctx, p.UserID, in.WorkspaceId, in.UserId, models.Role(in.Role))
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	response.AccessToken = p.AccessToken
+
+	return &response, nil
+}
+
+func (c Controller) RemoveWorkspaceMemberGRPC(
+	ctx context.Context,
+	in *pb.RemoveWorkspaceMemberRequest) (*pb.RemoveWorkspaceMemberResponse, error) {
+	var response pb.RemoveWorkspaceMemberResponse
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.service.RemoveWorkspaceMember(ctx, p.UserID, in.WorkspaceId, in.UserId)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	response.AccessToken = p.AccessToken
+
+	return &response, nil
+}
+
+func (c Controller) GetWorkspacesGRPC(
+	ctx context.Context,
+	in *pb.GetWorkspacesRequest) (*pb.GetWorkspacesResponse, error) {
+	var response pb.GetWorkspacesResponse
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces, err := c.service.UserWorkspaces(ctx, p.UserID)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	for _, w := range workspaces {
+		response.Workspaces = append(response.Workspaces, &pb.Workspace{Id: w.ID, Name: w.Name, Role: w.Role})
+	}
+
+	response.AccessToken = p.AccessToken
+
+	return &response, nil
+}
+
+// GetQRCodeGRPC returns QR code of the full short URL as it is reachable through the authority the client used.
+func (c Controller) GetQRCodeGRPC(
+	ctx context.Context,
+	in *pb.GetQRCodeRequest) (*pb.GetQRCodeResponse, error) {
+	var response pb.GetQRCodeResponse
+
+	opts, err := qr.NewOptions(in.Format, int(in.Size), in.Level)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	o := grpcOrigin(ctx)
+
+	link, err := c.service.Link(ctx, o.Host, in.ShortUrl)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	shortURL := c.service.ShortURL(o, link)
+
+	response.Image, err = qr.Encode(shortURL, opts)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	response.ContentType = opts.ContentType()
+	response.Etag = opts.ETag(shortURL)
+
+	return &response, nil
+}
+
+func (c Controller) ReportLinkGRPC(
+	ctx context.Context,
+	in *pb.ReportLinkRequest) (*pb.ReportLinkResponse, error) {
+	err := c.service.ReportLink(ctx, grpcOrigin(ctx).Host, in.ShortUrl, contextI.ClientIPFrom(ctx), in.Reason, in.Contact)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	return &pb.ReportLinkResponse{}, nil
+}
+
+// grpcPrincipal returns the caller authenticated by the interceptor.
+func grpcPrincipal(ctx context.Context) (contextI.Principal, error) {
+	p, ok := contextI.PrincipalFrom(ctx)
+	if !ok {
+		return contextI.Principal{}, status.Error(codes.FailedPrecondition, "user id not found in context")
+	}
+
+	return p, nil
+}
+
+// grpcMetadata returns the first value of metadata key of the call.
+func grpcMetadata(ctx context.Context, key string) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+	}
+
+	return ""
+}
+
+// grpcWorkspaceID returns the workspace-id metadata, streaming RPCs take the workspace from it.
+func grpcWorkspaceID(ctx context.Context) string {
+	return grpcMetadata(ctx, "workspace-id")
+}
+
+// grpcDomain returns the domain metadata, streaming RPCs take the short domain from it.
+func grpcDomain(ctx context.Context) string {
+	return grpcMetadata(ctx, "domain")
+}
+
+// grpcOrigin returns the scheme and :authority of the call, the gRPC counterpart of proxy.Origin.
+// For calls from the REST gateway it is the origin of the original HTTP request.
+func grpcOrigin(ctx context.Context) proxy.Origin {
+	var o proxy.Origin
+	if pr, ok := peer.FromContext(ctx); ok {
+		_, o.HTTPS = pr.AuthInfo.(credentials.TLSInfo)
+	}
+
+	o.Host = grpcMetadata(ctx, ":authority")
+
+	// The gateway calls over an in-memory connection and reports the origin of the original request.
+	if gateway.IsGateway(ctx) {
+		o.HTTPS = grpcMetadata(ctx, "x-forwarded-proto") == "https"
+
+		if host := grpcMetadata(ctx, "x-forwarded-host"); host != "" {
+			o.Host = host
+		}
+	}
+
+	return o
+}
+
+// serviceStatus maps errors of the service to gRPC status codes.
+func serviceStatus(err error) error {
+	switch {
+	case errors.Is(err, errs.ErrNotURL), errors.Is(err, errs.ErrRejected),
+		errors.Is(err, errs.ErrInvalidMaxClicks), errors.Is(err, errs.ErrInvalidRedirectType),
+		errors.Is(err, errs.ErrUnknownRole), errors.Is(err, errs.ErrInvalidUserID),
+		errors.Is(err, errs.ErrEmptyWorkspaceName), errors.Is(err, errs.ErrUnknownDomain),
+		errors.Is(err, errs.ErrEmptyLinkQuery), errors.Is(err, errs.ErrMalformedHost),
+		errors.Is(err, errs.ErrInvalidTakedownStatus), errors.Is(err, errs.ErrEmptyReportReason),
+		errors.Is(err, errs.ErrReportTooLong), errors.Is(err, errs.ErrUnknownReportStatus),
+		errors.Is(err, errs.ErrInvalidTimeRange), errors.Is(err, errs.ErrInvalidLimit),
+		errors.Is(err, errs.ErrPasswordTooLong):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, errs.ErrForbidden), errors.Is(err, errs.ErrWrongPassword):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, errs.ErrURLNotFound), errors.Is(err, errs.ErrGone), errors.Is(err, errs.ErrWorkspaceNotFound),
+		errors.Is(err, errs.ErrReportNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errs.ErrDuplicate):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, errs.ErrUserMismatch), errors.Is(err, errs.ErrDisabled), errors.Is(err, errs.ErrReportResolved),
+		errors.Is(err, errs.ErrLastOwner):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, errs.ErrTooManyAttempts), errors.Is(err, errs.ErrTooManyItems):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, errs.ErrClicksUnavailable):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,185 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/models"
+	pb "github.com/MukizuL/shortener/proto"
+)
+
+// AdminServer serves the gRPC admin service. Callers are checked by the Admin interceptor.
+type AdminServer struct {
+	c *Controller
+	pb.UnimplementedAdminServer
+}
+
+func newAdminServer(c *Controller) *AdminServer {
+	return &AdminServer{c: c}
+}
+
+func (a AdminServer) FindLinks(ctx context.Context, in *pb.FindLinksRequest) (*pb.FindLinksResponse, error) {
+	q := models.LinkQuery{
+		ShortURL:    in.ShortUrl,
+		Destination: in.Destination,
+		Host:        in.Host,
+		UserID:      in.UserId,
+	}
+
+	if in.Domain != "" {
+		q.Domains = []string{in.Domain}
+	}
+
+	links, err := a.c.service.FindLinks(ctx, q)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	var response pb.FindLinksResponse
+	for _, link := range a.c.adminLinks(grpcOrigin(ctx), links) {
+		response.Links = append(response.Links, &pb.AdminLink{
+			ShortUrl:       link.ShortURL,
+			Domain:         link.Domain,
+			OriginalUrl:    link.OriginalURL,
+			UserId:         link.UserID,
+			WorkspaceId:    link.WorkspaceID,
+			CreatedAt:      link.CreatedAt.UnixMilli(),
+			Protected:      link.Protected,
+			MaxClicks:      int32(link.MaxClicks),
+			ClicksLeft:     int32(link.ClicksLeft),
+			DisabledStatus: int32(link.DisabledStatus),
+			DisabledReason: link.DisabledReason,
+		})
+	}
+
+	return &response, nil
+}
+
+func (a AdminServer) DisableLink(ctx context.Context, in *pb.DisableLinkRequest) (*pb.DisableLinkResponse, error) {
+	err := a.c.service.DisableLink(ctx, in.Domain, in.ShortUrl, models.Takedown{Status: int(in.Status), Reason: in.Reason})
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	return &pb.DisableLinkResponse{}, nil
+}
+
+func (a AdminServer) EnableLink(ctx context.Context, in *pb.EnableLinkRequest) (*pb.EnableLinkResponse, error) {
+	err := a.c.service.EnableLink(ctx, in.Domain, in.ShortUrl)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	return &pb.EnableLinkResponse{}, nil
+}
+
+func (a AdminServer) DisableHost(ctx context.Context, in *pb.DisableHostRequest) (*pb.DisableHostResponse, error) {
+	n, err := a.c.service.DisableHost(ctx, in.Host, models.Takedown{Status: int(in.Status), Reason: in.Reason})
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	return &pb.DisableHostResponse{Disabled: int32(n)}, nil
+}
+
+func (a AdminServer) ListReports(ctx context.Context, in *pb.ListReportsRequest) (*pb.ListReportsResponse, error) {
+	reports, err := a.c.service.Reports(ctx, models.ReportStatus(in.Status))
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	var response pb.ListReportsResponse
+	for _, report := range a.c.abuseReports(grpcOrigin(ctx), reports) {
+		response.Reports = append(response.Reports, &pb.AbuseReport{
+			Id:        report.ID,
+			ShortUrl:  report.ShortURL,
+			Domain:    report.Domain,
+			Reason:    report.Reason,
+			Contact:   report.Contact,
+			Reporter:  report.Reporter,
+			Status:    report.Status,
+			CreatedAt: report.CreatedAt.UnixMilli(),
+		})
+	}
+
+	return &response, nil
+}
+
+func (a AdminServer) ApproveReport(ctx context.Context, in *pb.ApproveReportRequest) (*pb.ApproveReportResponse, error) {
+	err := a.c.service.ApproveReport(ctx, in.ReportId, models.Takedown{Status: int(in.Status), Reason: in.Reason})
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	return &pb.ApproveReportResponse{}, nil
+}
+
+func (a AdminServer) DismissReport(ctx context.Context, in *pb.DismissReportRequest) (*pb.DismissReportResponse, error) {
+	err := a.c.service.DismissReport(ctx, in.ReportId)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	return &pb.DismissReportResponse{}, nil
+}
+
+func (a AdminServer) QueryAudit(ctx context.Context, in *pb.QueryAuditRequest) (*pb.QueryAuditResponse, error) {
+	q := models.AuditQuery{
+		ActorID:  in.ActorId,
+		ShortURL: in.ShortUrl,
+		Action:   in.Action,
+		Limit:    int(in.Limit),
+	}
+
+	if in.Domain != "" {
+		q.Domains = []string{in.Domain}
+	}
+
+	if in.From != 0 {
+		q.From = time.UnixMilli(in.From)
+	}
+
+	if in.To != 0 {
+		q.To = time.UnixMilli(in.To)
+	}
+
+	events, err := a.c.service.AuditLog(ctx, q)
+	if err != nil {
+		return nil, serviceStatus(err)
+	}
+
+	var response pb.QueryAuditResponse
+	for _, event := range a.c.auditEvents(grpcOrigin(ctx), events) {
+		response.Events = append(response.Events, &pb.AuditEvent{
+			Id:          event.ID,
+			Time:        event.Time.UnixMilli(),
+			Action:      event.Action,
+			ActorId:     event.ActorID,
+			ActorIp:     event.ActorIP,
+			Transport:   event.Transport,
+			RequestId:   event.RequestID,
+			ShortUrl:    event.ShortURL,
+			Domain:      event.Domain,
+			WorkspaceId: event.WorkspaceID,
+			Before:      auditValues(event.Before),
+			After:       auditValues(event.After),
+		})
+	}
+
+	return &response, nil
+}
+
+// auditValues encodes the values of an audit event as a JSON object, empty if there are none.
+func auditValues(values map[string]any) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"context"
+	"net/netip"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/models"
+	pb "github.com/MukizuL/shortener/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ListUserURLs streams URLs of the caller as they are read from storage.
+func (c Controller) ListUserURLs(in *pb.GetUserURLRequest, stream grpc.ServerStreamingServer[pb.URLPair]) error {
+	ctx := stream.Context()
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.service.WalkUserURLs(ctx, p.UserID, in.Domain, func(v dto.URLPair) error {
+		return stream.Send(&pb.URLPair{
+			OriginalUrl: v.OriginalURL,
+			ShortUrl:    v.ShortURL,
+			Domain:      v.Domain,
+		})
+	})
+	if err != nil {
+		return serviceStatus(err)
+	}
+
+	return nil
+}
+
+// BulkCreate creates short URLs from a stream with the same semantics as CreateStreamGRPC, but answers once.
+func (c Controller) BulkCreate(stream grpc.ClientStreamingServer[pb.BatchRequest, pb.CreateBatchShortURLResponse]) error {
+	ctx := stream.Context()
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := models.URLOptions{WorkspaceID: grpcWorkspaceID(ctx), Domain: grpcDomain(ctx)}
+
+	err = c.service.CheckDomain(opts.Domain)
+	if err != nil {
+		return serviceStatus(err)
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	err = c.service.CheckWorkspaceEditor(checkCtx, opts.WorkspaceID, p.UserID)
+	cancel()
+	if err != nil {
+		return serviceStatus(err)
+	}
+
+	recv := func() (dto.BatchRequest, error) {
+		in, err := stream.Recv()
+		if err != nil {
+			return dto.BatchRequest{}, err
+		}
+
+		return dto.BatchRequest{CorrelationID: in.CorrelationId, OriginalURL: in.OriginalUrl}, nil
+	}
+
+	response := pb.CreateBatchShortURLResponse{AccessToken: p.AccessToken}
+
+	send := func(results []dto.BatchResponse) error {
+		for _, v := range results {
+			response.Batch = append(response.Batch, &pb.BatchResponse{
+				CorrelationId: v.CorrelationID,
+				ShortUrl:      v.ShortURL,
+				Status:        v.Status,
+				Error:         v.Error,
+			})
+		}
+
+		return nil
+	}
+
+	err = c.streamBatch(ctx, p.UserID, grpcOrigin(ctx), opts, recv, send)
+	if err != nil {
+		return serviceStatus(err)
+	}
+
+	return stream.SendAndClose(&response)
+}
+
+// WatchClicks pushes redirects of links created by the caller until the client goes away.
+// A client falling behind is disconnected with codes.ResourceExhausted.
+func (c Controller) WatchClicks(in *pb.WatchClicksRequest, stream grpc.ServerStreamingServer[pb.ClickEvent]) error {
+	ctx := stream.Context()
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, events, unsubscribe, err := c.service.SubscribeClicks(p.UserID, 0)
+	if err != nil {
+		return serviceStatus(err)
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "click stream fell behind")
+			}
+
+			if e.Type != models.WebhookLinkClicked {
+				continue
+			}
+
+			err := stream.Send(&pb.ClickEvent{
+				ShortUrl:  e.ShortURL,
+				Timestamp: e.Time.UnixMilli(),
+				ClientIp:  clientIP(e.ClientIP),
+				Referer:   e.Referer,
+				UserAgent: e.UserAgent,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// clientIP formats a visitor address for responses, an unknown one is empty.
+func clientIP(addr netip.Addr) string {
+	if !addr.IsValid() {
+		return ""
+	}
+
+	return addr.String()
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/MukizuL/shortener/internal/dto"
 	"github.com/MukizuL/shortener/internal/errs"
 	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
@@ -26,11 +27,14 @@ import (
 //	@Produce		text/plain
 //	@Param			Cookie	header		string		false	"Cookie with access token"
 //	@Param			URL		body		string		true	"URL to shorten"
+//	@Param			workspace_id	query	string	false	"Workspace to create the link in"
+//	@Param			domain	query		string		false	"Short domain to create the link on, the requested one by default"
 //	@Success		201		body		string		"Short url"
 //	@Header			201		{string}	Set-cookie	"Access token"
 //	@Failure		400		{string}	string		"Wrong URL schema"
+//	@Failure		403		{string}	string		"No edit access to workspace"
 //	@Failure		409		{string}	string		"URL already exists"
-//	@Failure		422		{string}	string		"Not a URL"
+//	@Failure		422		{string}	string		"Not a URL, rejected by URL policy or unknown domain"
 //	@Failure		500		{string}	string		"Internal Server Error"
 //	@Router			/ [post]
 func (c Controller) CreateShortURL(w http.ResponseWriter, r *http.Request) {
@@ -43,24 +47,28 @@ func (c Controller) CreateShortURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url, err := helpers.CheckURL(rawURL)
-	if err != nil {
-		http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
-		return
-	}
+	p, _ := contextI.PrincipalFrom(r.Context())
 
-	userID := r.Context().Value(contextI.UserIDContextKey).(string)
-
-	urlBase := helpers.BuildURLSBase(r.TLS, r.Host)
+	req := dto.Request{
+		FullURL:     string(rawURL),
+		WorkspaceID: r.URL.Query().Get("workspace_id"),
+		Domain:      r.URL.Query().Get("domain"),
+	}
 
-	shortURL, err := c.storage.CreateShortURL(ctx, userID, urlBase, url)
+	shortURL, err := c.service.CreateShortURL(ctx, p.UserID, c.urls.Origin(r), req)
 	if err != nil {
-		if errors.Is(err, errs.ErrDuplicate) {
+		switch {
+		case errors.Is(err, errs.ErrDuplicate):
 			http.Error(w, shortURL, http.StatusConflict)
-			return
+		case errors.Is(err, errs.ErrNotURL):
+			http.Error(w, http.StatusText(http.StatusUnprocessableEntity), http.StatusUnprocessableEntity)
+		case errors.Is(err, errs.ErrRejected), errors.Is(err, errs.ErrUnknownDomain):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, errs.ErrForbidden):
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		default:
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		}
-
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
@@ -75,18 +83,36 @@ func (c Controller) CreateShortURL(w http.ResponseWriter, r *http.Request) {
 
 // GetFullURL godoc
 //
-//	@Summary	Redirects to original URL
-//	@Tags		default
-//	@Produce	text/html
-//	@Param		Cookie	header	string	true	"Cookie with access token"
-//	@Param		ID		query	string	true	"Short URL ID"
-//	@Success	307
-//	@Header		307	{string}	Location	"Original URL"
-//	@Failure	400	{string}	string		"ID is not present"
-//	@Failure	404	{string}	string		"URL not Found"
-//	@Failure	410	{string}	string		"URL deleted"
-//	@Failure	500	{string}	string		"Internal Server Error"
-//	@Router		/:id [get]
+//	@Summary		Redirects to original URL
+//	@Description	For password protected links serves a password form instead. The form is posted back to the same URL.
+//	@Description	Links with a click limit answer 410 once all clicks are used up. HEAD requests don't use up clicks.
+//	@Description	Redirects with the status code chosen at creation, 307 by default.
+//	@Description	ID with a trailing "+" or preview=1 query parameter render a preview page instead of redirecting.
+//	@Tags			default
+//	@Accept			application/x-www-form-urlencoded
+//	@Produce		text/html
+//	@Param			Cookie		header		string	true	"Cookie with access token"
+//	@Param			ID			query		string	true	"Short URL ID"
+//	@Param			password	formData	string	false	"Password of a protected link"
+//	@Param			preview		query		string	false	"Set to 1 to render the preview page"
+//	@Success		200			{string}	string	"Password form or preview page"
+//	@Success		301
+//	@Success		302
+//	@Success		303
+//	@Success		307
+//	@Success		308
+//	@Header			307	{string}	Location		"Original URL"
+//	@Header			307	{string}	Cache-Control	"Caching policy of the redirect"
+//	@Failure		400	{string}	string		"ID is not present"
+//	@Failure		401	{string}	string		"Wrong password"
+//	@Failure		404	{string}	string		"URL not Found"
+//	@Failure		410	{string}	string		"URL deleted, click limit exhausted or link disabled"
+//	@Failure		451	{string}	string		"Link disabled for legal reasons"
+//	@Failure		429	{string}	string		"Too many failed password attempts"
+//	@Failure		500	{string}	string		"Internal Server Error"
+//	@Router			/:id [get]
+//	@Router			/:id [head]
+//	@Router			/:id [post]
 func (c Controller) GetFullURL(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
@@ -97,13 +123,23 @@ func (c Controller) GetFullURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	fullURL, err := c.storage.GetLongURL(ctx, ID)
+	if isPreview(r, ID) {
+		c.previewURL(w, r, ID)
+		return
+	}
+
+	link, err := c.service.Resolve(ctx, c.urls.Origin(r).Host, ID)
 	if err != nil {
 		if errors.Is(err, errs.ErrURLNotFound) {
 			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
 			return
 		}
 
+		if errors.Is(err, errs.ErrDisabled) {
+			c.renderTakedown(w, link.Takedown)
+			return
+		}
+
 		if errors.Is(err, errs.ErrGone) {
 			http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
 			return
@@ -113,32 +149,56 @@ func (c Controller) GetFullURL(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	http.Redirect(w, r, fullURL, http.StatusTemporaryRedirect)
+	if link.Protected() && !c.unlockURL(w, r, link) {
+		return
+	}
+
+	// HEAD requests come from link checkers and unfurlers, they must not use up clicks.
+	if r.Method != http.MethodHead {
+		err = c.service.Visit(ctx, link, contextI.ClientIPFrom(ctx), r.Referer(), r.UserAgent())
+		if err != nil {
+			if errors.Is(err, errs.ErrGone) {
+				http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
+				return
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	redirect(w, r, link)
 }
 
 // GetURLs godoc
 //
 //	@Summary	Returns array of user URLs
+//	@Description	Includes URLs of all workspaces the user is a member of.
 //	@Tags		json
 //	@Produce	application/json
 //	@Param		Cookie	header		string			true	"Cookie with access token"
+//	@Param		domain	query		string			false	"Lists links on this short domain only"
 //	@Success	200		{object}	[]dto.URLPair	"Array of URLs"
+//	@Failure	400		{string}	string			"Unknown domain"
 //	@Failure	500		{string}	string			"Internal Server Error"
 //	@Router		/api/user/urls [get]
 func (c Controller) GetURLs(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	var (
-		userID string
-		ok     bool
-	)
-	if userID, ok = r.Context().Value(contextI.UserIDContextKey).(string); !ok {
+	p, ok := contextI.PrincipalFrom(r.Context())
+	if !ok {
 		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
 	}
 
-	data, err := c.storage.GetUserURLs(ctx, userID)
+	data, err := c.service.UserURLs(ctx, p.UserID, r.URL.Query().Get("domain"))
 	if err != nil {
+		if errors.Is(err, errs.ErrUnknownDomain) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
@@ -153,7 +213,8 @@ func (c Controller) GetURLs(w http.ResponseWriter, r *http.Request) {
 // DeleteURLs godoc
 //
 //	@Summary	Deletes user URLs
-//	@Description Accepts array of short URLs
+//	@Description Accepts array of short URL IDs on the requested domain or full short URLs on any domain.
+//	@Description Workspace editors and owners may delete links of their workspaces.
 //	@Tags		json
 //	@Accept		application/json
 //	@Produce	application/json
@@ -167,7 +228,7 @@ func (c Controller) DeleteURLs(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	userID := r.Context().Value(contextI.UserIDContextKey).(string)
+	p, _ := contextI.PrincipalFrom(r.Context())
 
 	var urls []string
 
@@ -177,7 +238,7 @@ func (c Controller) DeleteURLs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = c.storage.DeleteURLs(ctx, userID, urls)
+	err = c.service.DeleteURLs(ctx, p.UserID, c.urls.Origin(r).Host, urls)
 	if err != nil {
 		if errors.Is(err, errs.ErrUserMismatch) {
 			helpers.WriteJSON(w, http.StatusUnauthorized, dto.ResponseWrapper{"error": http.StatusText(http.StatusUnauthorized)})
@@ -203,8 +264,9 @@ func (c Controller) DeleteURLs(w http.ResponseWriter, r *http.Request) {
 //	@Success		201		body		dto.Response		"Short url"
 //	@Header			201		{string}	Set-cookie			"Access token"
 //	@Failure		400		{object}	dto.ResponseWrapper	"Wrong URL schema"
+//	@Failure		403		{object}	dto.ResponseWrapper	"No edit access to workspace"
 //	@Failure		409		{object}	dto.ResponseWrapper	"URL already exists"
-//	@Failure		422		{object}	dto.ResponseWrapper	"Not a URL"
+//	@Failure		422		{object}	dto.ResponseWrapper	"Not a URL, rejected by URL policy or unknown domain"
 //	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
 //	@Router			/api/shorten [post]
 func (c Controller) CreateShortURLJSON(w http.ResponseWriter, r *http.Request) {
@@ -218,24 +280,23 @@ func (c Controller) CreateShortURLJSON(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	url, err := helpers.CheckURL([]byte(req.FullURL))
-	if err != nil {
-		helpers.WriteJSON(w, http.StatusUnprocessableEntity, dto.ResponseWrapper{"error": fmt.Sprintf("URL %s is unprocessable", req.FullURL)})
-		return
-	}
-
-	userID := r.Context().Value(contextI.UserIDContextKey).(string)
+	p, _ := contextI.PrincipalFrom(r.Context())
 
-	urlBase := helpers.BuildURLSBase(r.TLS, r.Host)
-
-	shortURL, err := c.storage.CreateShortURL(ctx, userID, urlBase, url)
+	shortURL, err := c.service.CreateShortURL(ctx, p.UserID, c.urls.Origin(r), req)
 	if err != nil {
-		if errors.Is(err, errs.ErrDuplicate) {
+		switch {
+		case errors.Is(err, errs.ErrDuplicate):
 			helpers.WriteJSON(w, http.StatusConflict, dto.ResponseWrapper{"result": shortURL})
-			return
+		case errors.Is(err, errs.ErrNotURL):
+			helpers.WriteJSON(w, http.StatusUnprocessableEntity, dto.ResponseWrapper{"error": fmt.Sprintf("URL %s is unprocessable", req.FullURL)})
+		case errors.Is(err, errs.ErrRejected), errors.Is(err, errs.ErrInvalidMaxClicks), errors.Is(err, errs.ErrInvalidRedirectType),
+			errors.Is(err, errs.ErrPasswordTooLong), errors.Is(err, errs.ErrUnknownDomain):
+			helpers.WriteJSON(w, http.StatusUnprocessableEntity, dto.ResponseWrapper{"error": err.Error()})
+		case errors.Is(err, errs.ErrForbidden):
+			helpers.WriteJSON(w, http.StatusForbidden, dto.ResponseWrapper{"error": http.StatusText(http.StatusForbidden)})
+		default:
+			helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
 		}
-
-		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
 
@@ -248,16 +309,20 @@ func (c Controller) CreateShortURLJSON(w http.ResponseWriter, r *http.Request) {
 //
 //	@Summary		Creates a batch of short URLs
 //	@Description	If cookie with access token is not provided, creates a new token with new userID.
+//	@Description	Every item gets a result with status created, existing (with the existing short URL) or invalid (with a reason).
+//	@Description	Invalid and existing items don't prevent the rest from being created.
 //	@Tags			json
 //	@Accept			application/json
 //	@Produce		application/json
 //	@Param			Cookie	header		string				false	"Cookie with access token"
 //	@Param			URL		body		[]dto.BatchRequest	true	"URLs to shorten"
-//	@Success		201		body		[]dto.BatchResponse	"Short urls"
+//	@Param			workspace_id	query	string		false	"Workspace to create the links in"
+//	@Param			domain	query		string		false	"Short domain to create the links on, the requested one by default"
+//	@Success		201		{object}	[]dto.BatchResponse	"All short urls created"
+//	@Success		207		{object}	[]dto.BatchResponse	"Some items are existing or invalid"
 //	@Header			201		{string}	Set-cookie			"Access token"
-//	@Failure		400		{object}	dto.ResponseWrapper	"Wrong URL schema"
-//	@Failure		409		{object}	dto.ResponseWrapper	"URL already exists"
-//	@Failure		422		{object}	dto.ResponseWrapper	"Not a URL"
+//	@Failure		403		{object}	dto.ResponseWrapper	"No edit access to workspace"
+//	@Failure		422		{object}	dto.ResponseWrapper	"Unknown domain"
 //	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
 //	@Router			/api/shorten/batch [post]
 func (c Controller) BatchCreateShortURLJSON(w http.ResponseWriter, r *http.Request) {
@@ -271,22 +336,19 @@ func (c Controller) BatchCreateShortURLJSON(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	for _, v := range req {
-		_, err = helpers.CheckURL([]byte(v.OriginalURL))
-		if err != nil {
-			helpers.WriteJSON(w, http.StatusUnprocessableEntity, dto.ResponseWrapper{"error": fmt.Sprintf("URL %s is unprocessable", v.OriginalURL)})
-			return
-		}
-	}
-
-	userID := r.Context().Value(contextI.UserIDContextKey).(string)
+	p, _ := contextI.PrincipalFrom(r.Context())
 
-	urlBase := helpers.BuildURLSBase(r.TLS, r.Host)
+	opts := models.URLOptions{WorkspaceID: r.URL.Query().Get("workspace_id"), Domain: r.URL.Query().Get("domain")}
 
-	response, err := c.storage.BatchCreateShortURL(ctx, userID, urlBase, req)
+	response, err := c.service.CreateBatch(ctx, p.UserID, c.urls.Origin(r), req, opts)
 	if err != nil {
-		if errors.Is(err, errs.ErrDuplicate) {
-			helpers.WriteJSON(w, http.StatusConflict, dto.ResponseWrapper{"error": http.StatusText(http.StatusConflict)})
+		if errors.Is(err, errs.ErrForbidden) {
+			helpers.WriteJSON(w, http.StatusForbidden, dto.ResponseWrapper{"error": http.StatusText(http.StatusForbidden)})
+			return
+		}
+
+		if errors.Is(err, errs.ErrUnknownDomain) {
+			helpers.WriteJSON(w, http.StatusUnprocessableEntity, dto.ResponseWrapper{"error": err.Error()})
 			return
 		}
 
@@ -294,14 +356,14 @@ func (c Controller) BatchCreateShortURLJSON(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	helpers.WriteJSON(w, http.StatusCreated, response)
+	helpers.WriteJSON(w, batchStatusCode(response), response)
 }
 
 func (c Controller) Ping(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	err := c.storage.Ping(ctx)
+	err := c.service.Ping(ctx)
 	if err != nil {
 		c.logger.Error("Error in Ping handler", zap.Error(err))
 		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
@@ -326,7 +388,7 @@ func (c Controller) GetStats(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
 	defer cancel()
 
-	urls, users, err := c.storage.GetStats(ctx)
+	urls, users, err := c.service.Stats(ctx)
 	if err != nil {
 		c.logger.Error("Error in GetStats handler", zap.Error(err))
 		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
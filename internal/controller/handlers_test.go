@@ -11,16 +11,34 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/MukizuL/shortener/internal/clicks"
 	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/domain"
 	"github.com/MukizuL/shortener/internal/dto"
 	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/service"
 	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
 )
 
+// newTestController returns a controller over a service with dependencies p.
+func newTestController(p service.Params) *Controller {
+	if p.Logger == nil {
+		p.Logger = zap.NewNop()
+	}
+
+	return &Controller{
+		service: service.New(p),
+		logger:  p.Logger,
+	}
+}
+
 func TestApplication_CreateShortURL(t *testing.T) {
 	type want struct {
 		contentType string
@@ -39,7 +57,7 @@ func TestApplication_CreateShortURL(t *testing.T) {
 			body: "https://www.youtube.com",
 			mockStorage: func(m *mockstorage.MockRepo) {
 				m.EXPECT().
-					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com").
+					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{}).
 					Return("http://localhost:8080/qxDvSD", nil)
 			},
 			want: want{
@@ -53,7 +71,7 @@ func TestApplication_CreateShortURL(t *testing.T) {
 			body: "https://www.youtube.com",
 			mockStorage: func(m *mockstorage.MockRepo) {
 				m.EXPECT().
-					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com").
+					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{}).
 					Return("http://localhost:8080/qxDvSD", errs.ErrDuplicate)
 			},
 			want: want{
@@ -86,13 +104,11 @@ func TestApplication_CreateShortURL(t *testing.T) {
 				tt.mockStorage(mockRepo)
 			}
 
-			c := &Controller{
-				storage: mockRepo,
-			}
+			c := newTestController(service.Params{Storage: mockRepo})
 
 			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
 			r.Host = "localhost:8080"
-			r = r.Clone(context.WithValue(r.Context(), contextI.UserIDContextKey, "1"))
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "1"}))
 
 			w := httptest.NewRecorder()
 			c.CreateShortURL(w, r)
@@ -129,8 +145,8 @@ func TestApplication_GetFullURL(t *testing.T) {
 			query: "qxDvSD",
 			mockSetup: func(m *mockstorage.MockRepo) {
 				m.EXPECT().
-					GetLongURL(gomock.Any(), "qxDvSD").
-					Return("https://www.youtube.com", nil)
+					GetLongURL(gomock.Any(), "", "qxDvSD").
+					Return(models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com"}, nil)
 			},
 			want: want{
 				statusCode: 307,
@@ -142,14 +158,69 @@ func TestApplication_GetFullURL(t *testing.T) {
 			query: "qxDvSg",
 			mockSetup: func(m *mockstorage.MockRepo) {
 				m.EXPECT().
-					GetLongURL(gomock.Any(), "qxDvSg").
-					Return("", errs.ErrURLNotFound)
+					GetLongURL(gomock.Any(), "", "qxDvSg").
+					Return(models.Link{}, errs.ErrURLNotFound)
 			},
 			want: want{
 				statusCode: 404,
 				fullURL:    "",
 			},
 		},
+		{
+			name:  "Limited URL",
+			query: "qxDvSL",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().
+					GetLongURL(gomock.Any(), "", "qxDvSL").
+					Return(models.Link{ShortURL: "qxDvSL", OriginalURL: "https://www.youtube.com", MaxClicks: 1, ClicksLeft: 1}, nil)
+				m.EXPECT().ConsumeClick(gomock.Any(), "", "qxDvSL").Return(0, nil)
+			},
+			want: want{
+				statusCode: 307,
+				fullURL:    "https://www.youtube.com",
+			},
+		},
+		{
+			name:  "Limited URL concurrently exhausted",
+			query: "qxDvSL",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().
+					GetLongURL(gomock.Any(), "", "qxDvSL").
+					Return(models.Link{ShortURL: "qxDvSL", OriginalURL: "https://www.youtube.com", MaxClicks: 1, ClicksLeft: 1}, nil)
+				m.EXPECT().ConsumeClick(gomock.Any(), "", "qxDvSL").Return(0, errs.ErrGone)
+			},
+			want: want{
+				statusCode: 410,
+				fullURL:    "",
+			},
+		},
+		{
+			name:  "Exhausted URL",
+			query: "qxDvSL",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().
+					GetLongURL(gomock.Any(), "", "qxDvSL").
+					Return(models.Link{ShortURL: "qxDvSL", OriginalURL: "https://www.youtube.com", MaxClicks: 1, ClicksLeft: 0}, nil)
+			},
+			want: want{
+				statusCode: 410,
+				fullURL:    "",
+			},
+		},
+		{
+			name:  "Disabled URL",
+			query: "qxDvSB",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().
+					GetLongURL(gomock.Any(), "", "qxDvSB").
+					Return(models.Link{ShortURL: "qxDvSB", OriginalURL: "https://www.youtube.com",
+						Takedown: models.Takedown{Status: http.StatusUnavailableForLegalReasons, Reason: "Court order"}}, nil)
+			},
+			want: want{
+				statusCode: 451,
+				fullURL:    "",
+			},
+		},
 		{
 			name:  "Empty URL",
 			query: "",
@@ -173,9 +244,7 @@ func TestApplication_GetFullURL(t *testing.T) {
 				tt.mockSetup(mockRepo)
 			}
 
-			app := &Controller{
-				storage: mockRepo,
-			}
+			app := newTestController(service.Params{Storage: mockRepo})
 
 			r := httptest.NewRequest(http.MethodGet, "/", nil)
 
@@ -215,7 +284,7 @@ func TestApplication_GetURLs(t *testing.T) {
 		{
 			name: "Correct UserID with links",
 			mockSetup: func(m *mockstorage.MockRepo) {
-				m.EXPECT().GetUserURLs(gomock.Any(), "user1").Return([]dto.URLPair{
+				m.EXPECT().GetUserURLs(gomock.Any(), "user1", nil).Return([]dto.URLPair{
 					{ShortURL: "https://link1.com", OriginalURL: "https://localhost:8080/1"},
 					{ShortURL: "https://link2.com", OriginalURL: "https://localhost:8080/2"},
 					{ShortURL: "https://link3.com", OriginalURL: "https://localhost:8080/3"},
@@ -238,7 +307,7 @@ func TestApplication_GetURLs(t *testing.T) {
 		{
 			name: "Correct UserID with no links",
 			mockSetup: func(m *mockstorage.MockRepo) {
-				m.EXPECT().GetUserURLs(gomock.Any(), "user1").Return([]dto.URLPair{}, nil)
+				m.EXPECT().GetUserURLs(gomock.Any(), "user1", nil).Return([]dto.URLPair{}, nil)
 			},
 			user: "user1",
 			want: want{
@@ -249,7 +318,7 @@ func TestApplication_GetURLs(t *testing.T) {
 		{
 			name: "Error in storage",
 			mockSetup: func(m *mockstorage.MockRepo) {
-				m.EXPECT().GetUserURLs(gomock.Any(), "user1").Return([]dto.URLPair{}, errs.ErrInternalServerError)
+				m.EXPECT().GetUserURLs(gomock.Any(), "user1", nil).Return([]dto.URLPair{}, errs.ErrInternalServerError)
 			},
 			user: "user1",
 			want: want{
@@ -269,13 +338,11 @@ func TestApplication_GetURLs(t *testing.T) {
 				tt.mockSetup(mockRepo)
 			}
 
-			app := &Controller{
-				storage: mockRepo,
-			}
+			app := newTestController(service.Params{Storage: mockRepo})
 
 			r := httptest.NewRequest(http.MethodGet, "/api/user/urls", nil)
 
-			r = r.Clone(context.WithValue(r.Context(), contextI.UserIDContextKey, tt.user))
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: tt.user}))
 
 			w := httptest.NewRecorder()
 			app.GetURLs(w, r)
@@ -313,21 +380,21 @@ func TestApplication_DeleteURLs(t *testing.T) {
 		{
 			name: "Correct UserID with links",
 			mockSetup: func(m *mockstorage.MockRepo) {
-				m.EXPECT().DeleteURLs(gomock.Any(), "user1", []string{
-					"https://link1.com",
-					"https://link2.com",
-					"https://link3.com",
-					"https://link4.com",
-					"https://link5.com",
+				m.EXPECT().DeleteURLs(gomock.Any(), "user1", "", []string{
+					"qxDvS1",
+					"qxDvS2",
+					"qxDvS3",
+					"qxDvS4",
+					"qxDvS5",
 				}).Return(nil)
 			},
 			user: "user1",
 			links: []string{
-				"https://link1.com",
-				"https://link2.com",
-				"https://link3.com",
-				"https://link4.com",
-				"https://link5.com",
+				"qxDvS1",
+				"qxDvS2",
+				"qxDvS3",
+				"qxDvS4",
+				"qxDvS5",
 			},
 			want: want{
 				statusCode: 202,
@@ -336,28 +403,43 @@ func TestApplication_DeleteURLs(t *testing.T) {
 		{
 			name: "Error in storage",
 			mockSetup: func(m *mockstorage.MockRepo) {
-				m.EXPECT().DeleteURLs(gomock.Any(), "user1", []string{
-					"https://link1.com",
-					"https://link2.com",
-					"https://link3.com",
-					"https://link4.com",
-					"https://link5.com",
+				m.EXPECT().DeleteURLs(gomock.Any(), "user1", "", []string{
+					"qxDvS1",
+					"qxDvS2",
+					"qxDvS3",
+					"qxDvS4",
+					"qxDvS5",
 				}).Return(errs.ErrInternalServerError)
 			},
 			user: "user1",
 			links: []string{
-				"https://link1.com",
-				"https://link2.com",
-				"https://link3.com",
-				"https://link4.com",
-				"https://link5.com",
+				"qxDvS1",
+				"qxDvS2",
+				"qxDvS3",
+				"qxDvS4",
+				"qxDvS5",
 			},
 			want: want{
 				statusCode: 500,
 			},
 		},
+		{
+			name: "Links on several domains",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().DeleteURLs(gomock.Any(), "user1", "", []string{"qxDvS1", "qxDvS3"}).Return(nil)
+				m.EXPECT().DeleteURLs(gomock.Any(), "user1", "brand.ly", []string{"qxDvS2"}).Return(nil)
+			},
+			user:  "user1",
+			links: []string{"qxDvS1", "https://brand.ly/qxDvS2", "https://example.com/qxDvS3"},
+			want: want{
+				statusCode: 202,
+			},
+		},
 	}
 
+	domains, err := domain.New("example.com", "brand.ly")
+	require.NoError(t, err)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -368,9 +450,7 @@ func TestApplication_DeleteURLs(t *testing.T) {
 				tt.mockSetup(mockRepo)
 			}
 
-			app := &Controller{
-				storage: mockRepo,
-			}
+			app := newTestController(service.Params{Storage: mockRepo, Domains: domains})
 
 			buf := &bytes.Buffer{}
 			err := json.NewEncoder(buf).Encode(tt.links)
@@ -378,7 +458,7 @@ func TestApplication_DeleteURLs(t *testing.T) {
 
 			r := httptest.NewRequest(http.MethodDelete, "/api/user/urls", buf)
 
-			r = r.Clone(context.WithValue(r.Context(), contextI.UserIDContextKey, tt.user))
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: tt.user}))
 
 			w := httptest.NewRecorder()
 			app.DeleteURLs(w, r)
@@ -411,7 +491,7 @@ func TestApplication_CreateShortURLJSON(t *testing.T) {
 			body: "https://www.youtube.com",
 			mockStorage: func(m *mockstorage.MockRepo) {
 				m.EXPECT().
-					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com").
+					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{}).
 					Return("http://localhost:8080/qxDvSD", nil)
 			},
 			want: want{
@@ -425,7 +505,7 @@ func TestApplication_CreateShortURLJSON(t *testing.T) {
 			body: "https://www.youtube.com",
 			mockStorage: func(m *mockstorage.MockRepo) {
 				m.EXPECT().
-					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com").
+					CreateShortURL(gomock.Any(), gomock.Any(), "http://localhost:8080/", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{}).
 					Return("http://localhost:8080/qxDvSD", errs.ErrDuplicate)
 			},
 			want: want{
@@ -482,9 +562,7 @@ func TestApplication_CreateShortURLJSON(t *testing.T) {
 				tt.mockStorage(mockRepo)
 			}
 
-			c := &Controller{
-				storage: mockRepo,
-			}
+			c := newTestController(service.Params{Storage: mockRepo})
 
 			data, err := json.Marshal(&dto.Request{FullURL: tt.body})
 			require.NoError(t, err)
@@ -492,7 +570,7 @@ func TestApplication_CreateShortURLJSON(t *testing.T) {
 			r := httptest.NewRequest(http.MethodPost, "/api/shorten", bytes.NewReader(data))
 			r.Header.Set("Content-Type", "application/json")
 			r.Host = "localhost:8080"
-			r = r.Clone(context.WithValue(r.Context(), contextI.UserIDContextKey, "1"))
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "1"}))
 
 			w := httptest.NewRecorder()
 			c.CreateShortURLJSON(w, r)
@@ -553,18 +631,21 @@ func TestApplication_BatchCreateShortURLJSON(t *testing.T) {
 				},
 			},
 			mockStorage: func(m *mockstorage.MockRepo) {
-				m.EXPECT().BatchCreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", gomock.Any()).Return([]dto.BatchResponse{
+				m.EXPECT().BatchCreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", gomock.Any(), models.URLOptions{}).Return([]dto.BatchResponse{
 					{
 						CorrelationID: "1",
 						ShortURL:      "http://localhost:8080/qxDvSD",
+						Status:        dto.BatchStatusCreated,
 					},
 					{
 						CorrelationID: "2",
 						ShortURL:      "http://localhost:8080/qxDvSS",
+						Status:        dto.BatchStatusCreated,
 					},
 					{
 						CorrelationID: "3",
 						ShortURL:      "http://localhost:8080/qxDvSB",
+						Status:        dto.BatchStatusCreated,
 					},
 				}, nil)
 			},
@@ -575,14 +656,80 @@ func TestApplication_BatchCreateShortURLJSON(t *testing.T) {
 					{
 						CorrelationID: "1",
 						ShortURL:      "http://localhost:8080/qxDvSD",
+						Status:        dto.BatchStatusCreated,
 					},
 					{
 						CorrelationID: "2",
 						ShortURL:      "http://localhost:8080/qxDvSS",
+						Status:        dto.BatchStatusCreated,
+					},
+					{
+						CorrelationID: "3",
+						ShortURL:      "http://localhost:8080/qxDvSB",
+						Status:        dto.BatchStatusCreated,
+					},
+				},
+			},
+		},
+		{
+			name: "Partial success",
+			body: []dto.BatchRequest{
+				{
+					CorrelationID: "1",
+					OriginalURL:   "https://www.youtube1.com",
+				},
+				{
+					CorrelationID: "2",
+					OriginalURL:   "not a url",
+				},
+				{
+					CorrelationID: "3",
+					OriginalURL:   "https://www.youtube3.com",
+				},
+			},
+			mockStorage: func(m *mockstorage.MockRepo) {
+				m.EXPECT().BatchCreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", []dto.BatchRequest{
+					{
+						CorrelationID: "1",
+						OriginalURL:   "https://www.youtube1.com",
+						CanonicalURL:  "https://www.youtube1.com/",
+					},
+					{
+						CorrelationID: "3",
+						OriginalURL:   "https://www.youtube3.com",
+						CanonicalURL:  "https://www.youtube3.com/",
+					},
+				}, models.URLOptions{}).Return([]dto.BatchResponse{
+					{
+						CorrelationID: "1",
+						ShortURL:      "http://localhost:8080/qxDvSD",
+						Status:        dto.BatchStatusExisting,
+					},
+					{
+						CorrelationID: "3",
+						ShortURL:      "http://localhost:8080/qxDvSB",
+						Status:        dto.BatchStatusCreated,
+					},
+				}, nil)
+			},
+			want: want{
+				contentType: "application/json",
+				statusCode:  207,
+				response: []dto.BatchResponse{
+					{
+						CorrelationID: "1",
+						ShortURL:      "http://localhost:8080/qxDvSD",
+						Status:        dto.BatchStatusExisting,
+					},
+					{
+						CorrelationID: "2",
+						Status:        dto.BatchStatusInvalid,
+						Error:         "not a url",
 					},
 					{
 						CorrelationID: "3",
 						ShortURL:      "http://localhost:8080/qxDvSB",
+						Status:        dto.BatchStatusCreated,
 					},
 				},
 			},
@@ -605,7 +752,7 @@ func TestApplication_BatchCreateShortURLJSON(t *testing.T) {
 			},
 			mockStorage: func(m *mockstorage.MockRepo) {
 				m.EXPECT().
-					BatchCreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", gomock.Any()).
+					BatchCreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", gomock.Any(), models.URLOptions{}).
 					Return([]dto.BatchResponse{}, errs.ErrInternalServerError)
 			},
 			want: want{
@@ -626,9 +773,7 @@ func TestApplication_BatchCreateShortURLJSON(t *testing.T) {
 				tt.mockStorage(mockRepo)
 			}
 
-			c := &Controller{
-				storage: mockRepo,
-			}
+			c := newTestController(service.Params{Storage: mockRepo})
 
 			buf := &bytes.Buffer{}
 
@@ -638,7 +783,7 @@ func TestApplication_BatchCreateShortURLJSON(t *testing.T) {
 			r := httptest.NewRequest(http.MethodPost, "/api/shorten/batch", buf)
 			r.Header.Set("Content-Type", "application/json")
 			r.Host = "localhost:8080"
-			r = r.Clone(context.WithValue(r.Context(), contextI.UserIDContextKey, "user1"))
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "user1"}))
 
 			w := httptest.NewRecorder()
 			c.BatchCreateShortURLJSON(w, r)
@@ -651,7 +796,7 @@ func TestApplication_BatchCreateShortURLJSON(t *testing.T) {
 			fmt.Println(result.StatusCode)
 
 			switch tt.want.statusCode {
-			case http.StatusCreated:
+			case http.StatusCreated, http.StatusMultiStatus:
 				var resp []dto.BatchResponse
 				err = json.NewDecoder(result.Body).Decode(&resp)
 				require.NoError(t, err)
@@ -668,3 +813,175 @@ func TestApplication_BatchCreateShortURLJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestApplication_GetFullURLRedirectType(t *testing.T) {
+	type want struct {
+		statusCode   int
+		cacheControl string
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		link   models.Link
+		want   want
+	}{
+		{
+			name:   "Default redirect",
+			method: http.MethodGet,
+			link:   models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com"},
+			want:   want{statusCode: http.StatusTemporaryRedirect, cacheControl: "private, no-cache"},
+		},
+		{
+			name:   "Permanent redirect",
+			method: http.MethodGet,
+			link:   models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com", RedirectType: http.StatusPermanentRedirect},
+			want:   want{statusCode: http.StatusPermanentRedirect, cacheControl: "public, max-age=86400"},
+		},
+		{
+			name:   "Found",
+			method: http.MethodGet,
+			link:   models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com", RedirectType: http.StatusFound},
+			want:   want{statusCode: http.StatusFound, cacheControl: "private, no-cache"},
+		},
+		{
+			name:   "HEAD of limited permanent link doesn't use up clicks",
+			method: http.MethodHead,
+			link: models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com",
+				RedirectType: http.StatusMovedPermanently, MaxClicks: 1, ClicksLeft: 1},
+			want: want{statusCode: http.StatusMovedPermanently, cacheControl: "no-store"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(tt.link, nil)
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(tt.method, "/qxDvSD", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "qxDvSD")
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			app.GetFullURL(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+			assert.Equal(t, tt.want.cacheControl, result.Header.Get("Cache-Control"))
+			assert.Equal(t, "https://www.youtube.com", result.Header.Get("Location"))
+		})
+	}
+}
+
+func TestApplication_GetFullURLClickEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		wantEvent bool
+	}{
+		{name: "GET is a click", method: http.MethodGet, wantEvent: true},
+		{name: "HEAD is not a click", method: http.MethodHead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").
+				Return(models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com", UserID: "user1"}, nil)
+
+			hub := clicks.New(zap.NewNop())
+			_, events, unsubscribe := hub.Subscribe("user1", 0)
+			defer unsubscribe()
+
+			app := newTestController(service.Params{Storage: mockRepo, Clicks: hub})
+
+			r := httptest.NewRequest(tt.method, "/qxDvSD", nil)
+			r.Header.Set("Referer", "https://news.example/")
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "qxDvSD")
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			app.GetFullURL(w, r)
+
+			if !tt.wantEvent {
+				assert.Empty(t, events)
+				return
+			}
+
+			require.Len(t, events, 1)
+			e := <-events
+			assert.Equal(t, "qxDvSD", e.ShortURL)
+			assert.Equal(t, "https://news.example/", e.Referer)
+		})
+	}
+}
+
+func TestApplication_CreateShortURLJSONPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		wantError error
+	}{
+		{
+			name:      "Loopback",
+			url:       "http://127.0.0.1/admin",
+			wantError: errs.ErrPrivateAddress,
+		},
+		{
+			name:      "Localhost",
+			url:       "http://localhost:8080/qxDvSD",
+			wantError: errs.ErrPrivateAddress,
+		},
+		{
+			name:      "Own host",
+			url:       "https://short.example/qxDvSD",
+			wantError: errs.ErrSelfReferential,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			app := newTestController(service.Params{
+				Storage: mockstorage.NewMockRepo(ctrl),
+				Policy:  policy.New(zap.NewNop(), policy.PrivateAddress{}, policy.SelfReference{}),
+			})
+
+			body := `{"url":"` + tt.url + `"}`
+			r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body))
+			r.Host = "short.example"
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "user1"}))
+
+			w := httptest.NewRecorder()
+			app.CreateShortURLJSON(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			var resp dto.ResponseWrapper
+			err := json.NewDecoder(result.Body).Decode(&resp)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, result.StatusCode)
+			assert.Contains(t, resp["error"], tt.wantError.Error())
+		})
+	}
+}
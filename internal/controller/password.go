@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"go.uber.org/zap"
+)
+
+var passwordForm = template.Must(template.New("password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Protected link</title>
+</head>
+<body>
+	<h1>This link is protected</h1>
+	{{if .Error}}<p style="color: red">{{.Error}}</p>{{end}}
+	<form method="post" action="{{.Action}}">
+		<label>Password <input type="password" name="password" autofocus required></label>
+		<button type="submit">Open</button>
+	</form>
+</body>
+</html>
+`))
+
+type passwordFormData struct {
+	Action string
+	Error  string
+}
+
+// unlockURL serves the password form on GET and checks the password on POST.
+// Reports whether the correct password was provided. Otherwise, the response is already written.
+func (c Controller) unlockURL(w http.ResponseWriter, r *http.Request, link models.Link) bool {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if r.Method != http.MethodPost {
+		c.renderPasswordForm(w, r, http.StatusOK, "")
+		return false
+	}
+
+	err := c.service.Unlock(link, r.PostFormValue("password"), contextI.ClientIPFrom(r.Context()))
+	switch {
+	case errors.Is(err, errs.ErrTooManyAttempts):
+		c.renderPasswordForm(w, r, http.StatusTooManyRequests, "Too many failed attempts, try again later.")
+		return false
+	case err != nil:
+		c.renderPasswordForm(w, r, http.StatusUnauthorized, "Wrong password.")
+		return false
+	}
+
+	return true
+}
+
+func (c Controller) renderPasswordForm(w http.ResponseWriter, r *http.Request, status int, message string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+
+	err := passwordForm.Execute(w, passwordFormData{Action: r.URL.Path, Error: message})
+	if err != nil {
+		c.logger.Error("Error rendering password form", zap.Error(err))
+	}
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/limiter"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/service"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	pb "github.com/MukizuL/shortener/proto"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestApplication_GetFullURLProtected(t *testing.T) {
+	hash, err := helpers.HashPassword("secret")
+	require.NoError(t, err)
+
+	link := models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com", PasswordHash: hash}
+
+	type want struct {
+		statusCode int
+		location   string
+	}
+
+	tests := []struct {
+		name     string
+		method   string
+		password string
+		failures int
+		want     want
+	}{
+		{
+			name:   "Form is served",
+			method: http.MethodGet,
+			want:   want{statusCode: http.StatusOK},
+		},
+		{
+			name:     "Correct password",
+			method:   http.MethodPost,
+			password: "secret",
+			want:     want{statusCode: http.StatusSeeOther, location: "https://www.youtube.com"},
+		},
+		{
+			name:     "Wrong password",
+			method:   http.MethodPost,
+			password: "wrong",
+			want:     want{statusCode: http.StatusUnauthorized},
+		},
+		{
+			name:     "Too many attempts",
+			method:   http.MethodPost,
+			password: "secret",
+			failures: 3,
+			want:     want{statusCode: http.StatusTooManyRequests},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(link, nil)
+
+			app := newTestController(service.Params{Storage: mockRepo, Limiter: limiter.New(3, time.Minute)})
+
+			r := httptest.NewRequest(tt.method, "/qxDvSD", strings.NewReader(url.Values{"password": {tt.password}}.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			client := netip.MustParseAddr("192.0.2.1")
+			for i := 0; i < tt.failures; i++ {
+				err := app.service.Unlock(link, "wrong", client)
+				require.ErrorIs(t, err, errs.ErrWrongPassword)
+			}
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "qxDvSD")
+
+			ctx := contextI.WithClientIP(r.Context(), client)
+			r = r.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			app.GetFullURL(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+			assert.Equal(t, tt.want.location, result.Header.Get("Location"))
+		})
+	}
+}
+
+func TestApplication_CreateShortURLJSONPasswordTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app := newTestController(service.Params{Storage: mockstorage.NewMockRepo(ctrl)})
+
+	body := `{"url":"https://www.youtube.com","password":"` + strings.Repeat("a", helpers.MaxPasswordLength+1) + `"}`
+	r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body))
+	r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "user1"}))
+
+	w := httptest.NewRecorder()
+	app.CreateShortURLJSON(w, r)
+
+	result := w.Result()
+	defer result.Body.Close()
+
+	var resp dto.ResponseWrapper
+	err := json.NewDecoder(result.Body).Decode(&resp)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, result.StatusCode)
+	assert.Equal(t, errs.ErrPasswordTooLong.Error(), resp["error"])
+}
+
+func TestApplication_CreateGRPCPasswordTooLong(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	app := newTestController(service.Params{Storage: mockstorage.NewMockRepo(ctrl)})
+
+	ctx := contextI.WithPrincipal(context.Background(), contextI.Principal{UserID: "user1"})
+	_, err := app.CreateGRPC(ctx, &pb.CreateShortURLRequest{
+		OriginalUrl: "https://www.youtube.com",
+		Password:    strings.Repeat("a", helpers.MaxPasswordLength+1),
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
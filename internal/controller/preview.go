@@ -0,0 +1,179 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// previewSuffix appended to a short URL shows the preview page instead of redirecting.
+const previewSuffix = "+"
+
+const (
+	statusActive    = "active"
+	statusExhausted = "exhausted"
+	statusDisabled  = "disabled"
+)
+
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex">
+	<title>Link preview</title>
+</head>
+<body>
+	<h1>Link preview</h1>
+	<dl>
+		<dt>Short URL</dt>
+		<dd>{{.ShortURL}}</dd>
+		<dt>Destination</dt>
+		<dd>{{if .OriginalURL}}<a href="{{.OriginalURL}}" rel="nofollow noopener">{{.OriginalURL}}</a>{{else if eq .Status "disabled"}}Hidden, the link is disabled{{else}}Hidden, the link is password protected{{end}}</dd>
+		{{if not .CreatedAt.IsZero}}<dt>Created</dt>
+		<dd>{{.CreatedAt.Format "2006-01-02 15:04 MST"}}</dd>{{end}}
+		<dt>Status</dt>
+		<dd>{{.Status}}{{if .Protected}}, password protected{{end}}</dd>
+		{{if .DisabledReason}}<dt>Reason</dt>
+		<dd>{{.DisabledReason}}</dd>{{end}}
+		{{if .Owner}}{{if .WorkspaceID}}<dt>Workspace</dt>
+		<dd>{{.WorkspaceID}}</dd>{{end}}
+		{{if .MaxClicks}}<dt>Clicks left</dt>
+		<dd>{{.ClicksLeft}} of {{.MaxClicks}}</dd>{{end}}
+		<dt>Redirect type</dt>
+		<dd>{{.RedirectType}}</dd>{{end}}
+	</dl>
+</body>
+</html>
+`))
+
+// isPreview reports whether the request asks for the preview page of a link.
+func isPreview(r *http.Request, ID string) bool {
+	return strings.HasSuffix(ID, previewSuffix) || r.URL.Query().Get("preview") == "1"
+}
+
+// getLinkInfo describes a link for the user from the request context.
+// Destination of a protected link and its settings are only shown to the owner and members of its workspace.
+func (c Controller) getLinkInfo(ctx context.Context, r *http.Request, ID string) (dto.LinkInfo, error) {
+	o := c.urls.Origin(r)
+
+	link, err := c.service.Link(ctx, o.Host, ID)
+	if err != nil {
+		return dto.LinkInfo{}, err
+	}
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	owner, err := c.service.IsLinkOwner(ctx, link, p.UserID)
+	if err != nil {
+		return dto.LinkInfo{}, err
+	}
+
+	info := dto.LinkInfo{
+		ShortURL:  c.service.ShortURL(o, link),
+		CreatedAt: link.CreatedAt,
+		Status:    statusActive,
+		Protected: link.Protected(),
+		Owner:     owner,
+	}
+
+	switch {
+	case link.Disabled():
+		info.Status = statusDisabled
+		info.DisabledReason = link.Takedown.Reason
+	case link.Exhausted():
+		info.Status = statusExhausted
+	}
+
+	// Destinations of disabled links are taken down for everyone but the owner.
+	if (!link.Protected() && !link.Disabled()) || owner {
+		info.OriginalURL = link.OriginalURL
+	}
+
+	if owner {
+		info.WorkspaceID = link.WorkspaceID
+		info.MaxClicks = link.MaxClicks
+		info.ClicksLeft = link.ClicksLeft
+		info.RedirectType = link.StatusCode()
+	}
+
+	return info, nil
+}
+
+// previewURL renders the preview page of a link.
+func (c Controller) previewURL(w http.ResponseWriter, r *http.Request, ID string) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	info, err := c.getLinkInfo(ctx, r, strings.TrimSuffix(ID, previewSuffix))
+	if err != nil {
+		if errors.Is(err, errs.ErrURLNotFound) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, errs.ErrGone) {
+			http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// The page depends on who is looking at it.
+	w.Header().Set("Cache-Control", "private, no-cache")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	err = previewPage.Execute(w, info)
+	if err != nil {
+		c.logger.Error("Error rendering preview page", zap.Error(err))
+	}
+}
+
+// GetLinkInfo godoc
+//
+//	@Summary		Returns information about a short URL
+//	@Description	Works without a token. Destination of a password protected link, click counters,
+//	@Description	workspace and redirect type are only returned to the owner and members of the link's workspace.
+//	@Tags			json
+//	@Produce		application/json
+//	@Param			Cookie	header		string				false	"Cookie with access token"
+//	@Param			id		path		string				true	"Short URL ID"
+//	@Success		200		{object}	dto.LinkInfo		"Link information"
+//	@Failure		404		{object}	dto.ResponseWrapper	"URL not found"
+//	@Failure		410		{object}	dto.ResponseWrapper	"URL deleted"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/links/{id} [get]
+func (c Controller) GetLinkInfo(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	info, err := c.getLinkInfo(ctx, r, chi.URLParam(r, "id"))
+	if err != nil {
+		if errors.Is(err, errs.ErrURLNotFound) {
+			helpers.WriteJSON(w, http.StatusNotFound, dto.ResponseWrapper{"error": http.StatusText(http.StatusNotFound)})
+			return
+		}
+
+		if errors.Is(err, errs.ErrGone) {
+			helpers.WriteJSON(w, http.StatusGone, dto.ResponseWrapper{"error": http.StatusText(http.StatusGone)})
+			return
+		}
+
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, info)
+}
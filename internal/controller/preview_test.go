@@ -0,0 +1,209 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/service"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApplication_GetFullURLPreview(t *testing.T) {
+	link := models.Link{
+		ShortURL:     "qxDvSD",
+		OriginalURL:  "https://www.youtube.com",
+		UserID:       "user1",
+		CreatedAt:    time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC),
+		PasswordHash: "hash",
+		MaxClicks:    5,
+		ClicksLeft:   3,
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		userID     string
+		wantShown  []string
+		wantHidden []string
+	}{
+		{
+			name:       "Plus suffix, anonymous",
+			path:       "/qxDvSD+",
+			wantShown:  []string{"2025-01-02 03:04 UTC", "password protected"},
+			wantHidden: []string{"https://www.youtube.com", "Clicks left"},
+		},
+		{
+			name:      "Query parameter, owner",
+			path:      "/qxDvSD?preview=1",
+			userID:    "user1",
+			wantShown: []string{"https://www.youtube.com", "3 of 5", "307"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(link, nil)
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", r.URL.Path[1:])
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			if tt.userID != "" {
+				r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: tt.userID}))
+			}
+
+			w := httptest.NewRecorder()
+			app.GetFullURL(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			body, err := io.ReadAll(result.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, http.StatusOK, result.StatusCode)
+			assert.Contains(t, result.Header.Get("Content-Type"), "text/html")
+
+			for _, s := range tt.wantShown {
+				assert.Contains(t, string(body), s)
+			}
+
+			for _, s := range tt.wantHidden {
+				assert.NotContains(t, string(body), s)
+			}
+		})
+	}
+}
+
+func TestApplication_GetLinkInfo(t *testing.T) {
+	const workspaceID = "6b1d0c1e-7d3f-4a55-9b43-1a8c2f0e9d11"
+
+	link := models.Link{
+		ShortURL:     "qxDvSD",
+		OriginalURL:  "https://www.youtube.com",
+		UserID:       "user1",
+		WorkspaceID:  workspaceID,
+		PasswordHash: "hash",
+		MaxClicks:    1,
+		ClicksLeft:   0,
+		RedirectType: http.StatusMovedPermanently,
+	}
+
+	tests := []struct {
+		name       string
+		userID     string
+		mockSetup  func(m *mockstorage.MockRepo)
+		wantStatus int
+		want       dto.LinkInfo
+	}{
+		{
+			name:   "Workspace member sees everything",
+			userID: "user2",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(link, nil)
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user2").Return(models.RoleViewer, nil)
+			},
+			wantStatus: http.StatusOK,
+			want: dto.LinkInfo{
+				ShortURL:     "http://localhost:8080/qxDvSD",
+				OriginalURL:  "https://www.youtube.com",
+				Status:       statusExhausted,
+				Protected:    true,
+				Owner:        true,
+				WorkspaceID:  workspaceID,
+				MaxClicks:    1,
+				RedirectType: http.StatusMovedPermanently,
+			},
+		},
+		{
+			name:   "Stranger doesn't see protected destination",
+			userID: "user3",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(link, nil)
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user3").Return(models.Role(""), errs.ErrForbidden)
+			},
+			wantStatus: http.StatusOK,
+			want: dto.LinkInfo{
+				ShortURL:  "http://localhost:8080/qxDvSD",
+				Status:    statusExhausted,
+				Protected: true,
+			},
+		},
+		{
+			name: "Not found",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{}, errs.ErrURLNotFound)
+			},
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "Deleted",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{}, errs.ErrGone)
+			},
+			wantStatus: http.StatusGone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			tt.mockSetup(mockRepo)
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodGet, "/api/links/qxDvSD", nil)
+			r.Host = "localhost:8080"
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "qxDvSD")
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			if tt.userID != "" {
+				r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: tt.userID}))
+			}
+
+			w := httptest.NewRecorder()
+			app.GetLinkInfo(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, result.StatusCode)
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var got dto.LinkInfo
+			err := json.NewDecoder(result.Body).Decode(&got)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
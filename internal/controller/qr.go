@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/qr"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// qrCacheControl lets printers and browsers keep a QR code for a day. The encoded short URL never changes.
+const qrCacheControl = "public, max-age=86400"
+
+// GetQRCode godoc
+//
+//	@Summary		Returns QR code of a short URL
+//	@Description	Encodes the full short URL. Supports conditional requests with If-None-Match.
+//	@Tags			default
+//	@Produce		image/png
+//	@Produce		image/svg+xml
+//	@Param			id				path		string	true	"Short URL ID"
+//	@Param			format			query		string	false	"png (default) or svg"
+//	@Param			size			query		int		false	"Width and height in pixels, 64 to 1024, 256 by default"
+//	@Param			level			query		string	false	"Error correction level: L, M (default), Q or H"
+//	@Param			If-None-Match	header		string	false	"ETag of a cached image"
+//	@Success		200				{file}		file	"QR code"
+//	@Success		304
+//	@Header			200				{string}	ETag	"Version of the image"
+//	@Failure		400				{string}	string	"Invalid format, size or level"
+//	@Failure		404				{string}	string	"URL not Found"
+//	@Failure		410				{string}	string	"URL deleted"
+//	@Failure		500				{string}	string	"Internal Server Error"
+//	@Router			/{id}/qr [get]
+func (c Controller) GetQRCode(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	ID := chi.URLParam(r, "id")
+
+	query := r.URL.Query()
+
+	var size int
+	if rawSize := query.Get("size"); rawSize != "" {
+		var err error
+		size, err = strconv.Atoi(rawSize)
+		if err != nil {
+			http.Error(w, errs.ErrInvalidQRSize.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	opts, err := qr.NewOptions(query.Get("format"), size, query.Get("level"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	o := c.urls.Origin(r)
+
+	link, err := c.service.Link(ctx, o.Host, ID)
+	if err != nil {
+		if errors.Is(err, errs.ErrURLNotFound) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		if errors.Is(err, errs.ErrGone) {
+			http.Error(w, http.StatusText(http.StatusGone), http.StatusGone)
+			return
+		}
+
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	shortURL := c.service.ShortURL(o, link)
+	etag := opts.ETag(shortURL)
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", qrCacheControl)
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	data, err := qr.Encode(shortURL, opts)
+	if err != nil {
+		c.logger.Error("Error in handler GetQRCode", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", opts.ContentType())
+	w.WriteHeader(http.StatusOK)
+
+	_, err = w.Write(data)
+	if err != nil {
+		c.logger.Error("Error in handler GetQRCode", zap.Error(err))
+	}
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag. Weak tags match too.
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
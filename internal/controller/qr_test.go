@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/qr"
+	"github.com/MukizuL/shortener/internal/service"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApplication_GetQRCode(t *testing.T) {
+	link := models.Link{ShortURL: "qxDvSD", OriginalURL: "https://www.youtube.com"}
+	etag := qr.Options{Format: qr.FormatSVG, Size: qr.DefaultSize, Level: qr.DefaultLevel}.ETag("http://localhost:8080/qxDvSD")
+
+	type want struct {
+		statusCode  int
+		contentType string
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		ifNoneMatch string
+		mockSetup   func(m *mockstorage.MockRepo)
+		want        want
+	}{
+		{
+			name: "PNG by default",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(link, nil)
+			},
+			want: want{statusCode: http.StatusOK, contentType: "image/png"},
+		},
+		{
+			name:  "SVG",
+			query: "?format=svg&size=512&level=H",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(link, nil)
+			},
+			want: want{statusCode: http.StatusOK, contentType: "image/svg+xml"},
+		},
+		{
+			name:        "Not modified",
+			query:       "?format=svg",
+			ifNoneMatch: etag,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(link, nil)
+			},
+			want: want{statusCode: http.StatusNotModified},
+		},
+		{
+			name:      "Invalid size",
+			query:     "?size=big",
+			mockSetup: func(m *mockstorage.MockRepo) {},
+			want:      want{statusCode: http.StatusBadRequest},
+		},
+		{
+			name:      "Invalid level",
+			query:     "?level=Z",
+			mockSetup: func(m *mockstorage.MockRepo) {},
+			want:      want{statusCode: http.StatusBadRequest},
+		},
+		{
+			name: "Not found",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{}, errs.ErrURLNotFound)
+			},
+			want: want{statusCode: http.StatusNotFound},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			tt.mockSetup(mockRepo)
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodGet, "/qxDvSD/qr"+tt.query, nil)
+			r.Host = "localhost:8080"
+			if tt.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "qxDvSD")
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			app.GetQRCode(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.want.statusCode, result.StatusCode)
+
+			if tt.want.contentType != "" {
+				assert.Equal(t, tt.want.contentType, result.Header.Get("Content-Type"))
+				assert.NotEmpty(t, result.Header.Get("ETag"))
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/MukizuL/shortener/internal/models"
+)
+
+// permanentCacheControl lets browsers and proxies remember permanent redirects for a day.
+const permanentCacheControl = "public, max-age=86400"
+
+// redirect sends the client to the original URL using the link's redirect type.
+// Permanent redirects are cacheable, temporary ones must be revalidated every time.
+// Links with a click limit or a password are never cached, as a cached redirect would bypass them.
+func redirect(w http.ResponseWriter, r *http.Request, link models.Link) {
+	code := link.StatusCode()
+
+	switch {
+	case link.Limited() || link.Protected():
+		w.Header().Set("Cache-Control", "no-store")
+	case code == http.StatusMovedPermanently || code == http.StatusPermanentRedirect:
+		w.Header().Set("Cache-Control", permanentCacheControl)
+	default:
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+
+	// A form with the password was posted, the browser must follow with GET.
+	if r.Method == http.MethodPost {
+		code = http.StatusSeeOther
+	}
+
+	http.Redirect(w, r, link.OriginalURL, code)
+}
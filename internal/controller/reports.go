@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/go-chi/chi/v5"
+)
+
+// ReportLink godoc
+//
+//	@Summary		Reports an abusive link
+//	@Description	Files an abuse report for moderators to review. Accepts a JSON body or a posted HTML form.
+//	@Description	Once enough distinct visitors report a link within the report window, it is disabled
+//	@Description	until a moderator reviews the reports.
+//	@Tags			default
+//	@Accept			application/json,application/x-www-form-urlencoded
+//	@Produce		application/json
+//	@Param			id		path	string				true	"Short URL ID"
+//	@Param			Report	body	dto.ReportRequest	true	"Reason and optional contact"
+//	@Success		202
+//	@Failure		400	{object}	dto.ResponseWrapper	"No reason, too long reason or contact"
+//	@Failure		404	{object}	dto.ResponseWrapper	"URL not found"
+//	@Failure		410	{object}	dto.ResponseWrapper	"URL deleted"
+//	@Failure		500	{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/{id}/report [post]
+func (c Controller) ReportLink(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	req, err := decodeReport(r)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	err = c.service.ReportLink(ctx, c.urls.Origin(r).Host, chi.URLParam(r, "id"), contextI.ClientIPFrom(ctx), req.Reason, req.Contact)
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// GetReports godoc
+//
+//	@Summary		Returns the abuse report queue
+//	@Description	For moderators: admin users and clients from the trusted subnet.
+//	@Tags			admin
+//	@Produce		application/json
+//	@Param			Cookie	header		string				false	"Cookie with access token of an admin"
+//	@Param			status	query		string				false	"pending (default), approved or dismissed"
+//	@Success		200		{object}	[]dto.AbuseReport	"Reports, oldest first"
+//	@Failure		400		{object}	dto.ResponseWrapper	"Unknown status"
+//	@Failure		401		{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403		{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/reports [get]
+func (c Controller) GetReports(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	reports, err := c.service.Reports(ctx, models.ReportStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, c.abuseReports(c.urls.Origin(r), reports))
+}
+
+// ApproveReport godoc
+//
+//	@Summary		Approves an abuse report
+//	@Description	For moderators: admin users and clients from the trusted subnet. Disables the reported link
+//	@Description	and approves every pending report about it. The reason of the report is shown to visitors
+//	@Description	if the takedown has none. The body may be omitted.
+//	@Tags			admin
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			Cookie		header	string				false	"Cookie with access token of an admin"
+//	@Param			reportID	path	string				true	"Report ID"
+//	@Param			Takedown	body	dto.TakedownRequest	false	"Status and reason"
+//	@Success		204
+//	@Failure		400	{object}	dto.ResponseWrapper	"Malformed takedown"
+//	@Failure		401	{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403	{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		404	{object}	dto.ResponseWrapper	"Report or link not found"
+//	@Failure		409	{object}	dto.ResponseWrapper	"Report is already resolved"
+//	@Failure		500	{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/reports/{reportID}/approve [post]
+func (c Controller) ApproveReport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	var req dto.TakedownRequest
+	if r.ContentLength != 0 {
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+	}
+
+	err := c.service.ApproveReport(ctx, chi.URLParam(r, "reportID"), models.Takedown{Status: req.Status, Reason: req.Reason})
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// DismissReport godoc
+//
+//	@Summary		Dismisses an abuse report
+//	@Description	For moderators: admin users and clients from the trusted subnet. Dismisses every pending report
+//	@Description	about the reported link. A link disabled by reports is enabled again.
+//	@Tags			admin
+//	@Produce		application/json
+//	@Param			Cookie		header	string	false	"Cookie with access token of an admin"
+//	@Param			reportID	path	string	true	"Report ID"
+//	@Success		204
+//	@Failure		401	{object}	dto.ResponseWrapper	"No access token"
+//	@Failure		403	{object}	dto.ResponseWrapper	"User is not an admin"
+//	@Failure		404	{object}	dto.ResponseWrapper	"Report not found"
+//	@Failure		409	{object}	dto.ResponseWrapper	"Report is already resolved"
+//	@Failure		500	{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/admin/reports/{reportID}/dismiss [post]
+func (c Controller) DismissReport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	err := c.service.DismissReport(ctx, chi.URLParam(r, "reportID"))
+	if err != nil {
+		writeReportError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeReport reads a report from a JSON body or a posted form.
+func decodeReport(r *http.Request) (dto.ReportRequest, error) {
+	var req dto.ReportRequest
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "application/x-www-form-urlencoded" || mediaType == "multipart/form-data" {
+		req.Reason = r.PostFormValue("reason")
+		req.Contact = r.PostFormValue("contact")
+
+		return req, nil
+	}
+
+	err := json.NewDecoder(r.Body).Decode(&req)
+
+	return req, err
+}
+
+// abuseReports describes reports for moderators.
+func (c Controller) abuseReports(o proxy.Origin, reports []models.Report) []dto.AbuseReport {
+	result := make([]dto.AbuseReport, 0, len(reports))
+	for _, report := range reports {
+		link := models.Link{ShortURL: report.ShortURL, Domain: report.Domain}
+
+		result = append(result, dto.AbuseReport{
+			ID:        report.ID,
+			ShortURL:  c.service.ShortURL(o, link),
+			Domain:    c.service.DomainName(link),
+			Reason:    report.Reason,
+			Contact:   report.Contact,
+			Reporter:  report.Reporter,
+			Status:    string(report.Status),
+			CreatedAt: report.CreatedAt,
+		})
+	}
+
+	return result
+}
+
+func writeReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errs.ErrEmptyReportReason), errors.Is(err, errs.ErrReportTooLong),
+		errors.Is(err, errs.ErrUnknownReportStatus), errors.Is(err, errs.ErrInvalidTakedownStatus):
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": err.Error()})
+	case errors.Is(err, errs.ErrURLNotFound), errors.Is(err, errs.ErrReportNotFound):
+		helpers.WriteJSON(w, http.StatusNotFound, dto.ResponseWrapper{"error": http.StatusText(http.StatusNotFound)})
+	case errors.Is(err, errs.ErrGone):
+		helpers.WriteJSON(w, http.StatusGone, dto.ResponseWrapper{"error": http.StatusText(http.StatusGone)})
+	case errors.Is(err, errs.ErrReportResolved):
+		helpers.WriteJSON(w, http.StatusConflict, dto.ResponseWrapper{"error": err.Error()})
+	default:
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+	}
+}
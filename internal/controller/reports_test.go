@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/service"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApplication_ReportLink(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		mockSetup   func(m *mockstorage.MockRepo)
+		statusCode  int
+	}{
+		{
+			name:        "JSON",
+			contentType: "application/json",
+			body:        `{"reason":"Phishing","contact":"visitor@example.com"}`,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{ShortURL: "qxDvSD"}, nil)
+				m.EXPECT().CreateReport(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil)
+			},
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:        "Form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "reason=Phishing",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{ShortURL: "qxDvSD"}, nil)
+				m.EXPECT().CreateReport(gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil)
+			},
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:        "No reason",
+			contentType: "application/json",
+			body:        `{"contact":"visitor@example.com"}`,
+			statusCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "Malformed body",
+			contentType: "application/json",
+			body:        `reason`,
+			statusCode:  http.StatusBadRequest,
+		},
+		{
+			name:        "Unknown link",
+			contentType: "application/json",
+			body:        `{"reason":"Phishing"}`,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{}, errs.ErrURLNotFound)
+			},
+			statusCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockRepo)
+			}
+
+			app := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodPost, "/qxDvSD/report", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "qxDvSD")
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			w := httptest.NewRecorder()
+			app.ReportLink(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+		})
+	}
+}
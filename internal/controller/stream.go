@@ -0,0 +1,253 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/proxy"
+	pb "github.com/MukizuL/shortener/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+const (
+	// streamChunkSize is the number of items created in one transaction.
+	streamChunkSize = 500
+	// streamChunkTimeout limits creation of one chunk.
+	streamChunkTimeout = 3 * time.Second
+	// streamIdleTimeout is how long a stream may wait for the client to send or receive a chunk.
+	streamIdleTimeout = 30 * time.Second
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+// streamBatch reads items with recv until io.EOF and creates them in chunks, passing results of each committed chunk to send.
+// The next chunk is read only after the previous one was sent, so a slow client slows the stream down instead of
+// piling results up in memory. Items over the cap are not read, the ones before them are still created.
+func (c Controller) streamBatch(ctx context.Context, userID string, origin proxy.Origin, opts models.URLOptions,
+	recv func() (dto.BatchRequest, error), send func([]dto.BatchResponse) error) error {
+	chunk := make([]dto.BatchRequest, 0, streamChunkSize)
+
+	var count int
+	var recvErr error
+	for recvErr == nil {
+		var item dto.BatchRequest
+		item, recvErr = recv()
+		if recvErr == nil {
+			count++
+			if c.streamMaxItems > 0 && count > c.streamMaxItems {
+				recvErr = errs.ErrTooManyItems
+			} else {
+				chunk = append(chunk, item)
+			}
+		}
+
+		if len(chunk) < streamChunkSize && (recvErr == nil || len(chunk) == 0) {
+			continue
+		}
+
+		chunkCtx, cancel := context.WithTimeout(ctx, streamChunkTimeout)
+		results, err := c.service.CreateBatch(chunkCtx, userID, origin, chunk, opts)
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		err = send(results)
+		if err != nil {
+			return err
+		}
+
+		chunk = chunk[:0]
+	}
+
+	if errors.Is(recvErr, io.EOF) {
+		return nil
+	}
+
+	return recvErr
+}
+
+// StreamCreateShortURL godoc
+//
+//	@Summary		Creates short URLs from a stream
+//	@Description	Takes one dto.BatchRequest per line and streams back one dto.BatchResponse per line
+//	@Description	as items are committed in chunks of 500. Results have the same statuses as in a batch.
+//	@Description	An error after the response has started is reported as the last line {"error": "..."},
+//	@Description	items before it are created.
+//	@Tags			json
+//	@Accept			application/x-ndjson
+//	@Produce		application/x-ndjson
+//	@Param			Cookie	header		string				false	"Cookie with access token"
+//	@Param			URL		body		dto.BatchRequest	true	"URLs to shorten, one per line"
+//	@Param			workspace_id	query	string		false	"Workspace to create the links in"
+//	@Param			domain	query		string		false	"Short domain to create the links on, the requested one by default"
+//	@Success		200		{object}	dto.BatchResponse	"Results, one per line"
+//	@Header			200		{string}	Set-cookie			"Access token"
+//	@Failure		403		{object}	dto.ResponseWrapper	"No edit access to workspace"
+//	@Failure		415		{object}	dto.ResponseWrapper	"Content type is not application/x-ndjson"
+//	@Failure		422		{object}	dto.ResponseWrapper	"Unknown domain"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/shorten/stream [post]
+func (c Controller) StreamCreateShortURL(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != ndjsonContentType {
+		helpers.WriteJSON(w, http.StatusUnsupportedMediaType, dto.ResponseWrapper{"error": http.StatusText(http.StatusUnsupportedMediaType)})
+		return
+	}
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	opts := models.URLOptions{WorkspaceID: r.URL.Query().Get("workspace_id"), Domain: r.URL.Query().Get("domain")}
+
+	err := c.service.CheckDomain(opts.Domain)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusUnprocessableEntity, dto.ResponseWrapper{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	err = c.service.CheckWorkspaceEditor(ctx, opts.WorkspaceID, p.UserID)
+	cancel()
+	if err != nil {
+		if errors.Is(err, errs.ErrForbidden) {
+			helpers.WriteJSON(w, http.StatusForbidden, dto.ResponseWrapper{"error": http.StatusText(http.StatusForbidden)})
+			return
+		}
+
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	origin := c.urls.Origin(r)
+
+	rc := http.NewResponseController(w)
+	// HTTP/2 is always full duplex and doesn't support turning it on.
+	err = rc.EnableFullDuplex()
+	if err != nil {
+		c.logger.Debug("Error enabling full duplex", zap.Error(err))
+	}
+	c.extendDeadlines(rc)
+
+	w.Header().Set("Content-Type", ndjsonContentType)
+	w.WriteHeader(http.StatusOK)
+
+	dec := json.NewDecoder(r.Body)
+	enc := json.NewEncoder(w)
+
+	recv := func() (dto.BatchRequest, error) {
+		var item dto.BatchRequest
+		err := dec.Decode(&item)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return item, errs.ErrMalformedItem
+		}
+
+		return item, err
+	}
+
+	send := func(results []dto.BatchResponse) error {
+		for _, result := range results {
+			err := enc.Encode(result)
+			if err != nil {
+				return err
+			}
+		}
+
+		err := rc.Flush()
+		if err != nil {
+			return err
+		}
+		c.extendDeadlines(rc)
+
+		return nil
+	}
+
+	err = c.streamBatch(r.Context(), p.UserID, origin, opts, recv, send)
+	if err != nil {
+		c.logger.Debug("Stream aborted", zap.Error(err))
+
+		msg := http.StatusText(http.StatusInternalServerError)
+		if errors.Is(err, errs.ErrTooManyItems) || errors.Is(err, errs.ErrMalformedItem) {
+			msg = err.Error()
+		}
+
+		_ = enc.Encode(dto.ResponseWrapper{"error": msg})
+	}
+}
+
+// extendDeadlines moves the read and write deadlines of a streamed response streamIdleTimeout ahead,
+// server timeouts are meant for whole requests. If that fails the stream is cut by them, which is logged.
+func (c Controller) extendDeadlines(rc *http.ResponseController) {
+	deadline := time.Now().Add(streamIdleTimeout)
+
+	err := errors.Join(rc.SetReadDeadline(deadline), rc.SetWriteDeadline(deadline))
+	if err != nil {
+		c.logger.Warn("Error extending stream deadlines", zap.Error(err))
+	}
+}
+
+// CreateStreamGRPC creates short URLs from a stream with the same semantics as StreamCreateShortURL.
+func (c Controller) CreateStreamGRPC(stream grpc.BidiStreamingServer[pb.BatchRequest, pb.BatchResponse]) error {
+	ctx := stream.Context()
+
+	p, err := grpcPrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := models.URLOptions{WorkspaceID: grpcWorkspaceID(ctx), Domain: grpcDomain(ctx)}
+
+	err = c.service.CheckDomain(opts.Domain)
+	if err != nil {
+		return serviceStatus(err)
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	err = c.service.CheckWorkspaceEditor(checkCtx, opts.WorkspaceID, p.UserID)
+	cancel()
+	if err != nil {
+		return serviceStatus(err)
+	}
+
+	recv := func() (dto.BatchRequest, error) {
+		in, err := stream.Recv()
+		if err != nil {
+			return dto.BatchRequest{}, err
+		}
+
+		return dto.BatchRequest{CorrelationID: in.CorrelationId, OriginalURL: in.OriginalUrl}, nil
+	}
+
+	send := func(results []dto.BatchResponse) error {
+		for _, v := range results {
+			err := stream.Send(&pb.BatchResponse{
+				CorrelationId: v.CorrelationID,
+				ShortUrl:      v.ShortURL,
+				Status:        v.Status,
+				Error:         v.Error,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	err = c.streamBatch(ctx, p.UserID, grpcOrigin(ctx), opts, recv, send)
+	if err != nil {
+		return serviceStatus(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/service"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestApplication_StreamCreateShortURL(t *testing.T) {
+	created := func(id, shortURL string) dto.BatchResponse {
+		return dto.BatchResponse{CorrelationID: id, ShortURL: shortURL, Status: dto.BatchStatusCreated}
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		maxItems    int
+		mockStorage func(m *mockstorage.MockRepo)
+		statusCode  int
+		want        []string
+	}{
+		{
+			name:        "Correct working",
+			contentType: "application/x-ndjson",
+			body: `{"correlation_id":"1","original_url":"https://www.youtube1.com"}
+{"correlation_id":"2","original_url":"not a url"}
+{"correlation_id":"3","original_url":"https://www.youtube3.com"}
+`,
+			mockStorage: func(m *mockstorage.MockRepo) {
+				m.EXPECT().BatchCreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", gomock.Len(2), models.URLOptions{}).
+					Return([]dto.BatchResponse{
+						created("1", "http://localhost:8080/qxDvSD"),
+						created("3", "http://localhost:8080/qxDvSB"),
+					}, nil)
+			},
+			statusCode: http.StatusOK,
+			want: []string{
+				`{"correlation_id":"1","short_url":"http://localhost:8080/qxDvSD","status":"created"}`,
+				`{"correlation_id":"2","status":"invalid","error":"not a url"}`,
+				`{"correlation_id":"3","short_url":"http://localhost:8080/qxDvSB","status":"created"}`,
+			},
+		},
+		{
+			name:        "Over the cap",
+			contentType: "application/x-ndjson",
+			maxItems:    1,
+			body: `{"correlation_id":"1","original_url":"https://www.youtube1.com"}
+{"correlation_id":"2","original_url":"https://www.youtube2.com"}
+`,
+			mockStorage: func(m *mockstorage.MockRepo) {
+				m.EXPECT().BatchCreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", gomock.Len(1), models.URLOptions{}).
+					Return([]dto.BatchResponse{created("1", "http://localhost:8080/qxDvSD")}, nil)
+			},
+			statusCode: http.StatusOK,
+			want: []string{
+				`{"correlation_id":"1","short_url":"http://localhost:8080/qxDvSD","status":"created"}`,
+				`{"error":"too many items in a stream"}`,
+			},
+		},
+		{
+			name:        "Malformed line",
+			contentType: "application/x-ndjson",
+			body:        `{"correlation_id":"1","original_url":`,
+			statusCode:  http.StatusOK,
+			want: []string{
+				`{"error":"malformed stream item"}`,
+			},
+		},
+		{
+			name:        "Wrong content type",
+			contentType: "application/json",
+			body:        `[]`,
+			statusCode:  http.StatusUnsupportedMediaType,
+			want: []string{
+				`{"error":"Unsupported Media Type"}`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			if tt.mockStorage != nil {
+				tt.mockStorage(mockRepo)
+			}
+
+			c := newTestController(service.Params{Storage: mockRepo})
+			c.streamMaxItems = tt.maxItems
+
+			r := httptest.NewRequest(http.MethodPost, "/api/shorten/stream", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			r.Host = "localhost:8080"
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "user1"}))
+
+			w := httptest.NewRecorder()
+			c.StreamCreateShortURL(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+
+			lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+			require.Len(t, lines, len(tt.want))
+			for i := range lines {
+				assert.True(t, json.Valid([]byte(lines[i])))
+				assert.JSONEq(t, tt.want[i], lines[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/MukizuL/shortener/internal/models"
+	"go.uber.org/zap"
+)
+
+var takedownPage = template.Must(template.New("takedown").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="robots" content="noindex">
+	<title>{{.Title}}</title>
+</head>
+<body>
+	<h1>This link has been disabled</h1>
+	{{if .Reason}}<p>{{.Reason}}</p>{{end}}
+</body>
+</html>
+`))
+
+type takedownPageData struct {
+	Title  string
+	Reason string
+}
+
+// renderTakedown answers a visitor of a disabled link with the takedown status and reason.
+func (c Controller) renderTakedown(w http.ResponseWriter, t models.Takedown) {
+	// The link may be enabled again, the answer must not be remembered.
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(t.Status)
+
+	err := takedownPage.Execute(w, takedownPageData{Title: http.StatusText(t.Status), Reason: t.Reason})
+	if err != nil {
+		c.logger.Error("Error rendering takedown page", zap.Error(err))
+	}
+}
@@ -0,0 +1,227 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// CreateWebhook godoc
+//
+//	@Summary		Subscribes to events about the user's links
+//	@Description	Events are link.created, link.clicked and link.deleted, all of them if none are given.
+//	@Description	Deliveries are POSTed with X-Webhook-Signature: sha256= followed by the hex HMAC-SHA256
+//	@Description	of "<X-Webhook-Timestamp>.<body>" keyed with the secret, which is only returned here.
+//	@Description	Failed deliveries are retried with exponential backoff, then dead-lettered.
+//	@Tags			webhooks
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			Cookie	header		string				false	"Cookie with access token"
+//	@Param			Webhook	body		dto.WebhookRequest	true	"URL and events"
+//	@Success		201		{object}	dto.Webhook			"Created webhook with its secret"
+//	@Failure		400		{object}	dto.ResponseWrapper	"Malformed URL, private address or unknown event"
+//	@Failure		409		{object}	dto.ResponseWrapper	"Too many webhooks"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/user/webhooks [post]
+func (c Controller) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	var req dto.WebhookRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	hook, err := c.service.CreateWebhook(ctx, p.UserID, req.URL, req.Events)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusCreated, webhookResponse(hook))
+}
+
+// GetWebhooks godoc
+//
+//	@Summary	Returns the user's webhooks
+//	@Tags		webhooks
+//	@Produce	application/json
+//	@Param		Cookie	header		string				true	"Cookie with access token"
+//	@Success	200		{object}	[]dto.Webhook		"Webhooks without secrets, oldest first"
+//	@Success	204		"No webhooks"
+//	@Failure	500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router		/api/user/webhooks [get]
+func (c Controller) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	hooks, err := c.service.Webhooks(ctx, p.UserID)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	if len(hooks) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	result := make([]dto.Webhook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, webhookResponse(hook))
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, result)
+}
+
+// DeleteWebhook godoc
+//
+//	@Summary	Deletes a webhook
+//	@Description	Deliveries still queued for it are dropped.
+//	@Tags		webhooks
+//	@Param		Cookie		header	string	true	"Cookie with access token"
+//	@Param		webhookID	path	string	true	"Webhook ID"
+//	@Success	204
+//	@Failure	404	{object}	dto.ResponseWrapper	"Webhook not found"
+//	@Failure	500	{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router		/api/user/webhooks/{webhookID} [delete]
+func (c Controller) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	err := c.service.DeleteWebhook(ctx, p.UserID, chi.URLParam(r, "webhookID"))
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetWebhookDeliveries godoc
+//
+//	@Summary	Returns the newest deliveries of a webhook
+//	@Description	Dead deliveries failed every attempt and are only sent again by redelivery.
+//	@Tags		webhooks
+//	@Produce	application/json
+//	@Param		Cookie		header		string					true	"Cookie with access token"
+//	@Param		webhookID	path		string					true	"Webhook ID"
+//	@Param		status		query		string					false	"pending, delivered or dead, any if omitted"
+//	@Success	200			{object}	[]dto.WebhookDelivery	"Deliveries, newest first"
+//	@Success	204			"No deliveries"
+//	@Failure	400			{object}	dto.ResponseWrapper		"Unknown status"
+//	@Failure	404			{object}	dto.ResponseWrapper		"Webhook not found"
+//	@Failure	500			{object}	dto.ResponseWrapper		"Internal Server Error"
+//	@Router		/api/user/webhooks/{webhookID}/deliveries [get]
+func (c Controller) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	deliveries, err := c.service.WebhookDeliveries(ctx, p.UserID, chi.URLParam(r, "webhookID"),
+		models.DeliveryStatus(r.URL.Query().Get("status")))
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	if len(deliveries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	result := make([]dto.WebhookDelivery, 0, len(deliveries))
+	for _, d := range deliveries {
+		delivery := dto.WebhookDelivery{
+			ID:             d.ID,
+			Event:          d.Event,
+			Payload:        d.Payload,
+			Status:         string(d.Status),
+			Attempts:       d.Attempts,
+			LastError:      d.LastError,
+			ResponseStatus: d.ResponseStatus,
+			CreatedAt:      d.CreatedAt,
+			DeliveredAt:    d.DeliveredAt,
+		}
+
+		if d.Status == models.DeliveryPending {
+			delivery.NextAttemptAt = d.NextAttemptAt
+		}
+
+		result = append(result, delivery)
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, result)
+}
+
+// RedeliverWebhook godoc
+//
+//	@Summary	Sends a delivery again
+//	@Description	The delivery is queued with fresh attempts whatever its status, dead ones included.
+//	@Description	Receivers may deduplicate on X-Webhook-Delivery, it stays the same.
+//	@Tags		webhooks
+//	@Param		Cookie		header	string	true	"Cookie with access token"
+//	@Param		webhookID	path	string	true	"Webhook ID"
+//	@Param		deliveryID	path	string	true	"Delivery ID"
+//	@Success	202
+//	@Failure	404	{object}	dto.ResponseWrapper	"Delivery not found"
+//	@Failure	500	{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router		/api/user/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver [post]
+func (c Controller) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	err := c.service.Redeliver(ctx, p.UserID, chi.URLParam(r, "webhookID"), chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func webhookResponse(hook models.Webhook) dto.Webhook {
+	return dto.Webhook{
+		ID:        hook.ID,
+		URL:       hook.URL,
+		Events:    hook.Events,
+		Secret:    hook.Secret,
+		CreatedAt: hook.CreatedAt,
+	}
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errs.ErrMalformedWebhookURL), errors.Is(err, errs.ErrPrivateAddress),
+		errors.Is(err, errs.ErrUnknownWebhookEvent), errors.Is(err, errs.ErrUnknownDeliveryStatus):
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": err.Error()})
+	case errors.Is(err, errs.ErrWebhookNotFound), errors.Is(err, errs.ErrDeliveryNotFound):
+		helpers.WriteJSON(w, http.StatusNotFound, dto.ResponseWrapper{"error": http.StatusText(http.StatusNotFound)})
+	case errors.Is(err, errs.ErrTooManyWebhooks):
+		helpers.WriteJSON(w, http.StatusConflict, dto.ResponseWrapper{"error": err.Error()})
+	case errors.Is(err, errs.ErrWebhooksUnavailable):
+		helpers.WriteJSON(w, http.StatusServiceUnavailable, dto.ResponseWrapper{"error": err.Error()})
+	default:
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+	}
+}
@@ -0,0 +1,177 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// CreateWorkspace godoc
+//
+//	@Summary		Creates a workspace
+//	@Description	The user becomes the owner of the new workspace.
+//	@Tags			workspaces
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			Cookie		header		string					false	"Cookie with access token"
+//	@Param			Workspace	body		dto.WorkspaceRequest	true	"Workspace"
+//	@Success		201			{object}	dto.Workspace			"Created workspace"
+//	@Failure		400			{object}	dto.ResponseWrapper		"Empty name"
+//	@Failure		500			{object}	dto.ResponseWrapper		"Internal Server Error"
+//	@Router			/api/workspaces [post]
+func (c Controller) CreateWorkspace(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	var req dto.WorkspaceRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	ID, err := c.service.CreateWorkspace(ctx, p.UserID, req.Name)
+	if err != nil {
+		if errors.Is(err, errs.ErrEmptyWorkspaceName) {
+			helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": err.Error()})
+			return
+		}
+
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusCreated, dto.Workspace{ID: ID, Name: req.Name, Role: string(models.RoleOwner)})
+}
+
+// GetWorkspaces godoc
+//
+//	@Summary	Returns workspaces the user is a member of
+//	@Tags		workspaces
+//	@Produce	application/json
+//	@Param		Cookie	header		string			true	"Cookie with access token"
+//	@Success	200		{object}	[]dto.Workspace	"Array of workspaces"
+//	@Failure	500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router		/api/workspaces [get]
+func (c Controller) GetWorkspaces(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	data, err := c.service.UserWorkspaces(ctx, p.UserID)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	if len(data) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	helpers.WriteJSON(w, http.StatusOK, data)
+}
+
+// AddWorkspaceMember godoc
+//
+//	@Summary		Adds a member to a workspace
+//	@Description	Only the workspace owner may add members or change their roles. The last owner can't be demoted.
+//	@Tags			workspaces
+//	@Accept			application/json
+//	@Produce		application/json
+//	@Param			Cookie	header		string				true	"Cookie with access token"
+//	@Param			id		path		string				true	"Workspace ID"
+//	@Param			Member	body		dto.MemberRequest	true	"Member"
+//	@Success		204
+//	@Failure		400		{object}	dto.ResponseWrapper	"Wrong user ID or role"
+//	@Failure		403		{object}	dto.ResponseWrapper	"User is not the owner"
+//	@Failure		404		{object}	dto.ResponseWrapper	"Workspace not found"
+//	@Failure		409		{object}	dto.ResponseWrapper	"Last owner of the workspace"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/workspaces/{id}/members [post]
+func (c Controller) AddWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	workspaceID := chi.URLParam(r, "id")
+
+	var req dto.MemberRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	err = c.service.AddWorkspaceMember(ctx, p.UserID, workspaceID, req.UserID, models.Role(req.Role))
+	if err != nil {
+		if errors.Is(err, errs.ErrUnknownRole) || errors.Is(err, errs.ErrInvalidUserID) {
+			helpers.WriteJSON(w, http.StatusBadRequest, dto.ResponseWrapper{"error": "wrong user id or role"})
+			return
+		}
+
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RemoveWorkspaceMember godoc
+//
+//	@Summary		Removes a member from a workspace
+//	@Description	Only the workspace owner may remove members. The last owner can't be removed.
+//	@Tags			workspaces
+//	@Produce		application/json
+//	@Param			Cookie	header		string	true	"Cookie with access token"
+//	@Param			id		path		string	true	"Workspace ID"
+//	@Param			userID	path		string	true	"Member user ID"
+//	@Success		204
+//	@Failure		403		{object}	dto.ResponseWrapper	"User is not the owner"
+//	@Failure		404		{object}	dto.ResponseWrapper	"Workspace or member not found"
+//	@Failure		409		{object}	dto.ResponseWrapper	"Last owner of the workspace"
+//	@Failure		500		{object}	dto.ResponseWrapper	"Internal Server Error"
+//	@Router			/api/workspaces/{id}/members/{userID} [delete]
+func (c Controller) RemoveWorkspaceMember(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 3*time.Second)
+	defer cancel()
+
+	workspaceID := chi.URLParam(r, "id")
+	memberID := chi.URLParam(r, "userID")
+
+	p, _ := contextI.PrincipalFrom(r.Context())
+
+	err := c.service.RemoveWorkspaceMember(ctx, p.UserID, workspaceID, memberID)
+	if err != nil {
+		writeWorkspaceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeWorkspaceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, errs.ErrForbidden):
+		helpers.WriteJSON(w, http.StatusForbidden, dto.ResponseWrapper{"error": http.StatusText(http.StatusForbidden)})
+	case errors.Is(err, errs.ErrWorkspaceNotFound):
+		helpers.WriteJSON(w, http.StatusNotFound, dto.ResponseWrapper{"error": http.StatusText(http.StatusNotFound)})
+	case errors.Is(err, errs.ErrLastOwner):
+		helpers.WriteJSON(w, http.StatusConflict, dto.ResponseWrapper{"error": err.Error()})
+	default:
+		helpers.WriteJSON(w, http.StatusInternalServerError, dto.ResponseWrapper{"error": http.StatusText(http.StatusInternalServerError)})
+	}
+}
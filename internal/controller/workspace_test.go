@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/service"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	pb "github.com/MukizuL/shortener/proto"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestApplication_CreateShortURLJSONInWorkspace(t *testing.T) {
+	const workspaceID = "6b1d0c1e-7d3f-4a55-9b43-1a8c2f0e9d11"
+
+	tests := []struct {
+		name       string
+		mockSetup  func(m *mockstorage.MockRepo)
+		wantStatus int
+	}{
+		{
+			name: "Editor creates link",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(models.RoleEditor, nil)
+				m.EXPECT().
+					CreateShortURL(gomock.Any(), "user1", "http://localhost:8080/", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{WorkspaceID: workspaceID}).
+					Return("http://localhost:8080/qxDvSD", nil)
+			},
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name: "Viewer can't create link",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(models.RoleViewer, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Not a member",
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(models.Role(""), errs.ErrForbidden)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			tt.mockSetup(mockRepo)
+
+			c := newTestController(service.Params{Storage: mockRepo})
+
+			body := `{"url":"https://www.youtube.com","workspace_id":"` + workspaceID + `"}`
+			r := httptest.NewRequest(http.MethodPost, "/api/shorten", strings.NewReader(body))
+			r.Host = "localhost:8080"
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "user1"}))
+
+			w := httptest.NewRecorder()
+			c.CreateShortURLJSON(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, result.StatusCode)
+		})
+	}
+}
+
+func TestApplication_AddWorkspaceMember(t *testing.T) {
+	const (
+		workspaceID = "6b1d0c1e-7d3f-4a55-9b43-1a8c2f0e9d11"
+		memberID    = "0f5f6a3e-2c1b-4e8d-8d7a-5b9c3e2f1a00"
+	)
+
+	tests := []struct {
+		name       string
+		body       string
+		mockSetup  func(m *mockstorage.MockRepo)
+		wantStatus int
+	}{
+		{
+			name: "Owner adds editor",
+			body: `{"user_id":"` + memberID + `","role":"editor"}`,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(models.RoleOwner, nil)
+				m.EXPECT().AddWorkspaceMember(gomock.Any(), workspaceID, memberID, models.RoleEditor).Return(nil)
+			},
+			wantStatus: http.StatusNoContent,
+		},
+		{
+			name: "Editor can't add members",
+			body: `{"user_id":"` + memberID + `","role":"viewer"}`,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(models.RoleEditor, nil)
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "Last owner can't be demoted",
+			body: `{"user_id":"` + memberID + `","role":"editor"}`,
+			mockSetup: func(m *mockstorage.MockRepo) {
+				m.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(models.RoleOwner, nil)
+				m.EXPECT().AddWorkspaceMember(gomock.Any(), workspaceID, memberID, models.RoleEditor).Return(errs.ErrLastOwner)
+			},
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "Unknown role",
+			body:       `{"user_id":"` + memberID + `","role":"admin"}`,
+			mockSetup:  func(m *mockstorage.MockRepo) {},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			tt.mockSetup(mockRepo)
+
+			c := newTestController(service.Params{Storage: mockRepo})
+
+			r := httptest.NewRequest(http.MethodPost, "/api/workspaces/"+workspaceID+"/members", strings.NewReader(tt.body))
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", workspaceID)
+
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+			r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: "user1"}))
+
+			w := httptest.NewRecorder()
+			c.AddWorkspaceMember(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, result.StatusCode)
+		})
+	}
+}
+
+func TestApplication_RemoveWorkspaceMemberGRPC(t *testing.T) {
+	const (
+		workspaceID = "6b1d0c1e-7d3f-4a55-9b43-1a8c2f0e9d11"
+		memberID    = "0f5f6a3e-2c1b-4e8d-8d7a-5b9c3e2f1a00"
+	)
+
+	tests := []struct {
+		name     string
+		mockErr  error
+		wantCode codes.Code
+	}{
+		{
+			name:     "Member removed",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "Last owner",
+			mockErr:  errs.ErrLastOwner,
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(models.RoleOwner, nil)
+			mockRepo.EXPECT().RemoveWorkspaceMember(gomock.Any(), workspaceID, memberID).Return(tt.mockErr)
+
+			c := newTestController(service.Params{Storage: mockRepo})
+
+			ctx := contextI.WithPrincipal(context.Background(), contextI.Principal{UserID: "user1", AccessToken: "token"})
+			resp, err := c.RemoveWorkspaceMemberGRPC(ctx, &pb.RemoveWorkspaceMemberRequest{WorkspaceId: workspaceID, UserId: memberID})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if err == nil {
+				assert.Equal(t, "token", resp.AccessToken)
+			}
+		})
+	}
+}
+
+func TestApplication_GetWorkspacesGRPC(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockstorage.NewMockRepo(ctrl)
+	mockRepo.EXPECT().GetUserWorkspaces(gomock.Any(), "user1").
+		Return([]dto.Workspace{{ID: "6b1d0c1e-7d3f-4a55-9b43-1a8c2f0e9d11", Name: "Marketing", Role: "editor"}}, nil)
+
+	c := newTestController(service.Params{Storage: mockRepo})
+
+	ctx := contextI.WithPrincipal(context.Background(), contextI.Principal{UserID: "user1"})
+	resp, err := c.GetWorkspacesGRPC(ctx, &pb.GetWorkspacesRequest{})
+	require.NoError(t, err)
+
+	require.Len(t, resp.Workspaces, 1)
+	assert.Equal(t, "Marketing", resp.Workspaces[0].Name)
+	assert.Equal(t, "editor", resp.Workspaces[0].Role)
+}
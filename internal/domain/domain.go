@@ -0,0 +1,130 @@
+// Package domain holds the short domains links are created on.
+//
+// Links on the default domain, the first configured one, are stored with an empty domain, so links created
+// before several domains were configured keep resolving. Requests to hosts that are not configured are
+// served from the default domain as well.
+package domain
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/errs"
+	"go.uber.org/fx"
+)
+
+// Registry is the list of short domains. A nil or empty Registry serves every link from the default domain,
+// which is whatever host the request was made to.
+type Registry struct {
+	domains []string
+}
+
+// New creates a Registry of domains, the first of them is the default.
+func New(domains ...string) (*Registry, error) {
+	r := &Registry{}
+
+	for _, d := range domains {
+		if d == "" || strings.ContainsAny(d, ":/?#@[] ") {
+			return nil, fmt.Errorf("%w: %q", errs.ErrMalformedDomain, d)
+		}
+
+		d = hostname(d)
+
+		if !slices.Contains(r.domains, d) {
+			r.domains = append(r.domains, d)
+		}
+	}
+
+	return r, nil
+}
+
+func newRegistry(cfg *config.Config) (*Registry, error) {
+	var domains []string
+	for _, d := range strings.Split(cfg.Domains, ",") {
+		if d = strings.TrimSpace(d); d != "" {
+			domains = append(domains, d)
+		}
+	}
+
+	return New(domains...)
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newRegistry)
+}
+
+// Names returns configured domains, the default one first.
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+
+	return slices.Clone(r.domains)
+}
+
+// Default returns the default domain, empty if none is configured.
+func (r *Registry) Default() string {
+	if r == nil || len(r.domains) == 0 {
+		return ""
+	}
+
+	return r.domains[0]
+}
+
+// Key returns the domain links requested on host are stored under.
+func (r *Registry) Key(host string) string {
+	if r == nil {
+		return ""
+	}
+
+	name := hostname(host)
+	if i := slices.Index(r.domains, name); i > 0 {
+		return name
+	}
+
+	return ""
+}
+
+// Select returns the domain a link requested on host is created on. An empty name chooses the domain of host,
+// otherwise name must be one of configured domains or errs.ErrUnknownDomain is returned.
+func (r *Registry) Select(name, host string) (string, error) {
+	if name == "" {
+		return r.Key(host), nil
+	}
+
+	if !r.Contains(name) {
+		return "", errs.ErrUnknownDomain
+	}
+
+	return r.Key(name), nil
+}
+
+// Name returns the host name of a domain key, the default domain for an empty key.
+func (r *Registry) Name(key string) string {
+	if key == "" {
+		return r.Default()
+	}
+
+	return key
+}
+
+// Contains reports whether host is one of configured domains.
+func (r *Registry) Contains(host string) bool {
+	if r == nil {
+		return false
+	}
+
+	return slices.Contains(r.domains, hostname(host))
+}
+
+// hostname returns lowercase host without port and trailing dot.
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	for _, d := range []string{"https://sho.rt", "sho.rt:8080", "sho.rt/s", "[::1]"} {
+		_, err := New(d)
+		assert.ErrorIs(t, err, errs.ErrMalformedDomain, d)
+	}
+
+	r, err := New("Sho.rt.", "brand.ly", "sho.rt")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sho.rt", "brand.ly"}, r.Names())
+}
+
+func TestRegistry(t *testing.T) {
+	r, err := New("sho.rt", "brand.ly")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		domain  string
+		host    string
+		want    string
+		wantErr error
+	}{
+		{name: "Default domain", host: "sho.rt", want: ""},
+		{name: "Domain of the request", host: "Brand.ly:443", want: "brand.ly"},
+		{name: "Unknown host", host: "localhost:8080", want: ""},
+		{name: "Chosen domain", domain: "brand.ly", host: "sho.rt", want: "brand.ly"},
+		{name: "Chosen default domain", domain: "sho.rt", host: "brand.ly", want: ""},
+		{name: "Unknown domain", domain: "evil.example", host: "sho.rt", wantErr: errs.ErrUnknownDomain},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Select(tt.domain, tt.host)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, "sho.rt", r.Name(""))
+	assert.Equal(t, "brand.ly", r.Name("brand.ly"))
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var r *Registry
+
+	key, err := r.Select("", "sho.rt")
+	require.NoError(t, err)
+	assert.Equal(t, "", key)
+	assert.Equal(t, "", r.Name(""))
+
+	_, err = r.Select("sho.rt", "sho.rt")
+	assert.ErrorIs(t, err, errs.ErrUnknownDomain)
+}
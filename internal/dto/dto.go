@@ -1,28 +1,51 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type ResponseWrapper map[string]interface{}
 
 // Request represents a URL shortening request.
 type Request struct {
-	FullURL string `json:"url"`
+	FullURL      string `json:"url"`
+	Domain       string `json:"domain,omitempty"`
+	WorkspaceID  string `json:"workspace_id,omitempty"`
+	Password     string `json:"password,omitempty"`
+	MaxClicks    int    `json:"max_clicks,omitempty"`
+	RedirectType int    `json:"redirect_type,omitempty"`
 }
 
 // BatchRequest represents a batch URL shortening request item.
 type BatchRequest struct {
 	CorrelationID string `json:"correlation_id"`
 	OriginalURL   string `json:"original_url"`
+	CanonicalURL  string `json:"-"`
 }
 
+// Batch item statuses.
+const (
+	BatchStatusCreated  = "created"
+	BatchStatusExisting = "existing"
+	BatchStatusInvalid  = "invalid"
+)
+
 // BatchResponse represents a batch URL shortening response item.
+// ShortURL is empty for invalid items, Error explains why the item is invalid.
 type BatchResponse struct {
 	CorrelationID string `json:"correlation_id"`
-	ShortURL      string `json:"short_url"`
+	ShortURL      string `json:"short_url,omitempty"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
 }
 
-// URLPair represents a pair of original and shortened URLs
+// URLPair represents a pair of original and shortened URLs. Domain is the short domain of the link,
+// empty when no short domains are configured.
 type URLPair struct {
 	ShortURL    string `json:"short_url"`
 	OriginalURL string `json:"original_url"`
+	Domain      string `json:"domain,omitempty"`
 }
 
 // Stats provides info on how many shortURLs and users in the system.
@@ -30,3 +53,150 @@ type Stats struct {
 	Urls  int `json:"urls"`
 	Users int `json:"users"`
 }
+
+// WorkspaceRequest represents a workspace creation request.
+type WorkspaceRequest struct {
+	Name string `json:"name"`
+}
+
+// MemberRequest represents a request to add a member to a workspace.
+type MemberRequest struct {
+	UserID string `json:"user_id"`
+	Role   string `json:"role"`
+}
+
+// Workspace represents a workspace the user is a member of.
+type Workspace struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+// LinkInfo describes a short link without following it.
+// Fields after Protected are only filled for the owner of the link and members of its workspace.
+type LinkInfo struct {
+	ShortURL       string    `json:"short_url"`
+	OriginalURL    string    `json:"original_url,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"`
+	Status         string    `json:"status"`
+	DisabledReason string    `json:"disabled_reason,omitempty"`
+	Protected      bool      `json:"protected"`
+	Owner          bool      `json:"owner"`
+	WorkspaceID    string    `json:"workspace_id,omitempty"`
+	MaxClicks      int       `json:"max_clicks,omitempty"`
+	ClicksLeft     int       `json:"clicks_left,omitempty"`
+	RedirectType   int       `json:"redirect_type,omitempty"`
+}
+
+// AdminLink describes a link of any user for moderators.
+type AdminLink struct {
+	ShortURL       string    `json:"short_url"`
+	Domain         string    `json:"domain,omitempty"`
+	OriginalURL    string    `json:"original_url"`
+	UserID         string    `json:"user_id"`
+	WorkspaceID    string    `json:"workspace_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at,omitzero"`
+	Protected      bool      `json:"protected"`
+	MaxClicks      int       `json:"max_clicks,omitempty"`
+	ClicksLeft     int       `json:"clicks_left,omitempty"`
+	DisabledStatus int       `json:"disabled_status,omitempty"`
+	DisabledReason string    `json:"disabled_reason,omitempty"`
+}
+
+// TakedownRequest represents a request to disable links. Status is 410 or 451, 410 if omitted.
+type TakedownRequest struct {
+	Status int    `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// TakedownResponse reports how many links a takedown changed.
+type TakedownResponse struct {
+	Disabled int `json:"disabled"`
+}
+
+// ReportRequest represents an abuse report a visitor files about a link.
+type ReportRequest struct {
+	Reason  string `json:"reason"`
+	Contact string `json:"contact,omitempty"`
+}
+
+// AbuseReport describes an abuse report in the review queue.
+type AbuseReport struct {
+	ID        string    `json:"id"`
+	ShortURL  string    `json:"short_url"`
+	Domain    string    `json:"domain,omitempty"`
+	Reason    string    `json:"reason"`
+	Contact   string    `json:"contact,omitempty"`
+	Reporter  string    `json:"reporter"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditEvent describes a recorded change for moderators. ShortURL and Domain are set for events about a link.
+type AuditEvent struct {
+	ID          string         `json:"id"`
+	Time        time.Time      `json:"time"`
+	Action      string         `json:"action"`
+	ActorID     string         `json:"actor_id,omitempty"`
+	ActorIP     string         `json:"actor_ip,omitempty"`
+	Transport   string         `json:"transport"`
+	RequestID   string         `json:"request_id,omitempty"`
+	ShortURL    string         `json:"short_url,omitempty"`
+	Domain      string         `json:"domain,omitempty"`
+	WorkspaceID string         `json:"workspace_id,omitempty"`
+	Before      map[string]any `json:"before,omitempty"`
+	After       map[string]any `json:"after,omitempty"`
+}
+
+// WebhookRequest represents a request to subscribe to events about the caller's links, to every event if Events is empty.
+type WebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"`
+}
+
+// Webhook describes a webhook subscription. Secret is only returned when the webhook is created.
+type Webhook struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// WebhookDelivery describes an attempted or queued delivery of an event to a webhook.
+type WebhookDelivery struct {
+	ID             string          `json:"id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at,omitzero"` // only for pending deliveries
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    time.Time       `json:"delivered_at,omitzero"`
+}
+
+// WebhookEvent is the body of a webhook delivery and the data of an activity stream event.
+type WebhookEvent struct {
+	Event string        `json:"event"`
+	Time  time.Time     `json:"time"`
+	Link  WebhookLink   `json:"link"`
+	Click *WebhookClick `json:"click,omitempty"` // only for link.clicked
+}
+
+// WebhookLink describes the link a webhook event is about.
+type WebhookLink struct {
+	ID          string `json:"id"`
+	Domain      string `json:"domain,omitempty"`
+	ShortURL    string `json:"short_url,omitempty"` // only for link.created
+	OriginalURL string `json:"original_url,omitempty"`
+	WorkspaceID string `json:"workspace_id,omitempty"`
+}
+
+// WebhookClick describes the visit of a link.clicked event.
+type WebhookClick struct {
+	Referer   string `json:"referer,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+}
@@ -14,4 +14,50 @@ var (
 	ErrRefreshingToken         = errors.New("error refreshing token")
 	ErrNoCert                  = errors.New("no certificate provided")
 	ErrNoPK                    = errors.New("no private key provided")
+	ErrWorkspaceNotFound       = errors.New("workspace is not present")
+	ErrForbidden               = errors.New("user has no access to workspace")
+	ErrUnknownRole             = errors.New("unknown workspace role")
+	ErrLastOwner               = errors.New("workspace must keep at least one owner")
+	ErrInvalidMaxClicks        = errors.New("max clicks must not be negative")
+	ErrInvalidRedirectType     = errors.New("redirect type must be one of 301, 302, 307, 308")
+	ErrInvalidQRFormat         = errors.New("qr code format must be png or svg")
+	ErrInvalidQRSize           = errors.New("qr code size must be between 64 and 1024")
+	ErrInvalidQRLevel          = errors.New("qr code error correction level must be one of L, M, Q, H")
+	ErrPrivateAddress          = errors.New("destination is a private, loopback or link-local address")
+	ErrSelfReferential         = errors.New("destination points to the shortener itself")
+	ErrDeniedDomain            = errors.New("destination domain is on the deny list")
+	ErrNotAllowedDomain        = errors.New("destination domain is not on the allow list")
+	ErrUnknownDedupeScope      = errors.New("dedupe scope must be one of global, per-user, none")
+	ErrClientCAWithoutTLS      = errors.New("client CA requires HTTPS to be enabled")
+	ErrTooManyItems            = errors.New("too many items in a stream")
+	ErrMalformedItem           = errors.New("malformed stream item")
+	ErrNotURL                  = errors.New("not a url")
+	ErrRejected                = errors.New("url is rejected")
+	ErrWrongPassword           = errors.New("wrong password")
+	ErrPasswordTooLong         = errors.New("password must be at most 72 bytes")
+	ErrTooManyAttempts         = errors.New("too many failed password attempts")
+	ErrEmptyWorkspaceName      = errors.New("workspace name is empty")
+	ErrInvalidUserID           = errors.New("user id is not a uuid")
+	ErrClicksUnavailable       = errors.New("link events are not available")
+	ErrUnknownDomain           = errors.New("domain is not one of the short domains")
+	ErrMalformedDomain         = errors.New("short domain must be a host name without scheme, port or path")
+	ErrDisabled                = errors.New("link is disabled by a moderator")
+	ErrInvalidTakedownStatus   = errors.New("takedown status must be 410 or 451")
+	ErrMalformedHost           = errors.New("host must be a domain name like example.com")
+	ErrEmptyLinkQuery          = errors.New("query must select links by ID, destination, host or user")
+	ErrReportNotFound          = errors.New("report is not present")
+	ErrEmptyReportReason       = errors.New("report must have a reason")
+	ErrReportTooLong           = errors.New("report reason or contact is too long")
+	ErrUnknownReportStatus     = errors.New("report status must be pending, approved or dismissed")
+	ErrReportResolved          = errors.New("report is already resolved")
+	ErrInvalidTimeRange        = errors.New("time range must end after it starts")
+	ErrMalformedTime           = errors.New("time must be in RFC 3339 format")
+	ErrInvalidLimit            = errors.New("limit must be a number from 1 to 1000")
+	ErrWebhookNotFound         = errors.New("webhook is not present")
+	ErrDeliveryNotFound        = errors.New("webhook delivery is not present")
+	ErrMalformedWebhookURL     = errors.New("webhook url must be an absolute http(s) url")
+	ErrUnknownWebhookEvent     = errors.New("webhook events must be link.created, link.clicked or link.deleted")
+	ErrUnknownDeliveryStatus   = errors.New("delivery status must be pending, delivered or dead")
+	ErrTooManyWebhooks         = errors.New("too many webhooks")
+	ErrWebhooksUnavailable     = errors.New("webhooks are not available")
 )
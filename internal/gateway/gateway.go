@@ -0,0 +1,237 @@
+// Package gateway serves the REST mapping of the gRPC API generated from the HTTP annotations in proto/url.proto.
+//
+// The gateway talks to the gRPC server over an in-memory listener, so calls go through the same interceptors
+// as native gRPC calls.
+package gateway
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/config"
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/MukizuL/shortener/internal/requestid"
+	pb "github.com/MukizuL/shortener/proto"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	// Prefix is the path prefix of every gateway route.
+	Prefix = "/v1"
+
+	bufferSize = 1 << 20
+	network    = "bufconn"
+)
+
+// forwardingHeaders describe the original request, the gateway replaces them with what it resolved.
+var forwardingHeaders = []string{"Forwarded", "X-Forwarded-For", "X-Forwarded-Host", "X-Forwarded-Proto", "X-Real-IP"}
+
+type originKey struct{}
+
+// Gateway is an http.Handler serving the REST API. The gRPC server must serve Listener for it to work.
+type Gateway struct {
+	lis     *bufconn.Listener
+	conn    *grpc.ClientConn
+	mux     *runtime.ServeMux
+	proxies *proxy.Trust
+}
+
+// New creates a gateway that forwards calls to a server serving its listener.
+// Forwarding headers are taken into account for requests from proxies.
+func New(ctx context.Context, proxies *proxy.Trust, logger *zap.Logger) (*Gateway, error) {
+	g := &Gateway{lis: bufconn.Listen(bufferSize), proxies: proxies}
+
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return g.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	g.conn = conn
+	g.mux = runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(headerMatcher),
+		runtime.WithMetadata(g.annotate),
+		runtime.WithErrorHandler(errorHandler(logger)),
+	)
+
+	err = pb.RegisterShortenerHandler(ctx, g.mux, conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func newGateway(lc fx.Lifecycle, cfg *config.Config, proxies *proxy.Trust, logger *zap.Logger) (*Gateway, error) {
+	if cfg.GRPCPort == "" {
+		logger.Info("REST gateway is disabled, it requires GRPC server")
+		return nil, nil
+	}
+
+	g, err := New(context.Background(), proxies, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return g.conn.Close()
+		},
+	})
+
+	return g, nil
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newGateway)
+}
+
+// Listener returns the in-memory listener the gRPC server must serve for the gateway.
+func (g *Gateway) Listener() net.Listener {
+	return g.lis
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// The mux passes Host on as x-forwarded-host and the peer address as x-forwarded-for, so they must
+	// already describe the original request. Forwarding headers are resolved here and dropped, a client
+	// must not be able to pass them on.
+	o := g.proxies.Origin(r)
+	ip := g.proxies.ClientIP(r)
+
+	r = r.Clone(context.WithValue(r.Context(), originKey{}, o))
+	r.Host = o.Host
+	if ip.IsValid() {
+		r.RemoteAddr = net.JoinHostPort(ip.String(), "0")
+	}
+
+	for _, name := range forwardingHeaders {
+		r.Header.Del(name)
+	}
+
+	g.mux.ServeHTTP(w, r)
+}
+
+// IsGateway reports whether a gRPC call came from the gateway. Only such calls may carry
+// x-forwarded-host, x-forwarded-proto and x-forwarded-for describing the original HTTP request.
+func IsGateway(ctx context.Context) bool {
+	pr, ok := peer.FromContext(ctx)
+	return ok && pr.Addr != nil && pr.Addr.Network() == network
+}
+
+// ClientIP returns the client address of the original HTTP request of a call from the gateway.
+// It is invalid for other calls.
+func ClientIP(ctx context.Context) netip.Addr {
+	if !IsGateway(ctx) {
+		return netip.Addr{}
+	}
+
+	// The gateway appends the address it resolved, so the last value is the one to use.
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-forwarded-for")
+	if len(values) == 0 {
+		return netip.Addr{}
+	}
+
+	hops := strings.Split(values[len(values)-1], ",")
+
+	addr, err := netip.ParseAddr(strings.TrimSpace(hops[len(hops)-1]))
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	return addr.Unmap()
+}
+
+// headerMatcher forwards the access token header in addition to the default permanent headers.
+// Metadata describing the original request is set by the gateway only, so Grpc-Metadata- headers can't spoof it.
+func headerMatcher(key string) (string, bool) {
+	if strings.EqualFold(key, "Access-Token") || strings.EqualFold(key, "Workspace-Id") {
+		return strings.ToLower(key), true
+	}
+
+	name, ok := runtime.DefaultHeaderMatcher(key)
+	if !ok {
+		return "", false
+	}
+
+	for _, header := range forwardingHeaders {
+		if strings.EqualFold(name, header) {
+			return "", false
+		}
+	}
+
+	// The request ID is passed by annotate.
+	if strings.EqualFold(name, requestid.MetadataKey) {
+		return "", false
+	}
+
+	return name, true
+}
+
+// annotate passes the access token cookie, the scheme and the ID of the original request to the gRPC server.
+func (g *Gateway) annotate(ctx context.Context, r *http.Request) metadata.MD {
+	md := metadata.MD{}
+
+	if r.Header.Get("Access-Token") == "" {
+		if cookie, err := r.Cookie("Access-token"); err == nil {
+			md.Set("access-token", cookie.Value)
+		}
+	}
+
+	o, ok := ctx.Value(originKey{}).(proxy.Origin)
+	if !ok {
+		o = g.proxies.Origin(r)
+	}
+
+	proto := "http"
+	if o.HTTPS {
+		proto = "https"
+	}
+	md.Set("x-forwarded-proto", proto)
+
+	ID := contextI.RequestFrom(r.Context()).ID
+	if ID == "" {
+		ID = r.Header.Get(requestid.Header)
+	}
+
+	if ID != "" {
+		md.Set(requestid.MetadataKey, ID)
+	}
+
+	return md
+}
+
+// errorHandler answers with the status code of the gRPC error and the body the chi handlers use.
+func errorHandler(logger *zap.Logger) runtime.ErrorHandlerFunc {
+	return func(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+		st := status.Convert(err)
+		code := runtime.HTTPStatusFromCode(st.Code())
+
+		msg := st.Message()
+		if code == http.StatusInternalServerError {
+			logger.Error("Gateway call failed", zap.String("path", r.URL.Path), zap.Error(err))
+			msg = http.StatusText(http.StatusInternalServerError)
+		}
+
+		helpers.WriteJSON(w, code, dto.ResponseWrapper{"error": msg})
+	}
+}
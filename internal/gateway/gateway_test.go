@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	pb "github.com/MukizuL/shortener/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeShortener struct {
+	pb.UnimplementedShortenerServer
+}
+
+func (fakeShortener) CreateGRPC(ctx context.Context, in *pb.CreateShortURLRequest) (*pb.CreateShortURLResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if !IsGateway(ctx) {
+		return nil, status.Error(codes.PermissionDenied, "not from gateway")
+	}
+
+	return &pb.CreateShortURLResponse{
+		ShortUrl:    md.Get("x-forwarded-proto")[0] + "://" + md.Get("x-forwarded-host")[0] + "/qxDvSD",
+		AccessToken: strings.Join(md.Get("access-token"), ","),
+	}, nil
+}
+
+func (fakeShortener) GetOriginalURLGRPC(ctx context.Context, in *pb.GetOriginalURLRequest) (*pb.GetOriginalURLResponse, error) {
+	switch in.ShortUrl {
+	case "qxDvSD":
+		return &pb.GetOriginalURLResponse{OriginalUrl: "https://www.youtube.com", RedirectType: 307}, nil
+	case "whoami":
+		return &pb.GetOriginalURLResponse{OriginalUrl: ClientIP(ctx).String()}, nil
+	case "locked":
+		return nil, status.Error(codes.PermissionDenied, "wrong password")
+	case "broken":
+		return nil, status.Error(codes.Internal, "connection refused")
+	default:
+		return nil, status.Error(codes.NotFound, "url is not present")
+	}
+}
+
+func TestGateway(t *testing.T) {
+	g, err := New(context.Background(), nil, zap.NewNop())
+	require.NoError(t, err)
+
+	s := grpc.NewServer()
+	pb.RegisterShortenerServer(s, fakeShortener{})
+	go s.Serve(g.Listener())
+	defer s.Stop()
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		cookie     string
+		headers    map[string]string
+		statusCode int
+		want       map[string]any
+	}{
+		{
+			name:       "Create",
+			method:     http.MethodPost,
+			path:       "/v1/urls",
+			body:       `{"original_url":"https://www.youtube.com"}`,
+			cookie:     "token",
+			statusCode: http.StatusOK,
+			want:       map[string]any{"shortUrl": "http://short.example/qxDvSD", "accessToken": "token"},
+		},
+		{
+			name:   "Forwarding metadata can't be spoofed",
+			method: http.MethodPost,
+			path:   "/v1/urls",
+			body:   `{"original_url":"https://www.youtube.com"}`,
+			headers: map[string]string{
+				"X-Forwarded-Host":               "evil.example",
+				"Grpc-Metadata-X-Forwarded-Host": "evil.example",
+			},
+			statusCode: http.StatusOK,
+			want:       map[string]any{"shortUrl": "http://short.example/qxDvSD", "accessToken": ""},
+		},
+		{
+			name:   "Client address",
+			method: http.MethodGet,
+			path:   "/v1/urls/whoami",
+			headers: map[string]string{
+				"X-Forwarded-For":               "203.0.113.7",
+				"Grpc-Metadata-X-Forwarded-For": "203.0.113.8",
+			},
+			statusCode: http.StatusOK,
+			want:       map[string]any{"originalUrl": "192.0.2.1", "redirectType": float64(0)},
+		},
+		{
+			name:       "Get",
+			method:     http.MethodGet,
+			path:       "/v1/urls/qxDvSD",
+			statusCode: http.StatusOK,
+			want:       map[string]any{"originalUrl": "https://www.youtube.com", "redirectType": float64(307)},
+		},
+		{
+			name:       "Not found",
+			method:     http.MethodGet,
+			path:       "/v1/urls/missing",
+			statusCode: http.StatusNotFound,
+			want:       map[string]any{"error": "url is not present"},
+		},
+		{
+			name:       "Permission denied",
+			method:     http.MethodGet,
+			path:       "/v1/urls/locked",
+			statusCode: http.StatusForbidden,
+			want:       map[string]any{"error": "wrong password"},
+		},
+		{
+			name:       "Internal error is not exposed",
+			method:     http.MethodGet,
+			path:       "/v1/urls/broken",
+			statusCode: http.StatusInternalServerError,
+			want:       map[string]any{"error": http.StatusText(http.StatusInternalServerError)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r.Host = "short.example"
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "Access-token", Value: tt.cookie})
+			}
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+
+			var resp map[string]any
+			require.NoError(t, json.NewDecoder(result.Body).Decode(&resp))
+			assert.Equal(t, tt.want, resp)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	netUrl "net/url"
+	"slices"
+	"strings"
+)
+
+// trackingParams are query parameters added by ad and mail platforms. utm_* parameters are matched by prefix.
+var trackingParams = []string{"gclid", "dclid", "fbclid", "msclkid", "yclid", "igshid", "mc_cid", "mc_eid", "_ga"}
+
+// Canonicalization configures optional steps of URL canonicalization.
+type Canonicalization struct {
+	SortQuery     bool
+	StripTracking bool
+}
+
+// Canonicalize returns the form of url under which equivalent URLs are equal.
+// Scheme and host are lowercased, default ports dropped, percent-encoding normalized
+// and an empty path replaced by "/". url is not modified.
+func (c Canonicalization) Canonicalize(url *netUrl.URL) string {
+	result := *url
+
+	result.Scheme = strings.ToLower(result.Scheme)
+	result.Host = canonicalHost(result.Scheme, result.Host)
+
+	path := normalizeEscapes(result.EscapedPath())
+	if path == "" {
+		path = "/"
+	}
+
+	// RawPath is only used when it is a valid encoding of Path, so both are set from the normalized form.
+	result.Path, _ = netUrl.PathUnescape(path)
+	result.RawPath = path
+
+	result.RawQuery = c.canonicalQuery(result.RawQuery)
+	result.ForceQuery = false
+
+	return result.String()
+}
+
+func canonicalHost(scheme, host string) string {
+	host = strings.ToLower(host)
+
+	switch {
+	case scheme == "http" && strings.HasSuffix(host, ":80"):
+		return strings.TrimSuffix(host, ":80")
+	case scheme == "https" && strings.HasSuffix(host, ":443"):
+		return strings.TrimSuffix(host, ":443")
+	default:
+		return strings.TrimSuffix(host, ":")
+	}
+}
+
+func (c Canonicalization) canonicalQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := strings.Split(rawQuery, "&")
+	result := make([]string, 0, len(params))
+
+	for _, param := range params {
+		if param == "" {
+			continue
+		}
+
+		param = normalizeEscapes(param)
+
+		if c.StripTracking && isTrackingParam(queryKey(param)) {
+			continue
+		}
+
+		result = append(result, param)
+	}
+
+	if c.SortQuery {
+		// Stable sort keeps the order of repeated keys, which may be meaningful.
+		slices.SortStableFunc(result, func(a, b string) int {
+			return strings.Compare(queryKey(a), queryKey(b))
+		})
+	}
+
+	return strings.Join(result, "&")
+}
+
+func queryKey(param string) string {
+	key, _, _ := strings.Cut(param, "=")
+
+	unescaped, err := netUrl.QueryUnescape(key)
+	if err != nil {
+		return key
+	}
+
+	return unescaped
+}
+
+func isTrackingParam(key string) bool {
+	key = strings.ToLower(key)
+
+	return strings.HasPrefix(key, "utm_") || slices.Contains(trackingParams, key)
+}
+
+// normalizeEscapes decodes percent-encoded unreserved characters and uppercases the remaining escapes.
+func normalizeEscapes(s string) string {
+	if !strings.Contains(s, "%") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+2 >= len(s) || !isHex(s[i+1]) || !isHex(s[i+2]) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		decoded := unhex(s[i+1])<<4 | unhex(s[i+2])
+		if isUnreserved(decoded) {
+			b.WriteByte(decoded)
+		} else {
+			b.WriteByte('%')
+			b.WriteString(strings.ToUpper(s[i+1 : i+3]))
+		}
+
+		i += 2
+	}
+
+	return b.String()
+}
+
+func isUnreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0'
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10
+	default:
+		return c - 'A' + 10
+	}
+}
@@ -0,0 +1,85 @@
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplication_CheckURLCanonical(t *testing.T) {
+	tests := []struct {
+		name  string
+		url   string
+		canon Canonicalization
+		want  string
+	}{
+		{
+			name: "Scheme, host and default port",
+			url:  "HTTP://Example.COM:80/a?b=1&a=2",
+			want: "http://example.com/a?b=1&a=2",
+		},
+		{
+			name: "HTTPS default port and empty path",
+			url:  "https://example.com:443",
+			want: "https://example.com/",
+		},
+		{
+			name: "Non-default port is kept",
+			url:  "http://example.com:8080/",
+			want: "http://example.com:8080/",
+		},
+		{
+			name: "Percent-encoding",
+			url:  "http://example.com/%7euser/%2fa%20b?q=%41%2f",
+			want: "http://example.com/~user/%2Fa%20b?q=A%2F",
+		},
+		{
+			name:  "Sorted query",
+			url:   "http://example.com/a?b=1&a=2&a=1",
+			canon: Canonicalization{SortQuery: true},
+			want:  "http://example.com/a?a=2&a=1&b=1",
+		},
+		{
+			name:  "Tracking parameters stripped",
+			url:   "http://example.com/a?utm_source=x&id=5&UTM_Medium=y&fbclid=z",
+			canon: Canonicalization{StripTracking: true},
+			want:  "http://example.com/a?id=5",
+		},
+		{
+			name:  "Only tracking parameters",
+			url:   "http://example.com/a?utm_source=x",
+			canon: Canonicalization{StripTracking: true},
+			want:  "http://example.com/a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original, canonical, err := CheckURL([]byte(tt.url), tt.canon)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, canonical)
+			assert.NotEmpty(t, original)
+		})
+	}
+}
+
+func TestApplication_CheckURLEquivalent(t *testing.T) {
+	canon := Canonicalization{SortQuery: true}
+
+	_, a, err := CheckURL([]byte("HTTP://Example.com:80/a?b=1&a=2"), canon)
+	require.NoError(t, err)
+
+	_, b, err := CheckURL([]byte("http://example.com/a?a=2&b=1"), canon)
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+}
+
+func TestApplication_CheckURLInvalid(t *testing.T) {
+	for _, raw := range []string{"not a url", "ftp://example.com/file", "/relative"} {
+		_, _, err := CheckURL([]byte(raw), Canonicalization{})
+		assert.Error(t, err, raw)
+	}
+}
@@ -1,7 +1,6 @@
 package helpers
 
 import (
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"math/rand"
@@ -9,6 +8,8 @@ import (
 	netUrl "net/url"
 	"strings"
 	"time"
+
+	"golang.org/x/crypto/bcrypt"
 )
 
 const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
@@ -74,23 +75,34 @@ func BuildValuePlaceholders(numCols, numRows int) string {
 	return strings.Join(placeholders, ", ")
 }
 
-func BuildURLSBase(tls *tls.ConnectionState, host string) string {
-	if tls != nil {
-		return fmt.Sprintf("https://%s/", host)
-	} else {
-		return fmt.Sprintf("http://%s/", host)
-	}
-}
-
-func CheckURL(rawURL []byte) (string, error) {
+// CheckURL validates rawURL and returns it together with its canonical form, used to detect duplicates.
+func CheckURL(rawURL []byte, canon Canonicalization) (string, string, error) {
 	url, err := netUrl.ParseRequestURI(string(rawURL))
 	if err != nil {
-		return "", fmt.Errorf("error parsing raw URL: %w", err)
+		return "", "", fmt.Errorf("error parsing raw URL: %w", err)
 	}
 
 	if url.Scheme != "http" && url.Scheme != "https" || url.Host == "" {
-		return "", fmt.Errorf("error parsing raw URL: %v", rawURL)
+		return "", "", fmt.Errorf("error parsing raw URL: %v", rawURL)
+	}
+
+	return url.String(), canon.Canonicalize(url), nil
+}
+
+// MaxPasswordLength is the longest password in bytes bcrypt hashes.
+const MaxPasswordLength = 72
+
+// HashPassword returns bcrypt hash of the password.
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("error hashing password: %w", err)
 	}
 
-	return url.String(), nil
+	return string(hash), nil
+}
+
+// CheckPassword reports whether password matches bcrypt hash.
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
@@ -69,3 +69,13 @@ func TestApplication_SplitIntoBatches(t *testing.T) {
 		})
 	}
 }
+
+func TestApplication_HashPassword(t *testing.T) {
+	hash, err := HashPassword("secret")
+	assert.NoError(t, err)
+	assert.NotEqual(t, "secret", hash)
+
+	assert.True(t, CheckPassword(hash, "secret"))
+	assert.False(t, CheckPassword(hash, "wrong"))
+	assert.False(t, CheckPassword("", "secret"))
+}
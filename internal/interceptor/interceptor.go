@@ -3,39 +3,44 @@ package interceptor
 import (
 	"context"
 	"errors"
-	"net"
 	"net/http"
+	"net/netip"
 	"slices"
+	"strings"
 	"time"
 
+	"github.com/MukizuL/shortener/internal/admin"
 	"github.com/MukizuL/shortener/internal/config"
 	contextI "github.com/MukizuL/shortener/internal/context"
 	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/gateway"
 	jwtService "github.com/MukizuL/shortener/internal/jwt"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/MukizuL/shortener/internal/requestid"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
-type TokenPair struct {
-	AccessToken string
-	UserID      string
-}
-
 type Service struct {
 	jwtService jwtService.JWTServiceInterface
 	cfg        *config.Config
+	proxies    *proxy.Trust
+	guard      *admin.Guard
 	logger     *zap.Logger
 }
 
-func newService(jwtService jwtService.JWTServiceInterface, cfg *config.Config, logger *zap.Logger) *Service {
+func newService(jwtService jwtService.JWTServiceInterface, cfg *config.Config, proxies *proxy.Trust, guard *admin.Guard, logger *zap.Logger) *Service {
 	return &Service{
 		jwtService: jwtService,
 		cfg:        cfg,
+		proxies:    proxies,
+		guard:      guard,
 		logger:     logger,
 	}
 }
@@ -51,25 +56,85 @@ func (s Service) Logger(ctx context.Context, req any, info *grpc.UnaryServerInfo
 
 	duration := time.Since(start)
 
-	s.logger.Info("GRPC request", zap.String("method", info.FullMethod), zap.Duration("time", duration))
+	s.logger.Info("GRPC request", zap.String("method", info.FullMethod), zap.Duration("time", duration),
+		zap.String("request_id", contextI.RequestFrom(ctx).ID))
 	return resp, err
 }
 
+// StreamLogger is Logger for streaming RPCs, it logs once the stream is over.
+func (s Service) StreamLogger(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+
+	err := handler(srv, ss)
+
+	duration := time.Since(start)
+
+	s.logger.Info("GRPC stream", zap.String("method", info.FullMethod), zap.Duration("time", duration),
+		zap.Stringer("code", status.Code(err)), zap.String("request_id", contextI.RequestFrom(ss.Context()).ID))
+	return err
+}
+
 func (s Service) Auth(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	routes := []string{
 		"/shortener.Shortener/CreateGRPC",
 		"/shortener.Shortener/CreateBatchGRPC",
 		"/shortener.Shortener/GetUserURLsGRPC",
 		"/shortener.Shortener/DeleteGRPC",
+		"/shortener.Shortener/CreateWorkspaceGRPC",
+		"/shortener.Shortener/AddWorkspaceMemberGRPC",
+		"/shortener.Shortener/RemoveWorkspaceMemberGRPC",
+		"/shortener.Shortener/GetWorkspacesGRPC",
 	}
 
 	if !slices.Contains(routes, info.FullMethod) {
 		return handler(ctx, req)
 	}
 
+	data, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	newCtx := contextI.WithPrincipal(ctx, data)
+
+	return handler(newCtx, req)
+}
+
+// StreamAuth is Auth for streaming RPCs. As streams have no response message to carry the access token,
+// it is sent in the access-token header.
+func (s Service) StreamAuth(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	routes := []string{
+		"/shortener.Shortener/CreateStreamGRPC",
+		"/shortener.Shortener/ListUserURLs",
+		"/shortener.Shortener/BulkCreate",
+		"/shortener.Shortener/WatchClicks",
+	}
+
+	if !slices.Contains(routes, info.FullMethod) {
+		return handler(srv, ss)
+	}
+
+	data, err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+
+	err = ss.SetHeader(metadata.Pairs("access-token", data.AccessToken))
+	if err != nil {
+		return status.Errorf(codes.Internal, "%s", err.Error())
+	}
+
+	return handler(srv, &wrappedStream{
+		ServerStream: ss,
+		ctx:          contextI.WithPrincipal(ss.Context(), data),
+	})
+}
+
+// authenticate validates the access token from metadata or issues a new one with a new user ID.
+func (s Service) authenticate(ctx context.Context) (contextI.Principal, error) {
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
-		return nil, status.Errorf(codes.Unauthenticated, "metadata is not provided")
+		return contextI.Principal{}, status.Errorf(codes.Unauthenticated, "metadata is not provided")
 	}
 
 	var token, userID string
@@ -85,63 +150,190 @@ func (s Service) Auth(ctx context.Context, req any, info *grpc.UnaryServerInfo,
 	if err != nil {
 		switch {
 		case errors.Is(err, errs.ErrNotAuthorized), errors.Is(err, errs.ErrUnexpectedSigningMethod):
-			return nil, status.Errorf(codes.Unauthenticated, "%s", err.Error())
+			return contextI.Principal{}, status.Errorf(codes.Unauthenticated, "%s", err.Error())
 		case errors.Is(err, errs.ErrSigningToken):
-			return nil, status.Errorf(codes.Internal, "%s", err.Error())
+			return contextI.Principal{}, status.Errorf(codes.Internal, "%s", err.Error())
 		case errors.Is(err, errs.ErrRefreshingToken):
-			return nil, status.Errorf(codes.Internal, "%s", err.Error())
+			return contextI.Principal{}, status.Errorf(codes.Internal, "%s", err.Error())
 		default:
-			return nil, status.Errorf(codes.Internal, "%s", err.Error())
+			return contextI.Principal{}, status.Errorf(codes.Internal, "%s", err.Error())
 		}
 	}
 
-	data := TokenPair{
+	return contextI.Principal{
 		AccessToken: token,
 		UserID:      userID,
-	}
+	}, nil
+}
 
-	newCtx := context.WithValue(ctx, contextI.UserIDContextKey, data)
+// wrappedStream replaces the context of a stream.
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
 
-	return handler(newCtx, req)
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
 }
 
+// IsTrustedCIDR lets only trusted clients call internal methods. A client is trusted if it presented
+// a verified certificate with a trusted name, or if its address is in the trusted subnet.
 func (s Service) IsTrustedCIDR(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 	if info.FullMethod != "/shortener.Shortener/GetStatsGRPC" {
 		return handler(ctx, req)
 	}
 
-	if s.cfg.TrustedCIDR == "" {
+	if name, ok := s.trustedCert(ctx); ok {
+		s.logger.Debug("Client is trusted by certificate", zap.String("name", name))
+		return handler(ctx, req)
+	}
+
+	if !s.guard.HasTrustedSubnet() {
 		return nil, status.Errorf(codes.PermissionDenied, "ip is not in trusted subnet")
 	}
 
-	pr, ok := peer.FromContext(ctx)
-	if !ok {
-		return nil, status.Errorf(codes.PermissionDenied, "unable to get peer address")
+	ip := s.clientIP(ctx)
+	if !s.guard.Trusted(ip) {
+		s.logger.Warn("IP is not trusted", zap.Stringer("ip", ip))
+		return nil, status.Errorf(codes.PermissionDenied, "ip is not in trusted subnet")
 	}
 
-	host, _, err := net.SplitHostPort(pr.Addr.String())
-	if err != nil {
-		return nil, status.Error(codes.PermissionDenied, "invalid peer address format")
+	return handler(ctx, req)
+}
+
+// Admin lets only admins call methods of the admin service: clients with a trusted certificate, clients from
+// the trusted subnet and users with the admin role, identified by the access-token metadata.
+func (s Service) Admin(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !strings.HasPrefix(info.FullMethod, "/shortener.Admin/") {
+		return handler(ctx, req)
 	}
 
-	ip := net.ParseIP(host)
-	if ip == nil {
-		return nil, status.Error(codes.PermissionDenied, "unable to parse IP")
+	if name, ok := s.trustedCert(ctx); ok {
+		s.logger.Debug("Admin is trusted by certificate", zap.String("name", name))
+		return handler(ctx, req)
 	}
 
-	_, subnet, err := net.ParseCIDR(s.cfg.TrustedCIDR)
+	if s.guard.Trusted(s.clientIP(ctx)) {
+		return handler(ctx, req)
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get("access-token")
+	if len(tokens) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "access token is not provided")
+	}
+
+	token, userID, err := s.jwtService.ValidateToken(tokens[0])
 	if err != nil {
-		return nil, status.Error(codes.Internal, http.StatusText(http.StatusInternalServerError))
+		return nil, status.Error(codes.Unauthenticated, err.Error())
 	}
 
-	if !subnet.Contains(ip) {
-		s.logger.Warn("IP is not trusted", zap.String("ip", ip.String()))
-		return nil, status.Errorf(codes.PermissionDenied, "ip is not in trusted subnet")
+	if !s.guard.IsAdmin(userID) {
+		s.logger.Warn("User is not an admin", zap.String("user_id", userID))
+		return nil, status.Error(codes.PermissionDenied, "user is not an admin")
 	}
 
+	return handler(contextI.WithPrincipal(ctx, contextI.Principal{UserID: userID, AccessToken: token}), req)
+}
+
+// RequestID assigns the call an ID, the one passed in x-request-id metadata if it is usable, and sends it back
+// in the response header. Calls from the REST gateway keep the ID of the HTTP request and count as HTTP.
+func (s Service) RequestID(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx = s.withRequest(ctx)
+	grpc.SetHeader(ctx, metadata.Pairs(requestid.MetadataKey, contextI.RequestFrom(ctx).ID))
+
 	return handler(ctx, req)
 }
 
+// StreamRequestID is RequestID for streaming RPCs.
+func (s Service) StreamRequestID(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := s.withRequest(ss.Context())
+	ss.SetHeader(metadata.Pairs(requestid.MetadataKey, contextI.RequestFrom(ctx).ID))
+
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+}
+
+func (s Service) withRequest(ctx context.Context) context.Context {
+	transport := contextI.TransportGRPC
+	if gateway.IsGateway(ctx) {
+		transport = contextI.TransportHTTP
+	}
+
+	var ID string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(requestid.MetadataKey); len(values) > 0 {
+			ID = values[len(values)-1]
+		}
+	}
+
+	return contextI.WithRequest(ctx, contextI.Request{ID: requestid.Resolve(ID), Transport: transport})
+}
+
+// ClientIP resolves the address of the client, looking through trusted proxies, and stores it in context.
+func (s Service) ClientIP(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(contextI.WithClientIP(ctx, s.clientIP(ctx)), req)
+}
+
+// StreamClientIP is ClientIP for streaming RPCs.
+func (s Service) StreamClientIP(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := contextI.WithClientIP(ss.Context(), s.clientIP(ss.Context()))
+
+	return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+}
+
+// clientIP resolves the address of the caller. Calls from the REST gateway carry the address the gateway
+// resolved for the original HTTP request, others are resolved from the peer address and the forwarding metadata
+// set by trusted proxies.
+func (s Service) clientIP(ctx context.Context) netip.Addr {
+	if gateway.IsGateway(ctx) {
+		return gateway.ClientIP(ctx)
+	}
+
+	pr, ok := peer.FromContext(ctx)
+	if !ok || pr.Addr == nil {
+		return netip.Addr{}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	h := make(http.Header, len(md))
+	for key, values := range md {
+		for _, value := range values {
+			h.Add(key, value)
+		}
+	}
+
+	return s.proxies.Resolve(pr.Addr.String(), h)
+}
+
+// trustedCert returns the name of the verified client certificate of the call if it is trusted.
+func (s Service) trustedCert(ctx context.Context) (string, bool) {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+
+	tlsInfo, ok := pr.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+		return "", false
+	}
+
+	cert := tlsInfo.State.VerifiedChains[0][0]
+	names := append([]string{cert.Subject.CommonName}, cert.DNSNames...)
+
+	if s.cfg.GRPCTrustedClients == "" {
+		return names[0], true
+	}
+
+	for _, trusted := range strings.Split(s.cfg.GRPCTrustedClients, ",") {
+		trusted = strings.TrimSpace(trusted)
+		if trusted != "" && slices.Contains(names, trusted) {
+			return trusted, true
+		}
+	}
+
+	return "", false
+}
+
 func (s Service) Recovery(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -156,3 +348,19 @@ func (s Service) Recovery(ctx context.Context, req any, info *grpc.UnaryServerIn
 
 	return handler(ctx, req)
 }
+
+// StreamRecovery is Recovery for streaming RPCs.
+func (s Service) StreamRecovery(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("panic recovered",
+				zap.Any("panic", r),
+				zap.String("method", info.FullMethod),
+				zap.Stack("stack"),
+			)
+			err = status.Error(codes.Internal, "internal server error")
+		}
+	}()
+
+	return handler(srv, ss)
+}
@@ -0,0 +1,226 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/admin"
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/errs"
+	mockjwt "github.com/MukizuL/shortener/internal/jwt/mocks"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestService_IsTrustedCIDR(t *testing.T) {
+	clientCert := &x509.Certificate{Subject: pkix.Name{CommonName: "stats-collector"}, DNSNames: []string{"collector.internal"}}
+	verified := credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{clientCert}}}}
+
+	tests := []struct {
+		name           string
+		trustedCIDR    string
+		trustedClients string
+		proxies        string
+		addr           string
+		md             metadata.MD
+		authInfo       credentials.AuthInfo
+		wantCode       codes.Code
+	}{
+		{
+			name:        "Address in trusted subnet",
+			trustedCIDR: "10.0.0.0/8",
+			addr:        "10.1.2.3:5000",
+			wantCode:    codes.OK,
+		},
+		{
+			name:        "Address out of trusted subnet",
+			trustedCIDR: "10.0.0.0/8",
+			addr:        "192.168.1.1:5000",
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "IPv6 address in one of trusted subnets",
+			trustedCIDR: "10.0.0.0/8, fd00::/8",
+			addr:        "[fd00::1]:5000",
+			wantCode:    codes.OK,
+		},
+		{
+			name:        "Forwarded address is ignored from untrusted peer",
+			trustedCIDR: "10.0.0.0/8",
+			addr:        "192.168.1.1:5000",
+			md:          metadata.Pairs("x-forwarded-for", "10.1.2.3", "x-real-ip", "10.1.2.3"),
+			wantCode:    codes.PermissionDenied,
+		},
+		{
+			name:        "Forwarded address from trusted proxy",
+			trustedCIDR: "10.0.0.0/8",
+			proxies:     "192.168.1.1",
+			addr:        "192.168.1.1:5000",
+			md:          metadata.Pairs("x-forwarded-for", "10.1.2.3"),
+			wantCode:    codes.OK,
+		},
+		{
+			name:     "Any verified certificate",
+			addr:     "192.168.1.1:5000",
+			authInfo: verified,
+			wantCode: codes.OK,
+		},
+		{
+			name:           "Trusted certificate name",
+			trustedClients: "other, collector.internal",
+			addr:           "192.168.1.1:5000",
+			authInfo:       verified,
+			wantCode:       codes.OK,
+		},
+		{
+			name:           "Untrusted certificate name",
+			trustedClients: "other",
+			addr:           "192.168.1.1:5000",
+			authInfo:       verified,
+			wantCode:       codes.PermissionDenied,
+		},
+		{
+			name:     "TLS without client certificate",
+			addr:     "192.168.1.1:5000",
+			authInfo: credentials.TLSInfo{},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trusted, err := proxy.ParsePrefixes(tt.trustedCIDR)
+			require.NoError(t, err)
+			proxies, err := proxy.ParsePrefixes(tt.proxies)
+			require.NoError(t, err)
+
+			s := Service{
+				cfg:     &config.Config{GRPCTrustedClients: tt.trustedClients},
+				proxies: proxy.New(proxies...),
+				guard:   admin.New(trusted),
+				logger:  zap.NewNop(),
+			}
+
+			addr, err := net.ResolveTCPAddr("tcp", tt.addr)
+			assert.NoError(t, err)
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr, AuthInfo: tt.authInfo})
+			ctx = metadata.NewIncomingContext(ctx, tt.md)
+			info := &grpc.UnaryServerInfo{FullMethod: "/shortener.Shortener/GetStatsGRPC"}
+
+			_, err = s.IsTrustedCIDR(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				return nil, nil
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestService_Admin(t *testing.T) {
+	const adminID = "6f1c2d0e-8a4b-4c3d-9e2f-1a2b3c4d5e6f"
+
+	tests := []struct {
+		name      string
+		method    string
+		addr      string
+		md        metadata.MD
+		mockSetup func(*mockjwt.MockJWTServiceInterface)
+		wantCode  codes.Code
+	}{
+		{
+			name:     "Not an admin method",
+			method:   "/shortener.Shortener/GetStatsGRPC",
+			addr:     "192.168.1.1:5000",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "Trusted subnet",
+			method:   "/shortener.Admin/FindLinks",
+			addr:     "10.1.2.3:5000",
+			wantCode: codes.OK,
+		},
+		{
+			name:     "No token",
+			method:   "/shortener.Admin/FindLinks",
+			addr:     "192.168.1.1:5000",
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:   "Invalid token",
+			method: "/shortener.Admin/DisableLink",
+			addr:   "192.168.1.1:5000",
+			md:     metadata.Pairs("access-token", "invalid-token"),
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("invalid-token").Return("", "", errs.ErrNotAuthorized)
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:   "Not an admin",
+			method: "/shortener.Admin/DisableLink",
+			addr:   "192.168.1.1:5000",
+			md:     metadata.Pairs("access-token", "user-token"),
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("user-token").Return("user-token", "2c9a8d7e-1b3f-4e5d-8c6b-7a9f0e1d2c3b", nil)
+			},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:   "Admin",
+			method: "/shortener.Admin/DisableHost",
+			addr:   "192.168.1.1:5000",
+			md:     metadata.Pairs("access-token", "admin-token"),
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("admin-token").Return("admin-token", adminID, nil)
+			},
+			wantCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockJWT := mockjwt.NewMockJWTServiceInterface(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockJWT)
+			}
+
+			trusted, err := proxy.ParsePrefixes("10.0.0.0/8")
+			require.NoError(t, err)
+
+			s := Service{
+				cfg:        &config.Config{},
+				jwtService: mockJWT,
+				guard:      admin.New(trusted, adminID),
+				logger:     zap.NewNop(),
+			}
+
+			addr, err := net.ResolveTCPAddr("tcp", tt.addr)
+			assert.NoError(t, err)
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr})
+			ctx = metadata.NewIncomingContext(ctx, tt.md)
+			info := &grpc.UnaryServerInfo{FullMethod: tt.method}
+
+			_, err = s.Admin(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+				return nil, nil
+			})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package limiter
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+const (
+	// MaxFailures is the number of failed attempts allowed within Window.
+	MaxFailures = 5
+	// Window is the period failed attempts are counted over.
+	Window = 15 * time.Minute
+)
+
+type attempts struct {
+	count int
+	start time.Time
+}
+
+// Limiter counts failed attempts per key and blocks the key once MaxFailures is reached within Window.
+type Limiter struct {
+	failures    map[string]*attempts
+	maxFailures int
+	window      time.Duration
+	m           sync.Mutex
+}
+
+func New(maxFailures int, window time.Duration) *Limiter {
+	return &Limiter{
+		failures:    make(map[string]*attempts),
+		maxFailures: maxFailures,
+		window:      window,
+	}
+}
+
+func newLimiter() *Limiter {
+	return New(MaxFailures, Window)
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newLimiter)
+}
+
+// Allow reports whether another attempt is allowed for key.
+func (l *Limiter) Allow(key string) bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	a, ok := l.failures[key]
+	if !ok {
+		return true
+	}
+
+	if time.Since(a.start) > l.window {
+		delete(l.failures, key)
+		return true
+	}
+
+	return a.count < l.maxFailures
+}
+
+// Fail registers a failed attempt for key.
+func (l *Limiter) Fail(key string) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	now := time.Now()
+
+	a, ok := l.failures[key]
+	if !ok || now.Sub(a.start) > l.window {
+		l.sweep(now)
+		l.failures[key] = &attempts{count: 1, start: now}
+		return
+	}
+
+	a.count++
+}
+
+// Reset forgets failed attempts for key.
+func (l *Limiter) Reset(key string) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	delete(l.failures, key)
+}
+
+// sweep removes expired entries. Must be called under lock.
+func (l *Limiter) sweep(now time.Time) {
+	for k, a := range l.failures {
+		if now.Sub(a.start) > l.window {
+			delete(l.failures, k)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package limiter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	l := New(3, time.Minute)
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, l.Allow("key"))
+		l.Fail("key")
+	}
+
+	assert.False(t, l.Allow("key"))
+	assert.True(t, l.Allow("other"))
+
+	l.Reset("key")
+	assert.True(t, l.Allow("key"))
+}
+
+func TestLimiter_WindowExpires(t *testing.T) {
+	l := New(1, 10*time.Millisecond)
+
+	l.Fail("key")
+	assert.False(t, l.Allow("key"))
+
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, l.Allow("key"))
+}
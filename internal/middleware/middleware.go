@@ -2,19 +2,20 @@ package mw
 
 import (
 	"compress/gzip"
-	"context"
 	"errors"
 	"io"
-	"net"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/MukizuL/shortener/internal/admin"
 	"github.com/MukizuL/shortener/internal/config"
 	contextI "github.com/MukizuL/shortener/internal/context"
 	"github.com/MukizuL/shortener/internal/errs"
 	"github.com/MukizuL/shortener/internal/helpers"
 	jwtService "github.com/MukizuL/shortener/internal/jwt"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/MukizuL/shortener/internal/requestid"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
@@ -22,13 +23,17 @@ import (
 type MiddlewareService struct {
 	jwtService jwtService.JWTServiceInterface
 	cfg        *config.Config
+	proxies    *proxy.Trust
+	guard      *admin.Guard
 	logger     *zap.Logger
 }
 
-func newMiddlewareService(jwtService jwtService.JWTServiceInterface, cfg *config.Config, logger *zap.Logger) *MiddlewareService {
+func newMiddlewareService(jwtService jwtService.JWTServiceInterface, cfg *config.Config, proxies *proxy.Trust, guard *admin.Guard, logger *zap.Logger) *MiddlewareService {
 	return &MiddlewareService{
 		jwtService: jwtService,
 		cfg:        cfg,
+		proxies:    proxies,
+		guard:      guard,
 		logger:     logger,
 	}
 }
@@ -51,7 +56,8 @@ func (s *MiddlewareService) LoggerMW(h http.Handler) http.Handler {
 
 		duration := time.Since(start)
 
-		s.logger.Info("Request", zap.String("uri", r.RequestURI), zap.String("method", r.Method), zap.Duration("time", duration))
+		s.logger.Info("Request", zap.String("uri", r.RequestURI), zap.String("method", r.Method), zap.Duration("time", duration),
+			zap.String("request_id", contextI.RequestFrom(r.Context()).ID))
 		s.logger.Info("Response", zap.Int("status", mRW.GetStatusCode()), zap.Int("size", mRW.GetSize()))
 	})
 }
@@ -101,7 +107,7 @@ func (s *MiddlewareService) GzipCompress(h http.Handler) http.Handler {
 	})
 }
 
-// Authorization checks for Access-token in cookie. If it's present and valid, sets userID in context.
+// Authorization checks for Access-token in cookie. If it's present and valid, sets the principal in context.
 // If token is not present, creates a new one. If token is invalid, returns an error.
 func (s *MiddlewareService) Authorization(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -128,7 +134,7 @@ func (s *MiddlewareService) Authorization(h http.Handler) http.Handler {
 			}
 		}
 
-		r = r.Clone(context.WithValue(r.Context(), contextI.UserIDContextKey, userID))
+		r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: userID, AccessToken: token}))
 
 		helpers.WriteCookie(w, token)
 
@@ -136,33 +142,98 @@ func (s *MiddlewareService) Authorization(h http.Handler) http.Handler {
 	})
 }
 
+// Identify sets the principal in context when a valid Access-token cookie is present.
+// Unlike Authorization it never issues a new token and lets anonymous requests through.
+func (s *MiddlewareService) Identify(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("Access-token")
+		if err != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		token, userID, err := s.jwtService.ValidateToken(cookie.Value)
+		if err != nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: userID, AccessToken: token}))
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ClientIP resolves the address of the client, looking through trusted proxies, and stores it in context.
+func (s *MiddlewareService) ClientIP(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(contextI.WithClientIP(r.Context(), s.proxies.ClientIP(r)))
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// RequestID assigns the request an ID, the one passed in the X-Request-Id header if it is usable,
+// and echoes it back in the response.
+func (s *MiddlewareService) RequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ID := requestid.Resolve(r.Header.Get(requestid.Header))
+		w.Header().Set(requestid.Header, ID)
+
+		r = r.WithContext(contextI.WithRequest(r.Context(), contextI.Request{ID: ID, Transport: contextI.TransportHTTP}))
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// IsTrustedCIDR lets only clients from the trusted subnets through.
 func (s *MiddlewareService) IsTrustedCIDR(h http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if s.cfg.TrustedCIDR == "" {
+		if !s.guard.HasTrustedSubnet() {
 			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 			return
 		}
 
-		realIP := r.Header.Get("X-Real-IP")
-		if realIP == "" {
-			ipPort := strings.Split(r.RemoteAddr, ":")
-			realIP = ipPort[0]
+		ip := s.proxies.ClientIP(r)
+		if !s.guard.Trusted(ip) {
+			s.logger.Warn("IP is not trusted", zap.Stringer("ip", ip))
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
 		}
 
-		IP := net.ParseIP(realIP)
+		h.ServeHTTP(w, r)
+	})
+}
 
-		_, subnet, err := net.ParseCIDR(s.cfg.TrustedCIDR)
+// Admin lets through clients from the trusted subnet and users with the admin role. Admins are identified by
+// the Access-token cookie, which is never issued here, and are set as the principal in context.
+func (s *MiddlewareService) Admin(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.guard.Trusted(s.proxies.ClientIP(r)) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		cookie, err := r.Cookie("Access-token")
 		if err != nil {
-			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
 
-		if subnet.Contains(IP) {
-			h.ServeHTTP(w, r)
-		} else {
-			s.logger.Warn("IP is not trusted", zap.String("ip", IP.String()))
+		token, userID, err := s.jwtService.ValidateToken(cookie.Value)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		if !s.guard.IsAdmin(userID) {
+			s.logger.Warn("User is not an admin", zap.String("user_id", userID))
 			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 			return
 		}
+
+		r = r.Clone(contextI.WithPrincipal(r.Context(), contextI.Principal{UserID: userID, AccessToken: token}))
+
+		h.ServeHTTP(w, r)
 	})
 }
@@ -1,16 +1,24 @@
 package mw
 
 import (
+	"bytes"
+	"compress/gzip"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/MukizuL/shortener/internal/admin"
 	contextI "github.com/MukizuL/shortener/internal/context"
 	"github.com/MukizuL/shortener/internal/errs"
 	mockjwt "github.com/MukizuL/shortener/internal/jwt/mocks"
+	"github.com/MukizuL/shortener/internal/proxy"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 )
@@ -94,7 +102,8 @@ func TestApplication_Authorization(t *testing.T) {
 
 			// Create a simple handler to verify context was set
 			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				userID, ok := r.Context().Value(contextI.UserIDContextKey).(string)
+				p, ok := contextI.PrincipalFrom(r.Context())
+				userID := p.UserID
 				if !ok && tt.wantStatus == http.StatusOK {
 					t.Error("user ID not set in context")
 				}
@@ -130,3 +139,380 @@ func TestApplication_Authorization(t *testing.T) {
 		})
 	}
 }
+
+func TestApplication_Identify(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockSetup   func(*mockjwt.MockJWTServiceInterface)
+		cookieValue string
+		wantUserID  string
+	}{
+		{
+			name: "valid token",
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("valid-token").Return("new-token", "user1", nil)
+			},
+			cookieValue: "valid-token",
+			wantUserID:  "user1",
+		},
+		{
+			name: "invalid token",
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("invalid-token").Return("", "", errs.ErrNotAuthorized)
+			},
+			cookieValue: "invalid-token",
+		},
+		{
+			name: "no token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockJWT := mockjwt.NewMockJWTServiceInterface(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockJWT)
+			}
+
+			s := &MiddlewareService{
+				jwtService: mockJWT,
+				logger:     zap.NewNop(),
+			}
+
+			r := httptest.NewRequest("GET", "/", nil)
+			if tt.cookieValue != "" {
+				r.AddCookie(&http.Cookie{
+					Name:  "Access-token",
+					Value: tt.cookieValue,
+				})
+			}
+
+			w := httptest.NewRecorder()
+
+			var userID string
+			nextHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				p, _ := contextI.PrincipalFrom(r.Context())
+				userID = p.UserID
+			})
+
+			s.Identify(nextHandler).ServeHTTP(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, http.StatusOK, result.StatusCode)
+			assert.Equal(t, tt.wantUserID, userID)
+			assert.Empty(t, result.Cookies())
+		})
+	}
+}
+
+func TestApplication_IsTrustedCIDR(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    string
+		remoteAddr string
+		headers    map[string]string
+		statusCode int
+	}{
+		{
+			name:       "No trusted subnet",
+			remoteAddr: "10.1.2.3:5000",
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Address in trusted subnet",
+			trusted:    "10.0.0.0/8",
+			remoteAddr: "10.1.2.3:5000",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "IPv6 address in one of trusted subnets",
+			trusted:    "10.0.0.0/8, 2001:db8::/32",
+			remoteAddr: "[2001:db8::1]:5000",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Spoofed X-Real-IP",
+			trusted:    "10.0.0.0/8",
+			remoteAddr: "192.0.2.1:5000",
+			headers:    map[string]string{"X-Real-IP": "10.1.2.3"},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:       "Forwarded by trusted proxy",
+			trusted:    "10.0.0.0/8",
+			remoteAddr: "192.168.1.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "10.1.2.3"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Spoofed hop before trusted proxy",
+			trusted:    "10.0.0.0/8",
+			remoteAddr: "192.168.1.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "10.1.2.3, 192.0.2.1"},
+			statusCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trusted, err := proxy.ParsePrefixes(tt.trusted)
+			require.NoError(t, err)
+
+			s := &MiddlewareService{
+				proxies: proxy.New(netip.MustParsePrefix("192.168.1.1/32")),
+				guard:   admin.New(trusted),
+				logger:  zap.NewNop(),
+			}
+
+			r := httptest.NewRequest("GET", "/api/internal/stats", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for name, value := range tt.headers {
+				r.Header.Set(name, value)
+			}
+
+			w := httptest.NewRecorder()
+
+			s.IsTrustedCIDR(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestApplication_Admin(t *testing.T) {
+	const adminID = "6f1c2d0e-8a4b-4c3d-9e2f-1a2b3c4d5e6f"
+
+	tests := []struct {
+		name        string
+		remoteAddr  string
+		cookieValue string
+		mockSetup   func(*mockjwt.MockJWTServiceInterface)
+		statusCode  int
+	}{
+		{
+			name:       "Trusted subnet",
+			remoteAddr: "10.1.2.3:5000",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "No token",
+			remoteAddr: "192.0.2.1:5000",
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:        "Invalid token",
+			remoteAddr:  "192.0.2.1:5000",
+			cookieValue: "invalid-token",
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("invalid-token").Return("", "", errs.ErrNotAuthorized)
+			},
+			statusCode: http.StatusUnauthorized,
+		},
+		{
+			name:        "Not an admin",
+			remoteAddr:  "192.0.2.1:5000",
+			cookieValue: "user-token",
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("user-token").Return("user-token", "2c9a8d7e-1b3f-4e5d-8c6b-7a9f0e1d2c3b", nil)
+			},
+			statusCode: http.StatusForbidden,
+		},
+		{
+			name:        "Admin",
+			remoteAddr:  "192.0.2.1:5000",
+			cookieValue: "admin-token",
+			mockSetup: func(m *mockjwt.MockJWTServiceInterface) {
+				m.EXPECT().ValidateToken("admin-token").Return("admin-token", adminID, nil)
+			},
+			statusCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockJWT := mockjwt.NewMockJWTServiceInterface(ctrl)
+			if tt.mockSetup != nil {
+				tt.mockSetup(mockJWT)
+			}
+
+			s := &MiddlewareService{
+				jwtService: mockJWT,
+				guard:      admin.New(proxy.Subnets{netip.MustParsePrefix("10.0.0.0/8")}, adminID),
+				logger:     zap.NewNop(),
+			}
+
+			r := httptest.NewRequest("GET", "/api/admin/links", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.cookieValue != "" {
+				r.AddCookie(&http.Cookie{Name: "Access-token", Value: tt.cookieValue})
+			}
+
+			w := httptest.NewRecorder()
+
+			s.Admin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).ServeHTTP(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, tt.statusCode, result.StatusCode)
+		})
+	}
+}
+
+func TestApplication_RequestID(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		wantID string
+	}{
+		{
+			name:   "Given by client",
+			header: "3f2a-client.1",
+			wantID: "3f2a-client.1",
+		},
+		{
+			name: "Missing",
+		},
+		{
+			name:   "Malformed",
+			header: "id with spaces",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MiddlewareService{logger: zap.NewNop()}
+
+			r := httptest.NewRequest("GET", "/qxDvSD", nil)
+			if tt.header != "" {
+				r.Header.Set("X-Request-Id", tt.header)
+			}
+
+			w := httptest.NewRecorder()
+
+			var req contextI.Request
+			s.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req = contextI.RequestFrom(r.Context())
+			})).ServeHTTP(w, r)
+
+			result := w.Result()
+			defer result.Body.Close()
+
+			assert.Equal(t, contextI.TransportHTTP, req.Transport)
+			assert.Equal(t, req.ID, result.Header.Get("X-Request-Id"))
+			if tt.wantID != "" {
+				assert.Equal(t, tt.wantID, req.ID)
+			} else {
+				assert.NotEmpty(t, req.ID)
+				assert.NotEqual(t, tt.header, req.ID)
+			}
+		})
+	}
+}
+
+func TestApplication_GzipCompressFlush(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+	}{
+		{
+			name:           "Compressed",
+			acceptEncoding: "gzip",
+		},
+		{
+			name: "Plain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &MiddlewareService{logger: zap.NewNop()}
+
+			r := httptest.NewRequest("GET", "/api/user/events", nil)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			w := httptest.NewRecorder()
+
+			const chunk = "data: {}\n\n"
+			var flushed []byte
+			s.GzipCompress(http.HandlerFunc(func(mw http.ResponseWriter, r *http.Request) {
+				io.WriteString(mw, chunk)
+				require.NoError(t, http.NewResponseController(mw).Flush())
+
+				// What reached the client before the handler returns.
+				flushed = bytes.Clone(w.Body.Bytes())
+			})).ServeHTTP(w, r)
+
+			assert.True(t, w.Flushed)
+
+			body := io.Reader(bytes.NewReader(flushed))
+			if tt.acceptEncoding != "" {
+				gzR, err := gzip.NewReader(body)
+				require.NoError(t, err)
+				body = gzR
+			}
+
+			got := make([]byte, len(chunk))
+			_, err := io.ReadFull(body, got)
+			require.NoError(t, err)
+			assert.Equal(t, chunk, string(got))
+		})
+	}
+}
+
+func TestApplication_GzipCompressDeadlines(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+	}{
+		{
+			name:           "Compressed",
+			acceptEncoding: "gzip",
+		},
+		{
+			name: "Plain",
+		},
+	}
+
+	s := &MiddlewareService{logger: zap.NewNop()}
+
+	var results []error
+	srv := httptest.NewServer(s.GzipCompress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Streaming handlers reach the connection through the wrapped writer.
+		rc := http.NewResponseController(w)
+		deadline := time.Now().Add(time.Minute)
+		results = []error{rc.EnableFullDuplex(), rc.SetReadDeadline(deadline), rc.SetWriteDeadline(deadline), rc.Flush()}
+	})))
+	defer srv.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := http.NewRequest(http.MethodPost, srv.URL, nil)
+			require.NoError(t, err)
+			if tt.acceptEncoding != "" {
+				r.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+
+			resp, err := http.DefaultClient.Do(r)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			for _, err := range results {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package mw
 
 import (
-	"io"
+	"compress/gzip"
 	"net/http"
 )
 
@@ -29,9 +29,29 @@ func (r *moddedResponseWriter) Write(b []byte) (int, error) {
 	return size, err
 }
 
+// Unwrap lets http.ResponseController reach the connection, to flush and set deadlines.
+func (r *moddedResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
 type gzipResponseWriter struct {
 	moddedResponseWriter
-	Writer io.Writer
+	Writer *gzip.Writer
+}
+
+// Flush sends what was written so far, compressing it without waiting for more. Streaming handlers flush after
+// every message, they would otherwise stall until the compressor fills a block.
+func (r *gzipResponseWriter) Flush() {
+	_ = r.FlushError()
+}
+
+func (r *gzipResponseWriter) FlushError() error {
+	err := r.Writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	return http.NewResponseController(r.ResponseWriter).Flush()
 }
 
 func (r *gzipResponseWriter) Write(b []byte) (int, error) {
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE urls ADD COLUMN redirect_type SMALLINT NOT NULL DEFAULT 307 CHECK (redirect_type IN (301, 302, 307, 308));
+
+-- +goose Down
+ALTER TABLE urls DROP COLUMN redirect_type;
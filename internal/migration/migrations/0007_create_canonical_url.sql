@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN canonical_url TEXT;
+UPDATE urls SET canonical_url = full_url;
+ALTER TABLE urls ALTER COLUMN canonical_url SET NOT NULL;
+
+ALTER TABLE urls DROP CONSTRAINT urls_full_url_key;
+ALTER TABLE urls ADD CONSTRAINT urls_canonical_url_key UNIQUE (canonical_url);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP CONSTRAINT urls_canonical_url_key;
+ALTER TABLE urls ADD CONSTRAINT urls_full_url_key UNIQUE (full_url);
+ALTER TABLE urls DROP COLUMN canonical_url;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE urls ADD COLUMN dedupe BOOL NOT NULL DEFAULT TRUE;
+
+ALTER TABLE urls DROP CONSTRAINT urls_canonical_url_key;
+CREATE INDEX urls_canonical_url_hash_idx ON urls USING HASH(canonical_url);
+
+-- Links created without deduplication are left out, so a user may have several links to the same URL.
+CREATE UNIQUE INDEX urls_user_id_canonical_url_key ON urls (user_id, canonical_url) WHERE dedupe;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX urls_user_id_canonical_url_key;
+DROP INDEX urls_canonical_url_hash_idx;
+ALTER TABLE urls ADD CONSTRAINT urls_canonical_url_key UNIQUE (canonical_url);
+ALTER TABLE urls DROP COLUMN dedupe;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Links on the default domain have an empty domain.
+ALTER TABLE urls ADD COLUMN domain TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE urls DROP CONSTRAINT urls_short_url_key;
+ALTER TABLE urls ADD CONSTRAINT urls_domain_short_url_key UNIQUE (domain, short_url);
+
+DROP INDEX urls_user_id_canonical_url_key;
+CREATE UNIQUE INDEX urls_domain_user_id_canonical_url_key ON urls (domain, user_id, canonical_url) WHERE dedupe;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX urls_domain_user_id_canonical_url_key;
+CREATE UNIQUE INDEX urls_user_id_canonical_url_key ON urls (user_id, canonical_url) WHERE dedupe;
+
+ALTER TABLE urls DROP CONSTRAINT urls_domain_short_url_key;
+ALTER TABLE urls ADD CONSTRAINT urls_short_url_key UNIQUE (short_url);
+
+ALTER TABLE urls DROP COLUMN domain;
+-- +goose StatementEnd
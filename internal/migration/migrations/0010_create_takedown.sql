@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Links disabled by a moderator have a status visitors get and a reason shown to them.
+ALTER TABLE urls ADD COLUMN disabled_status SMALLINT CHECK (disabled_status IN (410, 451));
+ALTER TABLE urls ADD COLUMN disabled_reason TEXT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE urls DROP COLUMN disabled_reason;
+ALTER TABLE urls DROP COLUMN disabled_status;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Abuse reports about links filed by visitors, reviewed by moderators.
+CREATE TABLE IF NOT EXISTS abuse_reports (
+    id UUID PRIMARY KEY,
+    domain TEXT NOT NULL DEFAULT '',
+    short_url TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    contact TEXT,
+    reporter TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'dismissed')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+-- A reporter has at most one pending report about a link.
+CREATE UNIQUE INDEX abuse_reports_pending_reporter_key ON abuse_reports (domain, short_url, reporter) WHERE status = 'pending';
+
+CREATE INDEX abuse_reports_status_created_at_idx ON abuse_reports (status, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS abuse_reports;
+-- +goose StatementEnd
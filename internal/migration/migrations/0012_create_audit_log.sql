@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Changes made through the API. Rows are only ever inserted.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT now(),
+    action TEXT NOT NULL,
+    actor_id UUID,
+    actor_ip INET,
+    transport TEXT NOT NULL,
+    request_id TEXT,
+    domain TEXT NOT NULL DEFAULT '',
+    short_url TEXT,
+    workspace_id UUID,
+    before JSONB,
+    after JSONB
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX audit_log_actor_id_created_at_idx ON audit_log (actor_id, created_at);
+CREATE INDEX audit_log_short_url_created_at_idx ON audit_log (short_url, created_at);
+
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+DROP FUNCTION IF EXISTS audit_log_append_only();
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleted links are left out, so a URL shortened again after its link was deleted gets a new link.
+DROP INDEX urls_domain_user_id_canonical_url_key;
+CREATE UNIQUE INDEX urls_domain_user_id_canonical_url_key ON urls (domain, user_id, canonical_url) WHERE dedupe AND NOT deleted_flag;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX urls_domain_user_id_canonical_url_key;
+CREATE UNIQUE INDEX urls_domain_user_id_canonical_url_key ON urls (domain, user_id, canonical_url) WHERE dedupe;
+-- +goose StatementEnd
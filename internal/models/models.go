@@ -1,8 +1,320 @@
 package models
 
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
 // Urls data type to store urls.
 type Urls struct {
-	UserID      string `json:"user_id"`
-	ShortURL    string `json:"short_url"`
-	OriginalURL string `json:"original_url"`
+	UserID       string    `json:"user_id"`
+	ShortURL     string    `json:"short_url"`
+	Domain       string    `json:"domain,omitempty"`
+	OriginalURL  string    `json:"original_url"`
+	CanonicalURL string    `json:"canonical_url,omitempty"`
+	WorkspaceID  string    `json:"workspace_id,omitempty"`
+	PasswordHash string    `json:"password_hash,omitempty"`
+	MaxClicks    int       `json:"max_clicks,omitempty"`
+	ClicksLeft   int       `json:"clicks_left,omitempty"`
+	RedirectType int       `json:"redirect_type,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitzero"`
+	// DisabledStatus and DisabledReason hold the Takedown of a disabled link.
+	DisabledStatus int    `json:"disabled_status,omitempty"`
+	DisabledReason string `json:"disabled_reason,omitempty"`
+}
+
+// LinkMeta holds information about who created a link and when, and the canonical form of its URL.
+type LinkMeta struct {
+	UserID       string
+	CanonicalURL string
+	CreatedAt    time.Time
+}
+
+// URLOptions holds optional parameters of a newly created short URL.
+type URLOptions struct {
+	Domain       string // empty for the default domain
+	WorkspaceID  string
+	PasswordHash string // bcrypt hash, the plaintext password is never stored
+	MaxClicks    int    // 0 means unlimited
+	RedirectType int    // HTTP status code of the redirect, 0 means DefaultRedirectType
+}
+
+// DefaultRedirectType is used for links created without explicit redirect type.
+const DefaultRedirectType = http.StatusTemporaryRedirect
+
+// ValidRedirectType reports whether code may be used as a link redirect type.
+func ValidRedirectType(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	default:
+		return false
+	}
+}
+
+// DedupeScope defines whose existing link is returned when a URL is shortened again.
+type DedupeScope string
+
+const (
+	DedupeGlobal  DedupeScope = "global"   // one link per URL for everyone
+	DedupePerUser DedupeScope = "per-user" // one link per URL for each user
+	DedupeNone    DedupeScope = "none"     // every request creates a new link
+)
+
+// Valid reports whether s is a known scope.
+func (s DedupeScope) Valid() bool {
+	switch s {
+	case DedupeGlobal, DedupePerUser, DedupeNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// Link is a stored short URL as it is resolved on redirect.
+type Link struct {
+	ShortURL     string
+	Domain       string // empty for the default domain
+	OriginalURL  string
+	UserID       string
+	WorkspaceID  string
+	CreatedAt    time.Time
+	PasswordHash string
+	MaxClicks    int
+	ClicksLeft   int
+	RedirectType int
+	Takedown     Takedown // zero unless the link was disabled by a moderator
+}
+
+// StatusCode returns HTTP status code to redirect with.
+func (l Link) StatusCode() int {
+	if l.RedirectType == 0 {
+		return DefaultRedirectType
+	}
+
+	return l.RedirectType
+}
+
+// Protected reports whether the link requires a password before redirecting.
+func (l Link) Protected() bool {
+	return l.PasswordHash != ""
+}
+
+// Limited reports whether the link may be followed only a limited number of times.
+func (l Link) Limited() bool {
+	return l.MaxClicks > 0
+}
+
+// Exhausted reports whether all clicks of a limited link are used up.
+func (l Link) Exhausted() bool {
+	return l.Limited() && l.ClicksLeft <= 0
+}
+
+// Disabled reports whether a moderator took the link down.
+func (l Link) Disabled() bool {
+	return l.Takedown.Status != 0
+}
+
+// Takedown is a moderator's decision to disable a link. The zero value means the link is enabled.
+type Takedown struct {
+	Status int    // HTTP status code visitors get, see ValidTakedownStatus
+	Reason string // shown to visitors
+}
+
+// DefaultTakedownStatus is used for takedowns without explicit status.
+const DefaultTakedownStatus = http.StatusGone
+
+// ValidTakedownStatus reports whether code may be used as the status of a disabled link.
+func ValidTakedownStatus(code int) bool {
+	return code == http.StatusGone || code == http.StatusUnavailableForLegalReasons
+}
+
+// LinkQuery selects links of every user for moderation. Empty fields match any link.
+type LinkQuery struct {
+	ShortURL    string   // ID of the link
+	Domains     []string // short domains the link is on, every domain if empty
+	Destination string   // original URL, matched as given or in canonical form
+	Host        string   // host of the destination, subdomains included
+	UserID      string   // creator of the link
+}
+
+// Empty reports whether q selects every link.
+func (q LinkQuery) Empty() bool {
+	return q.ShortURL == "" && q.Destination == "" && q.Host == "" && q.UserID == ""
+}
+
+// ReportStatus is the state of an abuse report in the review queue.
+type ReportStatus string
+
+const (
+	ReportPending   ReportStatus = "pending"
+	ReportApproved  ReportStatus = "approved"
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+// Valid reports whether s is one of the known report statuses.
+func (s ReportStatus) Valid() bool {
+	switch s {
+	case ReportPending, ReportApproved, ReportDismissed:
+		return true
+	default:
+		return false
+	}
+}
+
+// Report is a visitor's complaint about a link, waiting for a moderator in the review queue.
+type Report struct {
+	ID        string       `json:"id"`
+	Domain    string       `json:"domain,omitempty"` // short domain key of the link, empty for the default domain
+	ShortURL  string       `json:"short_url"`
+	Reason    string       `json:"reason"`
+	Contact   string       `json:"contact,omitempty"` // how to reach the reporter, optional
+	Reporter  string       `json:"reporter"`          // client IP, reports are counted per reporter
+	Status    ReportStatus `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// AuditEvent records a change made through the API: who made it, from where, and the values before and after.
+// Before is empty for created objects, After for deleted ones.
+type AuditEvent struct {
+	ID          string         `json:"id"`
+	Time        time.Time      `json:"time"`
+	Action      string         `json:"action"`
+	ActorID     string         `json:"actor_id,omitempty"` // empty for clients trusted by subnet or certificate and for anonymous visitors
+	ActorIP     string         `json:"actor_ip,omitempty"`
+	Transport   string         `json:"transport"`
+	RequestID   string         `json:"request_id,omitempty"`
+	Domain      string         `json:"domain,omitempty"` // short domain key of the link, empty for the default domain
+	ShortURL    string         `json:"short_url,omitempty"`
+	WorkspaceID string         `json:"workspace_id,omitempty"`
+	Before      map[string]any `json:"before,omitempty"`
+	After       map[string]any `json:"after,omitempty"`
+}
+
+// Audit actions.
+const (
+	AuditLinkCreate       = "link.create"
+	AuditLinkBatchCreate  = "link.batch_create"
+	AuditLinkDelete       = "link.delete"
+	AuditLinkDisable      = "link.disable"
+	AuditLinkEnable       = "link.enable"
+	AuditLinkAutoDisable  = "link.auto_disable"
+	AuditHostDisable      = "host.disable"
+	AuditReportCreate     = "report.create"
+	AuditReportApprove    = "report.approve"
+	AuditReportDismiss    = "report.dismiss"
+	AuditWorkspaceCreate  = "workspace.create"
+	AuditMemberAdd        = "workspace.member_add"
+	AuditMemberRemove     = "workspace.member_remove"
+	AuditWebhookCreate    = "webhook.create"
+	AuditWebhookDelete    = "webhook.delete"
+	AuditWebhookRedeliver = "webhook.redeliver"
+)
+
+// AuditQuery selects audit events. Empty fields match any event.
+type AuditQuery struct {
+	ActorID  string
+	ShortURL string   // ID of the link
+	Domains  []string // short domains of the link, every domain if empty
+	Action   string
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+	Limit    int       // newest events are returned first
+}
+
+// Role is a level of access a user has inside a workspace.
+type Role string
+
+const (
+	RoleOwner  Role = "owner"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleOwner, RoleEditor, RoleViewer:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanEdit reports whether r allows creating and deleting workspace links.
+func (r Role) CanEdit() bool {
+	return r == RoleOwner || r == RoleEditor
+}
+
+// CanManage reports whether r allows managing workspace members.
+func (r Role) CanManage() bool {
+	return r == RoleOwner
+}
+
+// Workspace data type to store workspaces and their members.
+type Workspace struct {
+	ID      string          `json:"id"`
+	Name    string          `json:"name"`
+	OwnerID string          `json:"owner_id"`
+	Members map[string]Role `json:"members"` // Members[UserID]Role
+}
+
+// Webhook events.
+const (
+	WebhookLinkCreated = "link.created"
+	WebhookLinkClicked = "link.clicked"
+	WebhookLinkDeleted = "link.deleted"
+)
+
+// WebhookEvents are all webhook events, a webhook without events is subscribed to these.
+var WebhookEvents = []string{WebhookLinkCreated, WebhookLinkClicked, WebhookLinkDeleted}
+
+// Webhook is a subscription of a user to events about links they created. Deliveries are signed with Secret.
+type Webhook struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DeliveryStatus is the state of a webhook delivery in the outbox.
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"   // waiting for its next attempt
+	DeliveryDelivered DeliveryStatus = "delivered" // the receiver answered 2xx
+	DeliveryDead      DeliveryStatus = "dead"      // every attempt failed, only redelivery sends it again
+)
+
+// Valid reports whether s is one of the known delivery statuses.
+func (s DeliveryStatus) Valid() bool {
+	switch s {
+	case DeliveryPending, DeliveryDelivered, DeliveryDead:
+		return true
+	default:
+		return false
+	}
+}
+
+// Delivery is an event queued in the outbox for a webhook.
+type Delivery struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhook_id"`
+	UserID         string          `json:"user_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	ResponseStatus int             `json:"response_status,omitempty"` // of the last attempt, 0 if there was no response
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    time.Time       `json:"delivered_at,omitzero"`
+
+	// URL and Secret of the webhook, only set for claimed deliveries.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
 }
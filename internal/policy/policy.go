@@ -0,0 +1,158 @@
+// Package policy decides which destinations may be shortened.
+package policy
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+// ReloadInterval is how often deny and allow list files are checked for changes.
+const ReloadInterval = 30 * time.Second
+
+// Rule rejects destinations by returning an error that explains why.
+// self is the host the link is being shortened on.
+type Rule interface {
+	Check(u *url.URL, self string) error
+}
+
+// Engine applies rules in order and stops at the first violation.
+// A nil Engine allows every destination.
+type Engine struct {
+	rules  []Rule
+	lists  []*DomainList
+	logger *zap.Logger
+}
+
+// New creates an engine from rules. Deny and allow lists among them are reloaded by Reload.
+func New(logger *zap.Logger, rules ...Rule) *Engine {
+	e := &Engine{
+		rules:  rules,
+		logger: logger,
+	}
+
+	for _, rule := range rules {
+		switch list := rule.(type) {
+		case *DenyList:
+			e.lists = append(e.lists, list.DomainList)
+		case *AllowList:
+			e.lists = append(e.lists, list.DomainList)
+		}
+	}
+
+	return e
+}
+
+func newEngine(lc fx.Lifecycle, cfg *config.Config, logger *zap.Logger) (*Engine, error) {
+	rules := []Rule{PrivateAddress{}, SelfReference{}}
+
+	if cfg.DenyList != "" {
+		list, err := LoadDomainList(cfg.DenyList)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, &DenyList{DomainList: list})
+	}
+
+	if cfg.AllowList != "" {
+		list, err := LoadDomainList(cfg.AllowList)
+		if err != nil {
+			return nil, err
+		}
+
+		rules = append(rules, &AllowList{DomainList: list})
+	}
+
+	e := New(logger, rules...)
+
+	if len(e.lists) == 0 {
+		return e, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go e.reloadLoop(ctx)
+
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+
+			return nil
+		},
+	})
+
+	return e, nil
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newEngine)
+}
+
+// Check returns the first rule violation of rawURL shortened on host self.
+func (e *Engine) Check(rawURL, self string) error {
+	if e == nil {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	for _, rule := range e.rules {
+		err = rule.Check(u, self)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Reload re-reads deny and allow list files that changed since they were loaded.
+// On error the previous contents of a list stay in use.
+func (e *Engine) Reload() {
+	for _, list := range e.lists {
+		reloaded, err := list.Reload()
+		if err != nil {
+			e.logger.Error("Error reloading domain list", zap.String("path", list.path), zap.Error(err))
+			continue
+		}
+
+		if reloaded {
+			e.logger.Info("Domain list reloaded", zap.String("path", list.path))
+		}
+	}
+}
+
+func (e *Engine) reloadLoop(ctx context.Context) {
+	ticker := time.NewTicker(ReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Reload()
+		}
+	}
+}
+
+// hostname returns lowercase host without port and trailing dot.
+func hostname(host string) string {
+	if u, err := url.Parse("//" + host); err == nil {
+		host = u.Hostname()
+	}
+
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
@@ -0,0 +1,111 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func writeList(t *testing.T, path, content string) {
+	t.Helper()
+
+	err := os.WriteFile(path, []byte(content), 0644)
+	require.NoError(t, err)
+}
+
+func TestEngine_Check(t *testing.T) {
+	dir := t.TempDir()
+
+	denyPath := filepath.Join(dir, "deny.txt")
+	writeList(t, denyPath, "# known bad\nevil.com\n\nMALWARE.example\n")
+
+	deny, err := LoadDomainList(denyPath)
+	require.NoError(t, err)
+
+	e := New(zap.NewNop(), PrivateAddress{}, SelfReference{}, &DenyList{DomainList: deny})
+
+	tests := []struct {
+		name    string
+		url     string
+		wantErr error
+	}{
+		{name: "Public domain", url: "https://www.youtube.com/watch"},
+		{name: "Public IP", url: "http://93.184.216.34/"},
+		{name: "Loopback", url: "http://127.0.0.1/admin", wantErr: errs.ErrPrivateAddress},
+		{name: "Loopback IPv6", url: "http://[::1]:8080/", wantErr: errs.ErrPrivateAddress},
+		{name: "Private", url: "http://10.1.2.3/", wantErr: errs.ErrPrivateAddress},
+		{name: "Link-local", url: "http://169.254.169.254/latest/meta-data", wantErr: errs.ErrPrivateAddress},
+		{name: "IPv4-mapped private", url: "http://[::ffff:192.168.0.1]/", wantErr: errs.ErrPrivateAddress},
+		{name: "Unspecified", url: "http://0.0.0.0/", wantErr: errs.ErrPrivateAddress},
+		{name: "Shorthand loopback", url: "http://127.1/", wantErr: errs.ErrPrivateAddress},
+		{name: "Decimal loopback", url: "http://2130706433/", wantErr: errs.ErrPrivateAddress},
+		{name: "Hex loopback", url: "http://0x7f000001/", wantErr: errs.ErrPrivateAddress},
+		{name: "Octal loopback", url: "http://0177.0.0.1/", wantErr: errs.ErrPrivateAddress},
+		{name: "Decimal public", url: "http://1572395042/"},
+		{name: "Numeric labels of a domain", url: "http://1.2.3.4.5/"},
+		{name: "Localhost", url: "http://LocalHost:3000/", wantErr: errs.ErrPrivateAddress},
+		{name: "Self", url: "http://short.example:8080/qxDvSD", wantErr: errs.ErrSelfReferential},
+		{name: "Denied domain", url: "https://evil.com/", wantErr: errs.ErrDeniedDomain},
+		{name: "Denied subdomain", url: "https://login.Evil.com./", wantErr: errs.ErrDeniedDomain},
+		{name: "Denied uppercase entry", url: "https://malware.example/", wantErr: errs.ErrDeniedDomain},
+		{name: "Lookalike is not denied", url: "https://notevil.com/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := e.Check(tt.url, "short.example:8080")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEngine_AllowList(t *testing.T) {
+	allowPath := filepath.Join(t.TempDir(), "allow.txt")
+	writeList(t, allowPath, "corp.example\n")
+
+	allow, err := LoadDomainList(allowPath)
+	require.NoError(t, err)
+
+	e := New(zap.NewNop(), &AllowList{DomainList: allow})
+
+	assert.NoError(t, e.Check("https://wiki.corp.example/page", ""))
+	assert.ErrorIs(t, e.Check("https://www.youtube.com", ""), errs.ErrNotAllowedDomain)
+}
+
+func TestEngine_Reload(t *testing.T) {
+	denyPath := filepath.Join(t.TempDir(), "deny.txt")
+	writeList(t, denyPath, "evil.com\n")
+
+	deny, err := LoadDomainList(denyPath)
+	require.NoError(t, err)
+
+	e := New(zap.NewNop(), &DenyList{DomainList: deny})
+	require.ErrorIs(t, e.Check("https://evil.com", ""), errs.ErrDeniedDomain)
+
+	writeList(t, denyPath, "worse.com\n")
+	future := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(denyPath, future, future))
+
+	e.Reload()
+
+	assert.NoError(t, e.Check("https://evil.com", ""))
+	assert.ErrorIs(t, e.Check("https://worse.com", ""), errs.ErrDeniedDomain)
+
+	// A broken file keeps the previous list.
+	require.NoError(t, os.Remove(denyPath))
+	e.Reload()
+
+	assert.ErrorIs(t, e.Check("https://worse.com", ""), errs.ErrDeniedDomain)
+}
+
+func TestEngine_Nil(t *testing.T) {
+	var e *Engine
+
+	assert.NoError(t, e.Check("http://127.0.0.1", ""))
+}
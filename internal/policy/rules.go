@@ -0,0 +1,228 @@
+package policy
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+)
+
+// PrivateAddress rejects IP literals and localhost names that point inside the network.
+type PrivateAddress struct{}
+
+func (PrivateAddress) Check(u *url.URL, _ string) error {
+	host := hostname(u.Host)
+
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return errs.ErrPrivateAddress
+	}
+
+	ip := parseIPv4(host)
+	if ip == nil {
+		ip = net.ParseIP(host)
+	}
+
+	if ip == nil {
+		return nil
+	}
+
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return errs.ErrPrivateAddress
+	}
+
+	return nil
+}
+
+// parseIPv4 parses host as browsers do (WHATWG URL standard): one to four dot separated parts, each decimal,
+// hex with a 0x prefix or octal with a leading 0, the last one filling the remaining bytes. So 127.1, 2130706433,
+// 0x7f000001 and 0177.0.0.1 are all 127.0.0.1. It returns nil if host isn't an IPv4 address.
+func parseIPv4(host string) net.IP {
+	parts := strings.Split(host, ".")
+	if len(parts) > 4 {
+		return nil
+	}
+
+	var addr uint64
+	for i, part := range parts {
+		n, ok := parseIPv4Part(part)
+		if !ok {
+			return nil
+		}
+
+		if i < len(parts)-1 {
+			if n > 255 {
+				return nil
+			}
+
+			addr |= n << (8 * (3 - i))
+			continue
+		}
+
+		if n >= 1<<(8*(5-len(parts))) {
+			return nil
+		}
+
+		addr |= n
+	}
+
+	return net.IPv4(byte(addr>>24), byte(addr>>16), byte(addr>>8), byte(addr))
+}
+
+func parseIPv4Part(part string) (uint64, bool) {
+	base := 10
+
+	switch {
+	case strings.HasPrefix(part, "0x") || strings.HasPrefix(part, "0X"):
+		part, base = part[2:], 16
+		if part == "" {
+			return 0, true
+		}
+	case len(part) > 1 && part[0] == '0':
+		part, base = part[1:], 8
+	}
+
+	n, err := strconv.ParseUint(part, base, 64)
+
+	return n, err == nil
+}
+
+// SelfReference rejects links to the host they are shortened on, which would redirect in a loop.
+type SelfReference struct{}
+
+func (SelfReference) Check(u *url.URL, self string) error {
+	if self != "" && hostname(u.Host) == hostname(self) {
+		return errs.ErrSelfReferential
+	}
+
+	return nil
+}
+
+// DenyList rejects domains on the list and their subdomains.
+type DenyList struct {
+	*DomainList
+}
+
+func (l *DenyList) Check(u *url.URL, _ string) error {
+	if l.Contains(u.Host) {
+		return errs.ErrDeniedDomain
+	}
+
+	return nil
+}
+
+// AllowList rejects every domain except those on the list and their subdomains.
+type AllowList struct {
+	*DomainList
+}
+
+func (l *AllowList) Check(u *url.URL, _ string) error {
+	if !l.Contains(u.Host) {
+		return errs.ErrNotAllowedDomain
+	}
+
+	return nil
+}
+
+// DomainList is a set of domains loaded from a file with one domain per line.
+// Empty lines and lines starting with # are ignored.
+type DomainList struct {
+	path    string
+	modTime time.Time
+	domains map[string]struct{}
+	m       sync.RWMutex
+}
+
+// LoadDomainList reads a domain list from path.
+func LoadDomainList(path string) (*DomainList, error) {
+	l := &DomainList{path: path}
+
+	_, err := l.Reload()
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Reload re-reads the file if it was modified since the last load. Reports whether it was re-read.
+func (l *DomainList) Reload() (bool, error) {
+	info, err := os.Stat(l.path)
+	if err != nil {
+		return false, fmt.Errorf("error reading domain list: %w", err)
+	}
+
+	l.m.RLock()
+	unchanged := l.domains != nil && info.ModTime().Equal(l.modTime)
+	l.m.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	domains, err := readDomains(l.path)
+	if err != nil {
+		return false, err
+	}
+
+	l.m.Lock()
+	l.domains = domains
+	l.modTime = info.ModTime()
+	l.m.Unlock()
+
+	return true, nil
+}
+
+// Contains reports whether host or any of its parent domains is on the list.
+func (l *DomainList) Contains(host string) bool {
+	host = hostname(host)
+
+	l.m.RLock()
+	defer l.m.RUnlock()
+
+	for {
+		if _, ok := l.domains[host]; ok {
+			return true
+		}
+
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			return false
+		}
+
+		host = parent
+	}
+}
+
+func readDomains(path string) (map[string]struct{}, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading domain list: %w", err)
+	}
+	defer file.Close()
+
+	domains := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		domains[hostname(line)] = struct{}{}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading domain list: %w", err)
+	}
+
+	return domains, nil
+}
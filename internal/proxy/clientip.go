@@ -0,0 +1,103 @@
+package proxy
+
+import (
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Subnets is a set of networks, IPv4 or IPv6.
+type Subnets []netip.Prefix
+
+// Contains reports whether addr is in any of the networks. An invalid address is in none.
+func (s Subnets) Contains(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range s {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClientIP returns the address of the client that made r. See Resolve.
+func (t *Trust) ClientIP(r *http.Request) netip.Addr {
+	return t.Resolve(r.RemoteAddr, r.Header)
+}
+
+// Resolve returns the address of the client behind a connection from remoteAddr that sent headers h.
+// Forwarding headers are only read when remoteAddr is a trusted proxy. Forwarded takes precedence over
+// X-Forwarded-For, X-Real-IP is used when neither is present. Hops are walked from the one closest
+// to the server, the first one that isn't a trusted proxy is the client, so a client can't spoof its
+// address by sending the headers itself. The address is invalid if remoteAddr can't be parsed.
+func (t *Trust) Resolve(remoteAddr string, h http.Header) netip.Addr {
+	addr, ok := parseAddr(remoteAddr)
+	if !ok {
+		return netip.Addr{}
+	}
+
+	if t == nil || !t.prefixes.Contains(addr) {
+		return addr
+	}
+
+	hops := forwardedFor(h)
+	if len(hops) == 0 {
+		hops = headerList(h.Values("X-Forwarded-For"))
+	}
+	if len(hops) == 0 {
+		hops = headerList(h.Values("X-Real-IP"))
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseAddr(hops[i])
+		if !ok {
+			// Obfuscated or garbled hop, nothing before it can be trusted.
+			return addr
+		}
+
+		addr = hop
+		if !t.prefixes.Contains(addr) {
+			return addr
+		}
+	}
+
+	return addr
+}
+
+// forwardedFor returns the for parameters of every element of the Forwarded headers, closest to the client first.
+// Elements without one are returned as empty strings, so they break the chain of trusted hops.
+func forwardedFor(h http.Header) []string {
+	var hops []string
+
+	for _, element := range headerList(h.Values("Forwarded")) {
+		var hop string
+		for _, pair := range strings.Split(element, ";") {
+			key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(key, "for") {
+				hop = strings.Trim(val, `"`)
+			}
+		}
+
+		hops = append(hops, hop)
+	}
+
+	return hops
+}
+
+// headerList splits comma separated header values into trimmed elements.
+func headerList(values []string) []string {
+	var list []string
+
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			list = append(list, strings.TrimSpace(item))
+		}
+	}
+
+	return list
+}
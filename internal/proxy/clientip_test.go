@@ -0,0 +1,113 @@
+package proxy
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrust_Resolve(t *testing.T) {
+	trust := New(mustPrefixes(t, "10.0.0.0/8, fd00::/8")...)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string][]string
+		want       string
+	}{
+		{
+			name:       "Direct client",
+			remoteAddr: "192.0.2.1:5000",
+			want:       "192.0.2.1",
+		},
+		{
+			name:       "Direct IPv6 client",
+			remoteAddr: "[2001:db8::1%eth0]:5000",
+			want:       "2001:db8::1",
+		},
+		{
+			name:       "Headers of untrusted peer are ignored",
+			remoteAddr: "192.0.2.1:5000",
+			headers: map[string][]string{
+				"X-Forwarded-For": {"203.0.113.7"},
+				"X-Real-Ip":       {"203.0.113.8"},
+				"Forwarded":       {"for=203.0.113.9"},
+			},
+			want: "192.0.2.1",
+		},
+		{
+			name:       "X-Forwarded-For from trusted proxy",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"203.0.113.7"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "Spoofed hops before the client are skipped",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"198.51.100.1, 203.0.113.7", "10.0.0.2"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "All hops trusted",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"10.0.0.3, 10.0.0.2"}},
+			want:       "10.0.0.3",
+		},
+		{
+			name:       "Garbled hop stops the walk",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Forwarded-For": {"203.0.113.7, not-an-ip"}},
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "Forwarded takes precedence",
+			remoteAddr: "[fd00::1]:5000",
+			headers: map[string][]string{
+				"Forwarded":       {`for="[2001:db8::7]:4711";proto=https, for=10.0.0.2`},
+				"X-Forwarded-For": {"203.0.113.7"},
+			},
+			want: "2001:db8::7",
+		},
+		{
+			name:       "Obfuscated Forwarded hop",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"Forwarded": {"for=_hidden"}},
+			want:       "10.0.0.1",
+		},
+		{
+			name:       "X-Real-IP from trusted proxy",
+			remoteAddr: "10.0.0.1:5000",
+			headers:    map[string][]string{"X-Real-Ip": {"::ffff:203.0.113.7"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "Unparsable peer",
+			remoteAddr: "bufconn",
+			want:       "invalid IP",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, trust.Resolve(tt.remoteAddr, http.Header(tt.headers)).String())
+		})
+	}
+
+	var none *Trust
+	assert.Equal(t, netip.MustParseAddr("192.0.2.1"), none.Resolve("192.0.2.1:5000", http.Header{"X-Real-Ip": {"203.0.113.7"}}))
+}
+
+func TestSubnets_Contains(t *testing.T) {
+	prefixes, err := ParsePrefixes("10.0.0.0/8, 2001:db8::/32")
+	assert.NoError(t, err)
+
+	subnets := Subnets(prefixes)
+
+	assert.True(t, subnets.Contains(netip.MustParseAddr("10.1.2.3")))
+	assert.True(t, subnets.Contains(netip.MustParseAddr("::ffff:10.1.2.3")))
+	assert.True(t, subnets.Contains(netip.MustParseAddr("2001:db8::1")))
+	assert.False(t, subnets.Contains(netip.MustParseAddr("192.0.2.1")))
+	assert.False(t, subnets.Contains(netip.Addr{}))
+}
@@ -0,0 +1,163 @@
+// Package proxy resolves details of requests that came through trusted reverse proxies.
+// Forwarding headers are only believed when the direct peer is a trusted proxy,
+// otherwise any client could spoof them.
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"go.uber.org/fx"
+)
+
+// Origin is the scheme and host a request was made to.
+type Origin struct {
+	HTTPS bool
+	Host  string
+}
+
+// Trust holds networks of trusted proxies. A nil Trust trusts no proxy.
+type Trust struct {
+	prefixes Subnets
+}
+
+// New creates a Trust for proxies in prefixes.
+func New(prefixes ...netip.Prefix) *Trust {
+	return &Trust{prefixes: prefixes}
+}
+
+func newTrust(cfg *config.Config) (*Trust, error) {
+	prefixes, err := ParsePrefixes(cfg.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(prefixes...), nil
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newTrust)
+}
+
+// ParsePrefixes parses a comma separated list of CIDRs or single addresses, IPv4 or IPv6.
+func ParsePrefixes(list string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if !strings.Contains(item, "/") {
+			addr, err := netip.ParseAddr(item)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing network %q: %w", item, err)
+			}
+
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(item)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing network %q: %w", item, err)
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return prefixes, nil
+}
+
+// Trusted reports whether remoteAddr, given as host:port or a bare address, is a trusted proxy.
+func (t *Trust) Trusted(remoteAddr string) bool {
+	if t == nil || len(t.prefixes) == 0 {
+		return false
+	}
+
+	addr, ok := parseAddr(remoteAddr)
+	if !ok {
+		return false
+	}
+
+	return t.prefixes.Contains(addr)
+}
+
+// Origin returns the scheme and host r was made to. For requests from trusted proxies they are taken
+// from the Forwarded header, or X-Forwarded-Proto and X-Forwarded-Host if it is absent.
+func (t *Trust) Origin(r *http.Request) Origin {
+	o := Origin{HTTPS: r.TLS != nil, Host: r.Host}
+
+	if !t.Trusted(r.RemoteAddr) {
+		return o
+	}
+
+	proto, host := forwarded(r.Header)
+	if proto == "" && host == "" {
+		proto = firstValue(r.Header.Get("X-Forwarded-Proto"))
+		host = firstValue(r.Header.Get("X-Forwarded-Host"))
+	}
+
+	if proto != "" {
+		o.HTTPS = strings.EqualFold(proto, "https")
+	}
+
+	if host != "" {
+		o.Host = host
+	}
+
+	return o
+}
+
+// forwarded returns proto and host of the first element of the Forwarded header (RFC 7239),
+// which is the one added by the proxy closest to the client.
+func forwarded(h http.Header) (proto, host string) {
+	value := h.Get("Forwarded")
+	if value == "" {
+		return "", ""
+	}
+
+	first, _, _ := strings.Cut(value, ",")
+	for _, pair := range strings.Split(first, ";") {
+		key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			continue
+		}
+
+		val = strings.Trim(val, `"`)
+
+		switch strings.ToLower(key) {
+		case "proto":
+			proto = val
+		case "host":
+			host = val
+		}
+	}
+
+	return proto, host
+}
+
+// firstValue returns the first element of a comma separated header value.
+func firstValue(value string) string {
+	first, _, _ := strings.Cut(value, ",")
+	return strings.TrimSpace(first)
+}
+
+// parseAddr parses host:port or a bare address. IPv4-mapped IPv6 addresses are unmapped.
+func parseAddr(s string) (netip.Addr, bool) {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+
+	addr, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+
+	return addr.Unmap().WithZone(""), true
+}
@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrust_Trusted(t *testing.T) {
+	prefixes, err := ParsePrefixes("10.0.0.0/8, 192.168.1.10, fd00::/8")
+	require.NoError(t, err)
+
+	trust := New(prefixes...)
+
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "10.1.2.3:4567", want: true},
+		{addr: "10.1.2.3", want: true},
+		{addr: "192.168.1.10:80", want: true},
+		{addr: "192.168.1.11:80", want: false},
+		{addr: "[fd12::1]:443", want: true},
+		{addr: "[::ffff:10.0.0.1]:443", want: true},
+		{addr: "[2001:db8::1]:443", want: false},
+		{addr: "bufconn", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, trust.Trusted(tt.addr))
+		})
+	}
+
+	var none *Trust
+	assert.False(t, none.Trusted("10.1.2.3:4567"))
+}
+
+func TestParsePrefixes(t *testing.T) {
+	_, err := ParsePrefixes("10.0.0.0/8,not-an-ip")
+	assert.Error(t, err)
+
+	prefixes, err := ParsePrefixes("")
+	require.NoError(t, err)
+	assert.Empty(t, prefixes)
+}
+
+func TestTrust_Origin(t *testing.T) {
+	trust := New(mustPrefixes(t, "10.0.0.0/8")...)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		tls        bool
+		headers    map[string]string
+		want       Origin
+	}{
+		{
+			name:       "Direct request",
+			remoteAddr: "203.0.113.7:1234",
+			want:       Origin{Host: "backend:8080"},
+		},
+		{
+			name:       "Direct TLS request",
+			remoteAddr: "203.0.113.7:1234",
+			tls:        true,
+			want:       Origin{HTTPS: true, Host: "backend:8080"},
+		},
+		{
+			name:       "Spoofed headers from untrusted client",
+			remoteAddr: "203.0.113.7:1234",
+			headers:    map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "evil.example"},
+			want:       Origin{Host: "backend:8080"},
+		},
+		{
+			name:       "X-Forwarded from trusted proxy",
+			remoteAddr: "10.0.0.2:1234",
+			headers:    map[string]string{"X-Forwarded-Proto": "https", "X-Forwarded-Host": "sho.rt, inner.proxy"},
+			want:       Origin{HTTPS: true, Host: "sho.rt"},
+		},
+		{
+			name:       "Forwarded from trusted proxy",
+			remoteAddr: "10.0.0.2:1234",
+			headers: map[string]string{
+				"Forwarded":        `for=198.51.100.1;proto=https;host="sho.rt", for=10.0.0.3;proto=http;host=inner`,
+				"X-Forwarded-Host": "ignored.example",
+			},
+			want: Origin{HTTPS: true, Host: "sho.rt"},
+		},
+		{
+			name:       "Forwarded without host",
+			remoteAddr: "10.0.0.2:1234",
+			headers:    map[string]string{"Forwarded": "for=198.51.100.1;proto=https"},
+			want:       Origin{HTTPS: true, Host: "backend:8080"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Host = "backend:8080"
+			r.RemoteAddr = tt.remoteAddr
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			assert.Equal(t, tt.want, trust.Origin(r))
+		})
+	}
+}
+
+func mustPrefixes(t *testing.T, list string) []netip.Prefix {
+	t.Helper()
+
+	prefixes, err := ParsePrefixes(list)
+	require.NoError(t, err)
+
+	return prefixes
+}
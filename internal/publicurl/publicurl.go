@@ -0,0 +1,126 @@
+// Package publicurl builds the base of generated short URLs.
+package publicurl
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"go.uber.org/fx"
+)
+
+// Builder builds short URL bases from PUBLIC_URL, or from the origin of a request when it is not set.
+// A nil Builder uses the origin of the request as is.
+type Builder struct {
+	// public is PUBLIC_URL ending with a slash, empty if not set.
+	public string
+	// host, scheme and publicPath are parts of PUBLIC_URL, publicPath doesn't end with a slash.
+	host       string
+	scheme     string
+	publicPath string
+	// path is the path the server is mounted on, used with request origins.
+	path    string
+	proxies *proxy.Trust
+}
+
+// New creates a Builder. publicURL must be an absolute URL or empty, basePath is the path routes are mounted on.
+func New(publicURL, basePath string, proxies *proxy.Trust) (*Builder, error) {
+	b := &Builder{path: basePath, proxies: proxies}
+
+	if publicURL != "" {
+		u, err := url.Parse(publicURL)
+		if err != nil {
+			return nil, err
+		}
+
+		b.host = u.Host
+		b.scheme = u.Scheme
+		b.publicPath = strings.TrimSuffix(u.Path, "/")
+		b.public = publicURL
+		if b.public[len(b.public)-1] != '/' {
+			b.public += "/"
+		}
+	}
+
+	return b, nil
+}
+
+func newBuilder(cfg *config.Config, proxies *proxy.Trust) (*Builder, error) {
+	return New(cfg.PublicURL, cfg.Base, proxies)
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newBuilder)
+}
+
+// Origin returns the scheme and host r was made to, honoring forwarding headers of trusted proxies.
+func (b *Builder) Origin(r *http.Request) proxy.Origin {
+	if b == nil {
+		return proxy.Origin{HTTPS: r.TLS != nil, Host: r.Host}
+	}
+
+	return b.proxies.Origin(r)
+}
+
+// Base returns the base short URL IDs are appended to for a request made to o.
+func (b *Builder) Base(o proxy.Origin) string {
+	if b != nil && b.public != "" {
+		return b.public
+	}
+
+	scheme := "http"
+	if o.HTTPS {
+		scheme = "https"
+	}
+
+	var path string
+	if b != nil {
+		path = b.path
+	}
+
+	return scheme + "://" + o.Host + path + "/"
+}
+
+// Host returns the host links are shortened on for a request made to o.
+func (b *Builder) Host(o proxy.Origin) string {
+	if b != nil && b.host != "" {
+		return b.host
+	}
+
+	return o.Host
+}
+
+// DomainBase returns the base short URL of links on domain for a request made to o. Links on an empty domain
+// or on the host of Base, whatever its port, get Base, others keep its scheme and path and replace the host.
+func (b *Builder) DomainBase(o proxy.Origin, domain string) string {
+	if domain == "" || strings.EqualFold(domain, hostname(b.Host(o))) {
+		return b.Base(o)
+	}
+
+	scheme := "http"
+	if o.HTTPS {
+		scheme = "https"
+	}
+
+	var path string
+	if b != nil {
+		path = b.path
+		if b.public != "" {
+			scheme, path = b.scheme, b.publicPath
+		}
+	}
+
+	return scheme + "://" + domain + path + "/"
+}
+
+// hostname returns host without port.
+func hostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+
+	return host
+}
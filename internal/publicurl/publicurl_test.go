@@ -0,0 +1,91 @@
+package publicurl
+
+import (
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name      string
+		publicURL string
+		basePath  string
+		origin    proxy.Origin
+		wantBase  string
+		wantHost  string
+	}{
+		{
+			name:     "From request",
+			origin:   proxy.Origin{Host: "localhost:8080"},
+			wantBase: "http://localhost:8080/",
+			wantHost: "localhost:8080",
+		},
+		{
+			name:     "From TLS request with base path",
+			basePath: "/s",
+			origin:   proxy.Origin{HTTPS: true, Host: "sho.rt"},
+			wantBase: "https://sho.rt/s/",
+			wantHost: "sho.rt",
+		},
+		{
+			name:      "Public URL",
+			publicURL: "https://sho.rt/go",
+			basePath:  "/s",
+			origin:    proxy.Origin{Host: "backend:8080"},
+			wantBase:  "https://sho.rt/go/",
+			wantHost:  "sho.rt",
+		},
+		{
+			name:      "Public URL with trailing slash",
+			publicURL: "https://sho.rt/",
+			origin:    proxy.Origin{Host: "evil.example"},
+			wantBase:  "https://sho.rt/",
+			wantHost:  "sho.rt",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := New(tt.publicURL, tt.basePath, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantBase, b.Base(tt.origin))
+			assert.Equal(t, tt.wantHost, b.Host(tt.origin))
+		})
+	}
+}
+
+func TestBuilder_Nil(t *testing.T) {
+	var b *Builder
+
+	assert.Equal(t, "https://sho.rt/", b.Base(proxy.Origin{HTTPS: true, Host: "sho.rt"}))
+	assert.Equal(t, "sho.rt", b.Host(proxy.Origin{Host: "sho.rt"}))
+}
+
+func TestBuilder_DomainBase(t *testing.T) {
+	request, err := New("", "/s", nil)
+	require.NoError(t, err)
+
+	public, err := New("https://sho.rt/go", "/s", nil)
+	require.NoError(t, err)
+
+	o := proxy.Origin{Host: "localhost:8080"}
+
+	assert.Equal(t, "http://localhost:8080/s/", request.DomainBase(o, ""))
+	assert.Equal(t, "http://brand.ly/s/", request.DomainBase(o, "brand.ly"))
+	assert.Equal(t, "https://sho.rt/go/", public.DomainBase(o, "sho.rt"))
+	assert.Equal(t, "https://brand.ly/go/", public.DomainBase(o, "brand.ly"))
+
+	// A registered domain served on a non-default port keeps the port of the request.
+	o = proxy.Origin{HTTPS: true, Host: "brand.ly:8443"}
+
+	assert.Equal(t, "https://brand.ly:8443/s/", request.DomainBase(o, "brand.ly"))
+
+	publicPort, err := New("https://sho.rt:8443/go", "/s", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://sho.rt:8443/go/", publicPort.DomainBase(o, "sho.rt"))
+}
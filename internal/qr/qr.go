@@ -0,0 +1,130 @@
+// Package qr renders QR codes for short links.
+package qr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 1024
+)
+
+// DefaultLevel recovers about 15% of damaged code, enough for printed posters.
+const DefaultLevel = "M"
+
+var levels = map[string]qrcode.RecoveryLevel{
+	"L": qrcode.Low,
+	"M": qrcode.Medium,
+	"Q": qrcode.High,
+	"H": qrcode.Highest,
+}
+
+// Options describe how a QR code is rendered.
+type Options struct {
+	Format string
+	Size   int
+	Level  string
+}
+
+// NewOptions validates options and fills in defaults for the empty ones.
+// The level is case-insensitive.
+func NewOptions(format string, size int, level string) (Options, error) {
+	opts := Options{
+		Format: strings.ToLower(format),
+		Size:   size,
+		Level:  strings.ToUpper(level),
+	}
+
+	if opts.Format == "" {
+		opts.Format = FormatPNG
+	}
+
+	if opts.Format != FormatPNG && opts.Format != FormatSVG {
+		return Options{}, errs.ErrInvalidQRFormat
+	}
+
+	if opts.Size == 0 {
+		opts.Size = DefaultSize
+	}
+
+	if opts.Size < MinSize || opts.Size > MaxSize {
+		return Options{}, errs.ErrInvalidQRSize
+	}
+
+	if opts.Level == "" {
+		opts.Level = DefaultLevel
+	}
+
+	if _, ok := levels[opts.Level]; !ok {
+		return Options{}, errs.ErrInvalidQRLevel
+	}
+
+	return opts, nil
+}
+
+// ContentType returns the MIME type of the rendered image.
+func (o Options) ContentType() string {
+	if o.Format == FormatSVG {
+		return "image/svg+xml"
+	}
+
+	return "image/png"
+}
+
+// ETag identifies the image rendered for content with these options.
+// Rendering is deterministic, so the tag is known without rendering the image.
+func (o Options) ETag(content string) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{content, o.Format, strconv.Itoa(o.Size), o.Level}, "|")))
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Encode renders content as a QR code.
+func Encode(content string, opts Options) ([]byte, error) {
+	code, err := qrcode.New(content, levels[opts.Level])
+	if err != nil {
+		return nil, fmt.Errorf("error encoding qr code: %w", err)
+	}
+
+	if opts.Format == FormatSVG {
+		return svg(code.Bitmap(), opts.Size), nil
+	}
+
+	return code.PNG(opts.Size)
+}
+
+// svg draws every dark module as a unit square of a single path, scaled to size by the viewBox.
+func svg(bitmap [][]bool, size int) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		size, size, len(bitmap), len(bitmap))
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#fff"/><path fill="#000" d="`)
+
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+
+	buf.WriteString(`"/></svg>`)
+
+	return buf.Bytes()
+}
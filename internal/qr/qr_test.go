@@ -0,0 +1,93 @@
+package qr
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		size    int
+		level   string
+		want    Options
+		wantErr error
+	}{
+		{
+			name: "Defaults",
+			want: Options{Format: FormatPNG, Size: DefaultSize, Level: DefaultLevel},
+		},
+		{
+			name:   "Custom",
+			format: "SVG",
+			size:   512,
+			level:  "h",
+			want:   Options{Format: FormatSVG, Size: 512, Level: "H"},
+		},
+		{
+			name:    "Unknown format",
+			format:  "gif",
+			wantErr: errs.ErrInvalidQRFormat,
+		},
+		{
+			name:    "Too small",
+			size:    16,
+			wantErr: errs.ErrInvalidQRSize,
+		},
+		{
+			name:    "Too big",
+			size:    4096,
+			wantErr: errs.ErrInvalidQRSize,
+		},
+		{
+			name:    "Unknown level",
+			level:   "X",
+			wantErr: errs.ErrInvalidQRLevel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewOptions(tt.format, tt.size, tt.level)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	const content = "http://localhost:8080/qxDvSD"
+
+	t.Run("PNG", func(t *testing.T) {
+		data, err := Encode(content, Options{Format: FormatPNG, Size: 128, Level: "M"})
+		require.NoError(t, err)
+
+		img, err := png.Decode(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, 128, img.Bounds().Dx())
+		assert.Equal(t, 128, img.Bounds().Dy())
+	})
+
+	t.Run("SVG", func(t *testing.T) {
+		data, err := Encode(content, Options{Format: FormatSVG, Size: 128, Level: "M"})
+		require.NoError(t, err)
+
+		assert.True(t, bytes.HasPrefix(data, []byte("<svg ")))
+		assert.Contains(t, string(data), `width="128"`)
+		assert.True(t, bytes.HasSuffix(data, []byte("</svg>")))
+	})
+}
+
+func TestOptions_ETag(t *testing.T) {
+	opts := Options{Format: FormatPNG, Size: 256, Level: "M"}
+
+	assert.Equal(t, opts.ETag("a"), opts.ETag("a"))
+	assert.NotEqual(t, opts.ETag("a"), opts.ETag("b"))
+	assert.NotEqual(t, opts.ETag("a"), Options{Format: FormatSVG, Size: 256, Level: "M"}.ETag("a"))
+}
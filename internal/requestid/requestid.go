@@ -0,0 +1,43 @@
+// Package requestid assigns IDs to requests. A client or a proxy in front of the server may pass its own
+// ID, so a request can be followed through their logs too.
+package requestid
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	// Header carries the request ID of HTTP requests and responses.
+	Header = "X-Request-Id"
+	// MetadataKey carries the request ID of gRPC calls.
+	MetadataKey = "x-request-id"
+
+	maxLength = 128
+)
+
+// Resolve returns id if it is a usable request ID, otherwise a new one.
+// IDs end up in logs, so only short IDs of letters, digits and -._: are accepted.
+func Resolve(id string) string {
+	if valid(id) {
+		return id
+	}
+
+	return uuid.NewString()
+}
+
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '.', c == '_', c == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
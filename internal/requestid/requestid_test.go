@@ -0,0 +1,36 @@
+package requestid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		keep bool
+	}{
+		{name: "Empty", id: ""},
+		{name: "UUID", id: "3f0e8c1a-5b2d-4e6f-8a9b-0c1d2e3f4a5b", keep: true},
+		{name: "Proxy ID", id: "req_01:edge.1", keep: true},
+		{name: "Too long", id: strings.Repeat("a", maxLength+1)},
+		{name: "Log injection", id: "abc\nlevel=error"},
+		{name: "Spaces", id: "abc def"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Resolve(tt.id)
+
+			if tt.keep {
+				assert.Equal(t, tt.id, got)
+			} else {
+				assert.NoError(t, uuid.Validate(got))
+			}
+		})
+	}
+}
@@ -7,27 +7,66 @@ import (
 
 	"github.com/MukizuL/shortener/internal/config"
 	"github.com/MukizuL/shortener/internal/controller"
+	"github.com/MukizuL/shortener/internal/gateway"
 	mw "github.com/MukizuL/shortener/internal/middleware"
+	pb "github.com/MukizuL/shortener/proto"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/fx"
 )
 
 // NewRouter initializes new chi.Mux with routes.
-func NewRouter(cfg *config.Config, mw *mw.MiddlewareService, c *controller.Controller) *chi.Mux {
+func NewRouter(cfg *config.Config, mw *mw.MiddlewareService, c *controller.Controller, gw *gateway.Gateway) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(mw.GzipCompress)
+	r.Use(mw.RequestID)
 	r.Use(mw.LoggerMW)
+	r.Use(mw.ClientIP)
 
 	r.With(mw.Authorization).Post(cfg.Base+"/", c.CreateShortURL)
-	r.Get(cfg.Base+"/{id}", c.GetFullURL)
+	r.With(mw.Identify).Get(cfg.Base+"/{id}", c.GetFullURL)
+	r.Head(cfg.Base+"/{id}", c.GetFullURL)
+	r.Post(cfg.Base+"/{id}", c.GetFullURL)
+	r.Get(cfg.Base+"/{id}/qr", c.GetQRCode)
+	r.Post(cfg.Base+"/{id}/report", c.ReportLink)
 	r.Get(cfg.Base+"/ping", c.Ping)
 
 	r.With(mw.Authorization).Get(cfg.Base+"/api/user/urls", c.GetURLs)
 	r.With(mw.Authorization).Delete(cfg.Base+"/api/user/urls", c.DeleteURLs)
+	r.With(mw.Authorization).Get(cfg.Base+"/api/user/events", c.StreamActivity)
+	r.With(mw.Authorization).Post(cfg.Base+"/api/user/webhooks", c.CreateWebhook)
+	r.With(mw.Authorization).Get(cfg.Base+"/api/user/webhooks", c.GetWebhooks)
+	r.With(mw.Authorization).Delete(cfg.Base+"/api/user/webhooks/{webhookID}", c.DeleteWebhook)
+	r.With(mw.Authorization).Get(cfg.Base+"/api/user/webhooks/{webhookID}/deliveries", c.GetWebhookDeliveries)
+	r.With(mw.Authorization).Post(cfg.Base+"/api/user/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver", c.RedeliverWebhook)
 	r.With(mw.Authorization).Post(cfg.Base+"/api/shorten", c.CreateShortURLJSON)
 	r.With(mw.Authorization).Post(cfg.Base+"/api/shorten/batch", c.BatchCreateShortURLJSON)
+	r.With(mw.Authorization).Post(cfg.Base+"/api/shorten/stream", c.StreamCreateShortURL)
+	r.With(mw.Identify).Get(cfg.Base+"/api/links/{id}", c.GetLinkInfo)
 	r.With(mw.IsTrustedCIDR).Get(cfg.Base+"/api/internal/stats", c.GetStats)
 
+	r.With(mw.Admin).Get(cfg.Base+"/api/admin/links", c.FindLinks)
+	r.With(mw.Admin).Get(cfg.Base+"/api/admin/users/{userID}/links", c.GetUserLinks)
+	r.With(mw.Admin).Post(cfg.Base+"/api/admin/links/{id}/disable", c.DisableLink)
+	r.With(mw.Admin).Delete(cfg.Base+"/api/admin/links/{id}/disable", c.EnableLink)
+	r.With(mw.Admin).Post(cfg.Base+"/api/admin/hosts/{host}/disable", c.DisableHost)
+	r.With(mw.Admin).Get(cfg.Base+"/api/admin/reports", c.GetReports)
+	r.With(mw.Admin).Post(cfg.Base+"/api/admin/reports/{reportID}/approve", c.ApproveReport)
+	r.With(mw.Admin).Post(cfg.Base+"/api/admin/reports/{reportID}/dismiss", c.DismissReport)
+	r.With(mw.Admin).Get(cfg.Base+"/api/admin/audit", c.GetAuditLog)
+
+	r.With(mw.Authorization).Post(cfg.Base+"/api/workspaces", c.CreateWorkspace)
+	r.With(mw.Authorization).Get(cfg.Base+"/api/workspaces", c.GetWorkspaces)
+	r.With(mw.Authorization).Post(cfg.Base+"/api/workspaces/{id}/members", c.AddWorkspaceMember)
+	r.With(mw.Authorization).Delete(cfg.Base+"/api/workspaces/{id}/members/{userID}", c.RemoveWorkspaceMember)
+
+	if gw != nil {
+		r.Handle(cfg.Base+gateway.Prefix+"/*", http.StripPrefix(cfg.Base, gw))
+		r.Get(cfg.Base+gateway.Prefix+"/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Write(pb.OpenAPI)
+		})
+	}
+
 	r.Mount("/debug", Profiler())
 
 	return r
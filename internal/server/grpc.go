@@ -6,6 +6,7 @@ import (
 
 	"github.com/MukizuL/shortener/internal/config"
 	"github.com/MukizuL/shortener/internal/controller"
+	"github.com/MukizuL/shortener/internal/gateway"
 	"github.com/MukizuL/shortener/internal/interceptor"
 	"github.com/MukizuL/shortener/internal/migration"
 	"github.com/MukizuL/shortener/internal/storage"
@@ -13,6 +14,10 @@ import (
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 type GRPCFxIn struct {
@@ -20,10 +25,12 @@ type GRPCFxIn struct {
 
 	Lc          fx.Lifecycle
 	Ctrl        *controller.Controller
+	Admin       *controller.AdminServer
 	Cfg         *config.Config
 	Logger      *zap.Logger
 	Interceptor *interceptor.Service
 	Storage     storage.Repo
+	Gateway     *gateway.Gateway
 	Migrator    *migration.Migrator
 }
 
@@ -33,34 +40,91 @@ func newGRPCServer(in GRPCFxIn) (*grpc.Server, error) {
 		return nil, nil
 	}
 
-	ln, err := net.Listen("tcp", in.Cfg.GRPCPort)
-	if err != nil {
-		return nil, err
+	var creds []grpc.ServerOption
+
+	if in.Cfg.HTTPS {
+		tlsConfig, err := grpcTLSConfig(in.Cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		creds = append(creds, grpc.Creds(credentials.NewTLS(tlsConfig)))
 	}
 
-	s := grpc.NewServer(
+	opts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
+			in.Interceptor.Recovery,
+			in.Interceptor.RequestID,
+			in.Interceptor.ClientIP,
 			in.Interceptor.Logger,
 			in.Interceptor.Auth,
 			in.Interceptor.IsTrustedCIDR,
+			in.Interceptor.Admin,
+		),
+		grpc.ChainStreamInterceptor(
+			in.Interceptor.StreamRecovery,
+			in.Interceptor.StreamRequestID,
+			in.Interceptor.StreamClientIP,
+			in.Interceptor.StreamLogger,
+			in.Interceptor.StreamAuth,
 		),
-	)
+	}
 
-	pb.RegisterShortenerServer(s, in.Ctrl)
+	ln, err := net.Listen("tcp", in.Cfg.GRPCPort)
+	if err != nil {
+		return nil, err
+	}
+
+	hs := health.NewServer()
+
+	s := grpc.NewServer(append(creds, opts...)...)
+	registerServices(s, in, hs)
+
+	// Credentials apply to every listener of a server, while the gateway dials its in-memory one in plaintext.
+	// It gets a server of its own with the same services and interceptors.
+	var local *grpc.Server
+	if in.Gateway != nil {
+		local = grpc.NewServer(opts...)
+		registerServices(local, in, hs)
+	}
+
+	healthCtx, cancel := context.WithCancel(context.Background())
 
 	in.Lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			in.Logger.Info("Starting GRPC server", zap.String("port", in.Cfg.GRPCPort))
+			go watchHealth(healthCtx, hs, in.Storage, HealthCheckInterval, in.Logger)
 			go s.Serve(ln)
 
+			if local != nil {
+				go local.Serve(in.Gateway.Listener())
+			}
+
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
+			cancel()
+			drain(ctx, hs, in.Cfg.GRPCDrainDelay)
 			go s.GracefulStop()
 
+			if local != nil {
+				go local.GracefulStop()
+			}
+
 			return nil
 		},
 	})
 
 	return s, nil
 }
+
+// registerServices registers the gRPC API on s.
+func registerServices(s *grpc.Server, in GRPCFxIn, hs *health.Server) {
+	pb.RegisterShortenerServer(s, in.Ctrl)
+	pb.RegisterAdminServer(s, in.Admin)
+	healthpb.RegisterHealthServer(s, hs)
+
+	if in.Cfg.GRPCReflection {
+		reflection.Register(s)
+	}
+}
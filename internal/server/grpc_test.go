@@ -0,0 +1,123 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/admin"
+	"github.com/MukizuL/shortener/internal/clicks"
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/controller"
+	"github.com/MukizuL/shortener/internal/domain"
+	"github.com/MukizuL/shortener/internal/gateway"
+	"github.com/MukizuL/shortener/internal/interceptor"
+	jwtService "github.com/MukizuL/shortener/internal/jwt"
+	"github.com/MukizuL/shortener/internal/limiter"
+	"github.com/MukizuL/shortener/internal/migration"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/proxy"
+	"github.com/MukizuL/shortener/internal/publicurl"
+	"github.com/MukizuL/shortener/internal/service"
+	"github.com/MukizuL/shortener/internal/storage"
+	"github.com/MukizuL/shortener/internal/storage/mapstorage"
+	"github.com/MukizuL/shortener/internal/storage/pgstorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxtest"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+func TestGRPCServer_GatewayWithTLS(t *testing.T) {
+	dir := t.TempDir()
+	cert, pk := writeCertificate(t, dir)
+
+	cfg := &config.Config{
+		Filepath:  filepath.Join(dir, "storage.json"),
+		HTTPS:     true,
+		Cert:      cert,
+		PK:        pk,
+		GRPCPort:  "127.0.0.1:0",
+		PublicURL: "https://short.example",
+	}
+
+	var gw *gateway.Gateway
+	app := fxtest.New(t,
+		fx.Supply(cfg),
+		fx.Provide(zap.NewNop),
+		fx.Provide(newGRPCServer),
+		service.Provide(),
+		controller.Provide(),
+		jwtService.Provide(),
+		interceptor.Provide(),
+		gateway.Provide(),
+		limiter.Provide(),
+		clicks.Provide(),
+		policy.Provide(),
+		proxy.Provide(),
+		publicurl.Provide(),
+		domain.Provide(),
+		admin.Provide(),
+		pgstorage.Provide(),
+		mapstorage.Provide(),
+		storage.Provide(),
+		migration.Provide(),
+		fx.Invoke(func(*grpc.Server) {}),
+		fx.Populate(&gw),
+	)
+	app.RequireStart()
+	defer app.RequireStop()
+
+	r := httptest.NewRequest(http.MethodPost, gateway.Prefix+"/urls", strings.NewReader(`{"original_url":"https://www.youtube.com"}`))
+	w := httptest.NewRecorder()
+	gw.ServeHTTP(w, r)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.True(t, strings.HasPrefix(resp["shortUrl"].(string), "https://short.example/"))
+}
+
+// writeCertificate writes a self-signed certificate for localhost and its key to dir and returns their paths.
+func writeCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	cert := filepath.Join(dir, "cert.pem")
+	pk := filepath.Join(dir, "pk.pem")
+	require.NoError(t, os.WriteFile(cert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(pk, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+
+	return cert, pk
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/storage"
+	pb "github.com/MukizuL/shortener/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// HealthCheckInterval is how often storage is pinged to update the gRPC health status.
+	HealthCheckInterval = 10 * time.Second
+	healthCheckTimeout  = 3 * time.Second
+)
+
+// checkHealth sets the status of the server and the Shortener service from a storage ping.
+func checkHealth(ctx context.Context, hs *health.Server, repo storage.Repo, logger *zap.Logger) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	status := healthpb.HealthCheckResponse_SERVING
+
+	err := repo.Ping(ctx)
+	if err != nil {
+		logger.Warn("Storage ping failed", zap.Error(err))
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	hs.SetServingStatus("", status)
+	hs.SetServingStatus(pb.Shortener_ServiceDesc.ServiceName, status)
+}
+
+// watchHealth checks health every interval until ctx is done.
+func watchHealth(ctx context.Context, hs *health.Server, repo storage.Repo, interval time.Duration, logger *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		checkHealth(ctx, hs, repo, logger)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain reports NOT_SERVING, then waits delay or until ctx is done, so that load balancers polling health checks
+// stop sending new calls before the server stops taking them.
+func drain(ctx context.Context, hs *health.Server, delay time.Duration) {
+	hs.Shutdown()
+
+	if delay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	pb "github.com/MukizuL/shortener/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestCheckHealth(t *testing.T) {
+	tests := []struct {
+		name    string
+		pingErr error
+		want    healthpb.HealthCheckResponse_ServingStatus
+	}{
+		{name: "Storage is up", want: healthpb.HealthCheckResponse_SERVING},
+		{name: "Storage is down", pingErr: errors.New("connection refused"), want: healthpb.HealthCheckResponse_NOT_SERVING},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().Ping(gomock.Any()).Return(tt.pingErr)
+
+			hs := health.NewServer()
+			checkHealth(context.Background(), hs, mockRepo, zap.NewNop())
+
+			for _, service := range []string{"", pb.Shortener_ServiceDesc.ServiceName} {
+				resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, resp.Status)
+			}
+		})
+	}
+}
+
+func TestCheckHealth_Shutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockstorage.NewMockRepo(ctrl)
+	mockRepo.EXPECT().Ping(gomock.Any()).Return(nil)
+
+	hs := health.NewServer()
+	hs.Shutdown()
+
+	// Pings after shutdown must not bring the server back.
+	checkHealth(context.Background(), hs, mockRepo, zap.NewNop())
+
+	resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
+
+func TestDrain(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	hs := health.NewServer()
+
+	done := make(chan struct{})
+	start := time.Now()
+	go func() {
+		drain(context.Background(), hs, delay)
+		close(done)
+	}()
+
+	// NOT_SERVING is reported right away, before the delay is over.
+	assert.Eventually(t, func() bool {
+		resp, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		return err == nil && resp.Status == healthpb.HealthCheckResponse_NOT_SERVING
+	}, delay, time.Millisecond)
+
+	<-done
+	assert.GreaterOrEqual(t, time.Since(start), delay)
+
+	// A stop deadline cuts the delay short.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start = time.Now()
+	drain(ctx, hs, time.Hour)
+	assert.Less(t, time.Since(start), time.Second)
+}
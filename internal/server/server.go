@@ -2,7 +2,6 @@ package server
 
 import (
 	"context"
-	"crypto/tls"
 	"net"
 	"net/http"
 	"time"
@@ -51,20 +50,7 @@ func newHTTPServer(in HTTPFxIn) *http.Server {
 			} else {
 				in.Logger.Info("Starting HTTPS server", zap.String("addr", srv.Addr))
 
-				tlsConfig := &tls.Config{
-					CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256}, // They have assembly implementation
-					MinVersion:       tls.VersionTLS12,
-					CipherSuites: []uint16{
-						tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-						tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-						tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-						tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-						tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
-						tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
-					},
-				}
-
-				srv.TLSConfig = tlsConfig
+				srv.TLSConfig = baseTLSConfig()
 
 				go srv.ServeTLS(ln, in.Cfg.Cert, in.Cfg.PK)
 
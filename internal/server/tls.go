@@ -0,0 +1,58 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/MukizuL/shortener/internal/config"
+)
+
+// baseTLSConfig returns TLS settings shared by HTTP and GRPC servers.
+func baseTLSConfig() *tls.Config {
+	return &tls.Config{
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256}, // They have assembly implementation
+		MinVersion:       tls.VersionTLS12,
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+}
+
+// grpcTLSConfig returns TLS settings of the GRPC server with the configured certificate.
+// With a client CA, client certificates are verified when presented. They are optional, as only trusted
+// clients need one, everyone else uses access tokens.
+func grpcTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.Cert, cfg.PK)
+	if err != nil {
+		return nil, fmt.Errorf("error loading certificate: %w", err)
+	}
+
+	tlsConfig := baseTLSConfig()
+	tlsConfig.Certificates = []tls.Certificate{cert}
+
+	if cfg.GRPCClientCA == "" {
+		return tlsConfig, nil
+	}
+
+	bundle, err := os.ReadFile(cfg.GRPCClientCA)
+	if err != nil {
+		return nil, fmt.Errorf("error reading client CA: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bundle) {
+		return nil, fmt.Errorf("no certificates in client CA %s", cfg.GRPCClientCA)
+	}
+
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+)
+
+// FindLinks returns links of every user matching q for moderators. q.Domains are short domain names,
+// the destination is matched as given and in canonical form. Fails with errs.ErrEmptyLinkQuery if q would
+// select every link.
+func (s Service) FindLinks(ctx context.Context, q models.LinkQuery) ([]models.Link, error) {
+	q, err := s.moderationQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.FindLinks(ctx, q)
+}
+
+// UserLinks returns every link created by userID for moderators.
+func (s Service) UserLinks(ctx context.Context, userID string) ([]models.Link, error) {
+	if uuid.Validate(userID) != nil {
+		return nil, errs.ErrInvalidUserID
+	}
+
+	return s.storage.FindLinks(ctx, models.LinkQuery{UserID: userID})
+}
+
+// DisableLink takes down the link with ID on the short domain, the default one if domain is empty.
+// Visitors get t.Status, models.DefaultTakedownStatus if it is zero, and see t.Reason.
+func (s Service) DisableLink(ctx context.Context, domain, ID string, t models.Takedown) error {
+	t, err := checkTakedown(t)
+	if err != nil {
+		return err
+	}
+
+	key, err := s.domains.Select(domain, "")
+	if err != nil {
+		return err
+	}
+
+	return s.setTakedown(ctx, key, ID, t)
+}
+
+// EnableLink reverts DisableLink.
+func (s Service) EnableLink(ctx context.Context, domain, ID string) error {
+	key, err := s.domains.Select(domain, "")
+	if err != nil {
+		return err
+	}
+
+	return s.setTakedown(ctx, key, ID, models.Takedown{})
+}
+
+// DisableHost takes down every link pointing at host or its subdomains on any short domain, like DisableLink.
+// Returns the number of links disabled.
+func (s Service) DisableHost(ctx context.Context, host string, t models.Takedown) (int, error) {
+	t, err := checkTakedown(t)
+	if err != nil {
+		return 0, err
+	}
+
+	host, err = normalizeHost(host)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := s.storage.DisableLinks(ctx, models.LinkQuery{Host: host}, t)
+	if err != nil {
+		return 0, err
+	}
+
+	after := takedownValues(t)
+	after["host"] = host
+	after["disabled"] = n
+	s.audit(ctx, models.AuditEvent{Action: models.AuditHostDisable, After: after})
+
+	return n, nil
+}
+
+// setTakedown sets the takedown of the link with ID on domain, a domain key, and records the change.
+// Fails with errs.ErrURLNotFound if there is no such link.
+func (s Service) setTakedown(ctx context.Context, domain, ID string, t models.Takedown) error {
+	link, err := s.storage.GetLongURL(ctx, domain, ID)
+	if err != nil {
+		if errors.Is(err, errs.ErrGone) {
+			return errs.ErrURLNotFound
+		}
+
+		return err
+	}
+
+	n, err := s.storage.DisableLinks(ctx, models.LinkQuery{ShortURL: ID, Domains: []string{domain}}, t)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return errs.ErrURLNotFound
+	}
+
+	action := models.AuditLinkDisable
+	if t == (models.Takedown{}) {
+		action = models.AuditLinkEnable
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:   action,
+		Domain:   domain,
+		ShortURL: ID,
+		Before:   takedownValues(link.Takedown),
+		After:    takedownValues(t),
+	})
+
+	return nil
+}
+
+// moderationQuery translates q from names moderators use to what storage matches.
+func (s Service) moderationQuery(q models.LinkQuery) (models.LinkQuery, error) {
+	if q.Empty() {
+		return models.LinkQuery{}, errs.ErrEmptyLinkQuery
+	}
+
+	if q.UserID != "" && uuid.Validate(q.UserID) != nil {
+		return models.LinkQuery{}, errs.ErrInvalidUserID
+	}
+
+	domains := make([]string, 0, len(q.Domains))
+	for _, name := range q.Domains {
+		key, err := s.domains.Select(name, "")
+		if err != nil {
+			return models.LinkQuery{}, err
+		}
+
+		domains = append(domains, key)
+	}
+	q.Domains = domains
+
+	if q.Destination != "" {
+		if _, canonicalURL, err := helpers.CheckURL([]byte(q.Destination), s.canon); err == nil {
+			q.Destination = canonicalURL
+		}
+	}
+
+	if q.Host != "" {
+		host, err := normalizeHost(q.Host)
+		if err != nil {
+			return models.LinkQuery{}, err
+		}
+
+		q.Host = host
+	}
+
+	return q, nil
+}
+
+// checkTakedown validates t and fills in the default status.
+func checkTakedown(t models.Takedown) (models.Takedown, error) {
+	if t.Status == 0 {
+		t.Status = models.DefaultTakedownStatus
+	}
+
+	if !models.ValidTakedownStatus(t.Status) {
+		return models.Takedown{}, errs.ErrInvalidTakedownStatus
+	}
+
+	t.Reason = strings.TrimSpace(t.Reason)
+
+	return t, nil
+}
+
+// normalizeHost returns host in the form storage matches it, a URL may be given instead.
+// A bare top-level domain is rejected, it would select a large part of the web.
+func normalizeHost(host string) (string, error) {
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if !strings.Contains(host, ".") || strings.ContainsAny(host, ":/?#@[] ") {
+		return "", errs.ErrMalformedHost
+	}
+
+	return host, nil
+}
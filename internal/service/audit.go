@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultAuditLimit is the number of events returned when a query sets no limit.
+	DefaultAuditLimit = 100
+	// MaxAuditLimit caps the number of events returned by one query.
+	MaxAuditLimit = 1000
+)
+
+// AuditLog returns audit events matching q for moderators, newest first. q.Domains are short domain names.
+func (s Service) AuditLog(ctx context.Context, q models.AuditQuery) ([]models.AuditEvent, error) {
+	if q.ActorID != "" && uuid.Validate(q.ActorID) != nil {
+		return nil, errs.ErrInvalidUserID
+	}
+
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return nil, errs.ErrInvalidTimeRange
+	}
+
+	switch {
+	case q.Limit == 0:
+		q.Limit = DefaultAuditLimit
+	case q.Limit < 0 || q.Limit > MaxAuditLimit:
+		return nil, errs.ErrInvalidLimit
+	}
+
+	domains := make([]string, 0, len(q.Domains))
+	for _, name := range q.Domains {
+		key, err := s.domains.Select(name, "")
+		if err != nil {
+			return nil, err
+		}
+
+		domains = append(domains, key)
+	}
+	q.Domains = domains
+
+	if s.auditor == nil {
+		return nil, nil
+	}
+
+	return s.auditor.QueryAudit(ctx, q)
+}
+
+// audit records events with the actor, the address, the transport and the ID of the request in ctx.
+// The actor is the caller authenticated by the middleware unless an event names one. The change is already
+// made when it is recorded, so failures are logged rather than returned.
+func (s Service) audit(ctx context.Context, events ...models.AuditEvent) {
+	if s.auditor == nil || len(events) == 0 {
+		return
+	}
+
+	p, _ := contextI.PrincipalFrom(ctx)
+	req := contextI.RequestFrom(ctx)
+	ip := contextI.ClientIPFrom(ctx)
+	now := time.Now()
+
+	for i := range events {
+		e := &events[i]
+		e.ID = uuid.NewString()
+		e.Time = now
+		e.Transport = req.Transport
+		e.RequestID = req.ID
+
+		if e.ActorID == "" {
+			e.ActorID = p.UserID
+		}
+
+		if ip.IsValid() {
+			e.ActorIP = ip.String()
+		}
+	}
+
+	// Recorded even if the client went away in the meantime.
+	err := s.auditor.AppendAudit(context.WithoutCancel(ctx), events...)
+	if err != nil {
+		s.logger.Error("Error writing audit log", zap.String("action", events[0].Action),
+			zap.Int("events", len(events)), zap.Error(err))
+	}
+}
+
+// takedownValues describes t in audit events, nil for enabled links.
+func takedownValues(t models.Takedown) map[string]any {
+	if t == (models.Takedown{}) {
+		return nil
+	}
+
+	return map[string]any{"disabled_status": t.Status, "disabled_reason": t.Reason}
+}
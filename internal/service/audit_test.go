@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"testing"
+	"time"
+
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/proxy"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestService_AuditCreate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockstorage.NewMockRepo(ctrl)
+	mockRepo.EXPECT().CreateShortURL(gomock.Any(), "user1", "http://short.example/", "https://www.youtube.com", gomock.Any(), gomock.Any()).
+		Return("http://short.example/qxDvSD", nil)
+
+	var recorded []models.AuditEvent
+	mockAudit := mockstorage.NewMockAuditLog(ctrl)
+	mockAudit.EXPECT().AppendAudit(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, events ...models.AuditEvent) error {
+			recorded = events
+			return nil
+		})
+
+	s := New(Params{
+		Storage: mockRepo,
+		Audit:   mockAudit,
+		Policy:  policy.New(zap.NewNop(), policy.PrivateAddress{}),
+		Logger:  zap.NewNop(),
+	})
+
+	ctx := contextI.WithRequest(context.Background(), contextI.Request{ID: "req-1", Transport: contextI.TransportGRPC})
+	ctx = contextI.WithClientIP(ctx, netip.MustParseAddr("192.0.2.1"))
+
+	_, err := s.CreateShortURL(ctx, "user1", proxy.Origin{Host: "short.example"}, dto.Request{FullURL: "https://www.youtube.com", MaxClicks: 5})
+	require.NoError(t, err)
+
+	require.Len(t, recorded, 1)
+	e := recorded[0]
+	assert.NotEmpty(t, e.ID)
+	assert.WithinDuration(t, time.Now(), e.Time, time.Second)
+	assert.Equal(t, models.AuditLinkCreate, e.Action)
+	assert.Equal(t, "user1", e.ActorID)
+	assert.Equal(t, "192.0.2.1", e.ActorIP)
+	assert.Equal(t, contextI.TransportGRPC, e.Transport)
+	assert.Equal(t, "req-1", e.RequestID)
+	assert.Equal(t, "qxDvSD", e.ShortURL)
+	assert.Empty(t, e.Before)
+	assert.Equal(t, "https://www.youtube.com", e.After["original_url"])
+	assert.Equal(t, 5, e.After["max_clicks"])
+}
+
+func TestService_AuditLog(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		q         models.AuditQuery
+		wantQuery models.AuditQuery
+		wantErr   error
+	}{
+		{
+			name:      "Default limit",
+			q:         models.AuditQuery{ShortURL: "qxDvSD"},
+			wantQuery: models.AuditQuery{ShortURL: "qxDvSD", Domains: []string{}, Limit: DefaultAuditLimit},
+		},
+		{
+			name:    "Malformed actor",
+			q:       models.AuditQuery{ActorID: "user1"},
+			wantErr: errs.ErrInvalidUserID,
+		},
+		{
+			name:    "Empty time range",
+			q:       models.AuditQuery{From: now, To: now},
+			wantErr: errs.ErrInvalidTimeRange,
+		},
+		{
+			name:    "Limit too high",
+			q:       models.AuditQuery{Limit: MaxAuditLimit + 1},
+			wantErr: errs.ErrInvalidLimit,
+		},
+		{
+			name:    "Unknown domain",
+			q:       models.AuditQuery{Domains: []string{"unknown.example"}},
+			wantErr: errs.ErrUnknownDomain,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAudit := mockstorage.NewMockAuditLog(ctrl)
+			if tt.wantErr == nil {
+				mockAudit.EXPECT().QueryAudit(gomock.Any(), tt.wantQuery).Return(nil, nil)
+			}
+
+			s := New(Params{Storage: mockstorage.NewMockRepo(ctrl), Audit: mockAudit, Logger: zap.NewNop()})
+
+			_, err := s.AuditLog(context.Background(), tt.q)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/proxy"
+)
+
+// ShortURL returns the full short URL of link for a request made to origin.
+func (s Service) ShortURL(origin proxy.Origin, link models.Link) string {
+	return s.linkBase(origin, link.Domain) + link.ShortURL
+}
+
+// DomainName returns the host name of the short domain link is on.
+func (s Service) DomainName(link models.Link) string {
+	return s.domains.Name(link.Domain)
+}
+
+// linkBase returns the base short URL of links on domain for a request made to origin.
+func (s Service) linkBase(origin proxy.Origin, domain string) string {
+	return s.urls.DomainBase(origin, s.domains.Name(domain))
+}
+
+// linkRef returns the domain and ID of a short URL given either as an ID requested on host
+// or as a full short URL, like create methods return it.
+func (s Service) linkRef(host, shortURL string) (domain, ID string) {
+	if u, err := url.Parse(shortURL); err == nil && u.Host != "" {
+		host, shortURL = u.Host, u.Path
+	}
+
+	if i := strings.LastIndex(shortURL, "/"); i >= 0 {
+		shortURL = shortURL[i+1:]
+	}
+
+	return s.domains.Key(host), shortURL
+}
+
+// CheckDomain returns errs.ErrUnknownDomain if name is neither empty nor one of the short domains.
+func (s Service) CheckDomain(name string) error {
+	if name != "" && !s.domains.Contains(name) {
+		return errs.ErrUnknownDomain
+	}
+
+	return nil
+}
+
+// domainFilter returns domains to list links on for a requested domain name, all of them if name is empty.
+func (s Service) domainFilter(name string) ([]string, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	err := s.CheckDomain(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{s.domains.Key(name)}, nil
+}
+
+// isShortDomain reports whether rawURL points to one of the short domains, a link to it would redirect in a loop.
+func (s Service) isShortDomain(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return s.domains.Contains(u.Host)
+}
+
+// UserURLs returns links of userID and of the workspaces they are a member of on domain, on every domain if it is empty.
+// Fails with errs.ErrUnknownDomain if domain is not one of the short domains.
+func (s Service) UserURLs(ctx context.Context, userID, domain string) ([]dto.URLPair, error) {
+	domains, err := s.domainFilter(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, err := s.storage.GetUserURLs(ctx, userID, domains)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range pairs {
+		pairs[i].Domain = s.domains.Name(pairs[i].Domain)
+	}
+
+	return pairs, nil
+}
+
+// WalkUserURLs is UserURLs that passes links to fn as they are read.
+func (s Service) WalkUserURLs(ctx context.Context, userID, domain string, fn func(dto.URLPair) error) error {
+	domains, err := s.domainFilter(domain)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.WalkUserURLs(ctx, userID, domains, func(pair dto.URLPair) error {
+		pair.Domain = s.domains.Name(pair.Domain)
+		return fn(pair)
+	})
+}
+
+// DeleteURLs marks links as deleted. shortURLs are IDs requested on host or full short URLs, so links on
+// several domains may be deleted at once. Fails with errs.ErrUserMismatch if userID may not delete some of them.
+func (s Service) DeleteURLs(ctx context.Context, userID, host string, shortURLs []string) error {
+	var domains []string
+	IDs := make(map[string][]string)
+
+	for _, shortURL := range shortURLs {
+		domain, ID := s.linkRef(host, shortURL)
+		if _, ok := IDs[domain]; !ok {
+			domains = append(domains, domain)
+		}
+
+		IDs[domain] = append(IDs[domain], ID)
+	}
+
+	for _, domain := range domains {
+		links := s.deletableLinks(ctx, userID, domain, IDs[domain])
+
+		err := s.storage.DeleteURLs(ctx, userID, domain, IDs[domain])
+		if err != nil {
+			return err
+		}
+
+		events := make([]models.AuditEvent, 0, len(links))
+		for _, link := range links {
+			events = append(events, models.AuditEvent{
+				Action:      models.AuditLinkDelete,
+				ActorID:     userID,
+				Domain:      domain,
+				ShortURL:    link.ShortURL,
+				WorkspaceID: link.WorkspaceID,
+				Before:      map[string]any{"original_url": link.OriginalURL, "user_id": link.UserID},
+			})
+
+			if s.subscribed(ctx, link.UserID, models.WebhookLinkDeleted) {
+				s.notifyAsync(ctx, link.UserID, dto.WebhookEvent{Event: models.WebhookLinkDeleted, Link: s.webhookLink(link)})
+			}
+		}
+
+		s.audit(ctx, events...)
+	}
+
+	return nil
+}
+
+// deletableLinks returns the links with IDs on domain that userID may delete, for audit, webhook and live events.
+// Values before deletion are only known while the links are there. Storage skips links userID may not delete,
+// so they are skipped here too.
+func (s Service) deletableLinks(ctx context.Context, userID, domain string, IDs []string) []models.Link {
+	if s.auditor == nil && s.webhooks == nil && s.clicks == nil {
+		return nil
+	}
+
+	links := make([]models.Link, 0, len(IDs))
+	for _, ID := range IDs {
+		link, err := s.storage.GetLongURL(ctx, domain, ID)
+		if err != nil {
+			continue
+		}
+
+		if link.UserID != userID && (link.WorkspaceID == "" || s.CheckWorkspaceEditor(ctx, link.WorkspaceID, userID) != nil) {
+			continue
+		}
+
+		links = append(links, link)
+	}
+
+	return links
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/clicks"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/proxy"
+)
+
+// CreateShortURL validates req and creates a link for userID requested from origin. The link is created on
+// req.Domain, or on the domain of origin if it is empty. Policy violations are wrapped in errs.ErrRejected.
+// Returns the existing short URL with errs.ErrDuplicate.
+func (s Service) CreateShortURL(ctx context.Context, userID string, origin proxy.Origin, req dto.Request) (string, error) {
+	url, canonicalURL, err := s.checkURL(req.FullURL, s.urls.Host(origin))
+	if err != nil {
+		if errors.Is(err, errs.ErrNotURL) {
+			return "", err
+		}
+
+		return "", fmt.Errorf("%w: %w", errs.ErrRejected, err)
+	}
+
+	opts, err := newURLOptions(req)
+	if err != nil {
+		return "", err
+	}
+
+	opts.Domain, err = s.domains.Select(req.Domain, origin.Host)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.CheckWorkspaceEditor(ctx, opts.WorkspaceID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	shortURL, err := s.storage.CreateShortURL(ctx, userID, s.linkBase(origin, opts.Domain), url, canonicalURL, opts)
+	if err != nil {
+		return shortURL, err
+	}
+
+	_, ID := s.linkRef("", shortURL)
+	s.audit(ctx, models.AuditEvent{
+		Action:      models.AuditLinkCreate,
+		ActorID:     userID,
+		Domain:      opts.Domain,
+		ShortURL:    ID,
+		WorkspaceID: opts.WorkspaceID,
+		After:       linkValues(url, opts),
+	})
+
+	if s.subscribed(ctx, userID, models.WebhookLinkCreated) {
+		s.notify(ctx, userID, dto.WebhookEvent{
+			Event: models.WebhookLinkCreated,
+			Link: dto.WebhookLink{
+				ID:          ID,
+				Domain:      s.domains.Name(opts.Domain),
+				ShortURL:    shortURL,
+				OriginalURL: url,
+				WorkspaceID: opts.WorkspaceID,
+			},
+		})
+	}
+
+	return shortURL, nil
+}
+
+// CreateBatch creates links for the valid items and returns a result for every item in the same order.
+// Invalid items don't prevent the rest from being created. opts.Domain is the requested domain name,
+// it and workspace access are checked as in CreateShortURL.
+func (s Service) CreateBatch(ctx context.Context, userID string, origin proxy.Origin, items []dto.BatchRequest, opts models.URLOptions) ([]dto.BatchResponse, error) {
+	var err error
+
+	opts.Domain, err = s.domains.Select(opts.Domain, origin.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.CheckWorkspaceEditor(ctx, opts.WorkspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	host := s.urls.Host(origin)
+
+	results := make([]dto.BatchResponse, len(items))
+
+	valid := make([]dto.BatchRequest, 0, len(items))
+	validIdx := make([]int, 0, len(items))
+
+	for i, item := range items {
+		url, canonicalURL, err := s.checkURL(item.OriginalURL, host)
+		if err != nil {
+			results[i] = dto.BatchResponse{CorrelationID: item.CorrelationID, Status: dto.BatchStatusInvalid, Error: err.Error()}
+			continue
+		}
+
+		valid = append(valid, dto.BatchRequest{CorrelationID: item.CorrelationID, OriginalURL: url, CanonicalURL: canonicalURL})
+		validIdx = append(validIdx, i)
+	}
+
+	if len(valid) == 0 {
+		return results, nil
+	}
+
+	created, err := s.storage.BatchCreateShortURL(ctx, userID, s.linkBase(origin, opts.Domain), valid, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	notify := s.subscribed(ctx, userID, models.WebhookLinkCreated)
+
+	events := make([]models.AuditEvent, 0, len(created))
+	for j, result := range created {
+		results[validIdx[j]] = result
+
+		if result.Status != dto.BatchStatusCreated {
+			continue
+		}
+
+		_, ID := s.linkRef("", result.ShortURL)
+		events = append(events, models.AuditEvent{
+			Action:      models.AuditLinkBatchCreate,
+			ActorID:     userID,
+			Domain:      opts.Domain,
+			ShortURL:    ID,
+			WorkspaceID: opts.WorkspaceID,
+			After:       linkValues(valid[j].OriginalURL, opts),
+		})
+
+		if notify {
+			s.notify(ctx, userID, dto.WebhookEvent{
+				Event: models.WebhookLinkCreated,
+				Link: dto.WebhookLink{
+					ID:          ID,
+					Domain:      s.domains.Name(opts.Domain),
+					ShortURL:    result.ShortURL,
+					OriginalURL: valid[j].OriginalURL,
+					WorkspaceID: opts.WorkspaceID,
+				},
+			})
+		}
+	}
+
+	s.audit(ctx, events...)
+
+	return results, nil
+}
+
+// linkValues describes a link created with opts in audit events. The password hash is left out.
+func linkValues(url string, opts models.URLOptions) map[string]any {
+	values := map[string]any{"original_url": url}
+
+	if opts.PasswordHash != "" {
+		values["protected"] = true
+	}
+
+	if opts.MaxClicks > 0 {
+		values["max_clicks"] = opts.MaxClicks
+	}
+
+	if opts.RedirectType != 0 {
+		values["redirect_type"] = opts.RedirectType
+	}
+
+	return values
+}
+
+// checkURL returns rawURL as stored and its canonical form. Fails with errs.ErrNotURL or the policy violation.
+// Links to any of the short domains are rejected like links to host.
+func (s Service) checkURL(rawURL, host string) (url, canonicalURL string, err error) {
+	url, canonicalURL, err = helpers.CheckURL([]byte(rawURL), s.canon)
+	if err != nil {
+		return "", "", errs.ErrNotURL
+	}
+
+	err = s.policy.Check(canonicalURL, host)
+	if err != nil {
+		return "", "", err
+	}
+
+	if s.isShortDomain(canonicalURL) {
+		return "", "", errs.ErrSelfReferential
+	}
+
+	return url, canonicalURL, nil
+}
+
+// Link returns a link as stored, including exhausted ones. shortURL is an ID requested on host or a full short URL.
+func (s Service) Link(ctx context.Context, host, shortURL string) (models.Link, error) {
+	domain, ID := s.linkRef(host, shortURL)
+	return s.storage.GetLongURL(ctx, domain, ID)
+}
+
+// Resolve returns a link that can be followed, looked up as in Link. Exhausted links fail with errs.ErrGone.
+// Disabled links fail with errs.ErrDisabled together with a link holding only the takedown to show the visitor.
+func (s Service) Resolve(ctx context.Context, host, shortURL string) (models.Link, error) {
+	link, err := s.Link(ctx, host, shortURL)
+	if err != nil {
+		return models.Link{}, err
+	}
+
+	if link.Disabled() {
+		return models.Link{Takedown: link.Takedown}, errs.ErrDisabled
+	}
+
+	if link.Exhausted() {
+		return models.Link{}, errs.ErrGone
+	}
+
+	return link, nil
+}
+
+// Unlock checks password of a protected link. Failed attempts are counted per link and client address,
+// once there are too many the link is locked for the client with errs.ErrTooManyAttempts.
+func (s Service) Unlock(link models.Link, password string, clientIP netip.Addr) error {
+	key := passwordAttemptKey(link.Domain, link.ShortURL, clientIP)
+	if !s.limiter.Allow(key) {
+		return errs.ErrTooManyAttempts
+	}
+
+	if !helpers.CheckPassword(link.PasswordHash, password) {
+		s.limiter.Fail(key)
+		return errs.ErrWrongPassword
+	}
+
+	s.limiter.Reset(key)
+
+	return nil
+}
+
+// Visit records a redirect through link: uses up a click of a limited link and publishes a click event.
+// Fails with errs.ErrGone if the last click was used up concurrently.
+func (s Service) Visit(ctx context.Context, link models.Link, clientIP netip.Addr, referer, userAgent string) error {
+	if link.Limited() {
+		_, err := s.storage.ConsumeClick(ctx, link.Domain, link.ShortURL)
+		if err != nil {
+			if errors.Is(err, errs.ErrURLNotFound) {
+				return errs.ErrGone
+			}
+
+			return err
+		}
+	}
+
+	if !s.subscribed(ctx, link.UserID, models.WebhookLinkClicked) {
+		return nil
+	}
+
+	click := &dto.WebhookClick{Referer: referer, UserAgent: userAgent}
+	if clientIP.IsValid() {
+		click.ClientIP = clientIP.String()
+	}
+
+	s.notifyAsync(ctx, link.UserID, dto.WebhookEvent{
+		Event: models.WebhookLinkClicked,
+		Time:  time.Now(),
+		Link:  s.webhookLink(link),
+		Click: click,
+	})
+
+	return nil
+}
+
+// SubscribeClicks returns events of links created by userID kept after lastID, then new ones
+// until unsubscribe is called. The channel is closed if the subscriber falls behind.
+func (s Service) SubscribeClicks(userID string, lastID uint64) (missed []clicks.Event, events <-chan clicks.Event,
+	unsubscribe func(), err error) {
+	if s.clicks == nil {
+		return nil, nil, nil, errs.ErrClicksUnavailable
+	}
+
+	missed, events, unsubscribe = s.clicks.Subscribe(userID, lastID)
+
+	return missed, events, unsubscribe, nil
+}
+
+// IsLinkOwner reports whether userID created the link or is a member of its workspace.
+func (s Service) IsLinkOwner(ctx context.Context, link models.Link, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+
+	if link.UserID == userID {
+		return true, nil
+	}
+
+	if link.WorkspaceID == "" {
+		return false, nil
+	}
+
+	_, err := s.storage.GetWorkspaceRole(ctx, link.WorkspaceID, userID)
+	if err != nil {
+		if errors.Is(err, errs.ErrForbidden) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// passwordAttemptKey identifies password attempts of one client for one link. The same ID on another domain
+// is another link.
+func passwordAttemptKey(domain, ID string, clientIP netip.Addr) string {
+	return domain + "|" + ID + "|" + clientIP.String()
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+)
+
+// newURLOptions validates options of a new short URL. Hashes the password, if it's set.
+func newURLOptions(req dto.Request) (models.URLOptions, error) {
+	if req.MaxClicks < 0 {
+		return models.URLOptions{}, errs.ErrInvalidMaxClicks
+	}
+
+	if req.RedirectType != 0 && !models.ValidRedirectType(req.RedirectType) {
+		return models.URLOptions{}, errs.ErrInvalidRedirectType
+	}
+
+	opts := models.URLOptions{
+		WorkspaceID:  req.WorkspaceID,
+		MaxClicks:    req.MaxClicks,
+		RedirectType: req.RedirectType,
+	}
+
+	if len(req.Password) > helpers.MaxPasswordLength {
+		return models.URLOptions{}, errs.ErrPasswordTooLong
+	}
+
+	if req.Password != "" {
+		hash, err := helpers.HashPassword(req.Password)
+		if err != nil {
+			return models.URLOptions{}, err
+		}
+
+		opts.PasswordHash = hash
+	}
+
+	return opts, nil
+}
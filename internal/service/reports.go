@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxReportLength caps the reason and the contact of an abuse report, in characters.
+const maxReportLength = 2000
+
+// reportedTakedown disables links reported by enough visitors until a moderator reviews the reports.
+var reportedTakedown = models.Takedown{
+	Status: http.StatusGone,
+	Reason: "This link was reported as abusive and is disabled until a moderator reviews it.",
+}
+
+// ReportLink files an abuse report about a link, looked up as in Link, from the visitor at clientIP.
+// Once distinct reporters within the report window reach the threshold, the link is disabled until
+// a moderator approves or dismisses the reports.
+func (s Service) ReportLink(ctx context.Context, host, shortURL string, clientIP netip.Addr, reason, contact string) error {
+	reason, contact = strings.TrimSpace(reason), strings.TrimSpace(contact)
+	if reason == "" {
+		return errs.ErrEmptyReportReason
+	}
+
+	if utf8.RuneCountInString(reason) > maxReportLength || utf8.RuneCountInString(contact) > maxReportLength {
+		return errs.ErrReportTooLong
+	}
+
+	link, err := s.Link(ctx, host, shortURL)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	reportID := uuid.NewString()
+	n, err := s.storage.CreateReport(ctx, models.Report{
+		ID:        reportID,
+		Domain:    link.Domain,
+		ShortURL:  link.ShortURL,
+		Reason:    reason,
+		Contact:   contact,
+		Reporter:  clientIP.String(),
+		Status:    models.ReportPending,
+		CreatedAt: now,
+	}, now.Add(-s.reports.window))
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:   models.AuditReportCreate,
+		Domain:   link.Domain,
+		ShortURL: link.ShortURL,
+		After:    map[string]any{"report_id": reportID, "reason": reason},
+	})
+
+	if s.reports.threshold == 0 || n < s.reports.threshold || link.Disabled() {
+		return nil
+	}
+
+	_, err = s.storage.DisableLinks(ctx, models.LinkQuery{ShortURL: link.ShortURL, Domains: []string{link.Domain}}, reportedTakedown)
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:   models.AuditLinkAutoDisable,
+		Domain:   link.Domain,
+		ShortURL: link.ShortURL,
+		After:    takedownValues(reportedTakedown),
+	})
+
+	s.logger.Info("Link disabled by abuse reports", zap.String("domain", link.Domain),
+		zap.String("short_url", link.ShortURL), zap.Int("reporters", n))
+
+	return nil
+}
+
+// Reports returns the review queue for moderators: reports with status, pending ones if it is empty, oldest first.
+func (s Service) Reports(ctx context.Context, status models.ReportStatus) ([]models.Report, error) {
+	if status == "" {
+		status = models.ReportPending
+	}
+
+	if !status.Valid() {
+		return nil, errs.ErrUnknownReportStatus
+	}
+
+	return s.storage.GetReports(ctx, status)
+}
+
+// ApproveReport upholds a pending report: the link is disabled with t, like DisableLink, and every pending report
+// about it is approved. The reason of the report is shown to visitors if t has none.
+func (s Service) ApproveReport(ctx context.Context, reportID string, t models.Takedown) error {
+	report, err := s.pendingReport(ctx, reportID)
+	if err != nil {
+		return err
+	}
+
+	t, err = checkTakedown(t)
+	if err != nil {
+		return err
+	}
+
+	if t.Reason == "" {
+		t.Reason = report.Reason
+	}
+
+	err = s.setTakedown(ctx, report.Domain, report.ShortURL, t)
+	if err != nil {
+		return err
+	}
+
+	return s.resolveReports(ctx, report, models.ReportApproved)
+}
+
+// DismissReport rejects a pending report together with every other pending report about the same link.
+// A link disabled by reports is enabled again, takedowns made by moderators are kept.
+func (s Service) DismissReport(ctx context.Context, reportID string) error {
+	report, err := s.pendingReport(ctx, reportID)
+	if err != nil {
+		return err
+	}
+
+	err = s.resolveReports(ctx, report, models.ReportDismissed)
+	if err != nil {
+		return err
+	}
+
+	link, err := s.storage.GetLongURL(ctx, report.Domain, report.ShortURL)
+	if errors.Is(err, errs.ErrURLNotFound) || errors.Is(err, errs.ErrGone) {
+		// Deleted since it was reported, there is nothing to enable.
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if link.Takedown != reportedTakedown {
+		return nil
+	}
+
+	return s.setTakedown(ctx, report.Domain, report.ShortURL, models.Takedown{})
+}
+
+// resolveReports sets status of every pending report about the link of report and records it.
+func (s Service) resolveReports(ctx context.Context, report models.Report, status models.ReportStatus) error {
+	n, err := s.storage.ResolveReports(ctx, report.Domain, report.ShortURL, status)
+	if err != nil {
+		return err
+	}
+
+	action := models.AuditReportApprove
+	if status == models.ReportDismissed {
+		action = models.AuditReportDismiss
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:   action,
+		Domain:   report.Domain,
+		ShortURL: report.ShortURL,
+		Before:   map[string]any{"status": string(models.ReportPending)},
+		After:    map[string]any{"report_id": report.ID, "status": string(status), "reports": n},
+	})
+
+	return nil
+}
+
+// pendingReport returns the report with ID. Fails with errs.ErrReportResolved if it is not pending anymore.
+func (s Service) pendingReport(ctx context.Context, ID string) (models.Report, error) {
+	if uuid.Validate(ID) != nil {
+		return models.Report{}, errs.ErrReportNotFound
+	}
+
+	report, err := s.storage.GetReport(ctx, ID)
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	if report.Status != models.ReportPending {
+		return models.Report{}, errs.ErrReportResolved
+	}
+
+	return report, nil
+}
@@ -0,0 +1,110 @@
+// Package service holds the business rules of the shortener: URL validation, policy, workspace access
+// and storage calls. The HTTP and gRPC controllers only decode requests, call it and map errs sentinels
+// to their status codes.
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/clicks"
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/domain"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/limiter"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/publicurl"
+	"github.com/MukizuL/shortener/internal/storage"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+type Service struct {
+	storage  storage.Repo
+	auditor  storage.AuditLog
+	webhooks storage.Webhooks
+	domains  *domain.Registry
+	urls     *publicurl.Builder
+	clicks   *clicks.Hub
+	limiter  *limiter.Limiter
+	policy   *policy.Engine
+	canon    helpers.Canonicalization
+	reports  reportPolicy
+	// allowPrivateHooks lets webhooks point inside the network.
+	allowPrivateHooks bool
+	subscriptions     *subscriptions
+	// pending holds a slot for every webhook event queued in the background.
+	pending chan struct{}
+	logger  *zap.Logger
+}
+
+// reportPolicy tells when abuse reports disable a link: threshold distinct reporters within window.
+// The config always sets a threshold, the zero one of a Service made without config never disables links.
+type reportPolicy struct {
+	threshold int
+	window    time.Duration
+}
+
+// Params are dependencies of Service. Audit, Webhooks, Clicks, Policy, Domains and URLs may be nil,
+// a nil Limiter is replaced with a default one.
+type Params struct {
+	fx.In
+
+	Config   *config.Config
+	Storage  storage.Repo
+	Audit    storage.AuditLog
+	Webhooks storage.Webhooks
+	Domains  *domain.Registry
+	URLs     *publicurl.Builder
+	Clicks   *clicks.Hub
+	Limiter  *limiter.Limiter
+	Policy   *policy.Engine
+	Logger   *zap.Logger
+}
+
+func New(p Params) *Service {
+	s := &Service{
+		storage:  p.Storage,
+		auditor:  p.Audit,
+		webhooks: p.Webhooks,
+		domains:  p.Domains,
+		urls:     p.URLs,
+		clicks:   p.Clicks,
+		limiter:  p.Limiter,
+		policy:   p.Policy,
+		logger:   p.Logger,
+
+		subscriptions: newSubscriptions(),
+		pending:       make(chan struct{}, MaxPendingNotifications),
+	}
+
+	if s.limiter == nil {
+		s.limiter = limiter.New(limiter.MaxFailures, limiter.Window)
+	}
+
+	if p.Config != nil {
+		s.canon = helpers.Canonicalization{
+			SortQuery:     p.Config.SortQuery,
+			StripTracking: p.Config.StripTracking,
+		}
+
+		s.reports = reportPolicy{threshold: p.Config.ReportThreshold, window: p.Config.ReportWindow}
+		s.allowPrivateHooks = p.Config.WebhookAllowPrivate
+	}
+
+	return s
+}
+
+func Provide() fx.Option {
+	return fx.Provide(New)
+}
+
+// Ping checks that storage is reachable.
+func (s Service) Ping(ctx context.Context) error {
+	return s.storage.Ping(ctx)
+}
+
+// Stats returns the number of links and users.
+func (s Service) Stats(ctx context.Context) (urls, users int, err error) {
+	return s.storage.GetStats(ctx)
+}
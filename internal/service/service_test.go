@@ -0,0 +1,375 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/domain"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/limiter"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/proxy"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+const workspaceID = "0b7c2f5e-4a1d-4c36-9f34-1f8a9d2d6e11"
+
+func TestService_CreateShortURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      dto.Request
+		mockRole *models.Role
+		mockErr  error
+		wantErr  error
+	}{
+		{
+			name:    "Not a URL",
+			req:     dto.Request{FullURL: "youtube"},
+			wantErr: errs.ErrNotURL,
+		},
+		{
+			name:    "Rejected by policy",
+			req:     dto.Request{FullURL: "http://localhost:8080/qxDvSD"},
+			wantErr: errs.ErrRejected,
+		},
+		{
+			name:    "Negative max clicks",
+			req:     dto.Request{FullURL: "https://www.youtube.com", MaxClicks: -1},
+			wantErr: errs.ErrInvalidMaxClicks,
+		},
+		{
+			name:    "Password too long",
+			req:     dto.Request{FullURL: "https://www.youtube.com", Password: strings.Repeat("a", helpers.MaxPasswordLength+1)},
+			wantErr: errs.ErrPasswordTooLong,
+		},
+		{
+			name:     "Viewer of workspace",
+			req:      dto.Request{FullURL: "https://www.youtube.com", WorkspaceID: workspaceID},
+			mockRole: ptr(models.RoleViewer),
+			wantErr:  errs.ErrForbidden,
+		},
+		{
+			name:     "Editor of workspace",
+			req:      dto.Request{FullURL: "https://www.youtube.com", WorkspaceID: workspaceID},
+			mockRole: ptr(models.RoleEditor),
+		},
+		{
+			name:    "Duplicate",
+			req:     dto.Request{FullURL: "https://www.youtube.com"},
+			mockErr: errs.ErrDuplicate,
+			wantErr: errs.ErrDuplicate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+
+			if tt.mockRole != nil {
+				mockRepo.EXPECT().GetWorkspaceRole(gomock.Any(), workspaceID, "user1").Return(*tt.mockRole, nil)
+			}
+
+			if tt.wantErr == nil || tt.mockErr != nil {
+				mockRepo.EXPECT().CreateShortURL(gomock.Any(), "user1", "http://short.example/", "https://www.youtube.com", gomock.Any(), gomock.Any()).
+					Return("http://short.example/qxDvSD", tt.mockErr)
+			}
+
+			s := New(Params{
+				Storage: mockRepo,
+				Policy:  policy.New(zap.NewNop(), policy.PrivateAddress{}),
+				Logger:  zap.NewNop(),
+			})
+
+			shortURL, err := s.CreateShortURL(context.Background(), "user1", proxy.Origin{Host: "short.example"}, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "http://short.example/qxDvSD", shortURL)
+		})
+	}
+}
+
+func TestService_CreateBatchInvalid(t *testing.T) {
+	s := New(Params{
+		Policy: policy.New(zap.NewNop(), policy.PrivateAddress{}),
+		Logger: zap.NewNop(),
+	})
+
+	results, err := s.CreateBatch(context.Background(), "user1", proxy.Origin{Host: "short.example"}, []dto.BatchRequest{
+		{CorrelationID: "1", OriginalURL: "youtube"},
+		{CorrelationID: "2", OriginalURL: "http://127.0.0.1/"},
+	}, models.URLOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []dto.BatchResponse{
+		{CorrelationID: "1", Status: dto.BatchStatusInvalid, Error: errs.ErrNotURL.Error()},
+		{CorrelationID: "2", Status: dto.BatchStatusInvalid, Error: errs.ErrPrivateAddress.Error()},
+	}, results)
+}
+
+func TestService_Unlock(t *testing.T) {
+	hash, err := helpers.HashPassword("secret")
+	require.NoError(t, err)
+
+	link := models.Link{ShortURL: "qxDvSD", PasswordHash: hash}
+
+	s := New(Params{Limiter: limiter.New(2, time.Minute), Logger: zap.NewNop()})
+
+	client := netip.MustParseAddr("10.0.0.1")
+
+	assert.ErrorIs(t, s.Unlock(link, "wrong", client), errs.ErrWrongPassword)
+	assert.NoError(t, s.Unlock(link, "secret", client))
+
+	assert.ErrorIs(t, s.Unlock(link, "wrong", client), errs.ErrWrongPassword)
+	assert.ErrorIs(t, s.Unlock(link, "wrong", client), errs.ErrWrongPassword)
+	assert.ErrorIs(t, s.Unlock(link, "secret", client), errs.ErrTooManyAttempts)
+
+	assert.NoError(t, s.Unlock(link, "secret", netip.MustParseAddr("2001:db8::1")))
+
+	branded := link
+	branded.Domain = "brand.ly"
+	assert.NoError(t, s.Unlock(branded, "secret", client), "the same ID on another domain isn't locked")
+}
+
+func TestService_VisitExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockstorage.NewMockRepo(ctrl)
+	mockRepo.EXPECT().ConsumeClick(gomock.Any(), "", "qxDvSD").Return(0, errs.ErrURLNotFound)
+
+	s := New(Params{Storage: mockRepo, Logger: zap.NewNop()})
+
+	err := s.Visit(context.Background(), models.Link{ShortURL: "qxDvSD", MaxClicks: 1}, netip.Addr{}, "", "")
+	assert.ErrorIs(t, err, errs.ErrGone)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestService_Domains(t *testing.T) {
+	domains, err := domain.New("sho.rt", "brand.ly")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		origin     proxy.Origin
+		req        dto.Request
+		wantBase   string
+		wantDomain string
+		wantErr    error
+	}{
+		{
+			name:     "Default domain",
+			origin:   proxy.Origin{HTTPS: true, Host: "sho.rt"},
+			req:      dto.Request{FullURL: "https://www.youtube.com"},
+			wantBase: "https://sho.rt/",
+		},
+		{
+			name:       "Domain of the request",
+			origin:     proxy.Origin{HTTPS: true, Host: "brand.ly"},
+			req:        dto.Request{FullURL: "https://www.youtube.com"},
+			wantBase:   "https://brand.ly/",
+			wantDomain: "brand.ly",
+		},
+		{
+			name:       "Chosen domain",
+			origin:     proxy.Origin{Host: "localhost:8080"},
+			req:        dto.Request{FullURL: "https://www.youtube.com", Domain: "Brand.ly"},
+			wantBase:   "http://brand.ly/",
+			wantDomain: "brand.ly",
+		},
+		{
+			name:    "Unknown domain",
+			origin:  proxy.Origin{Host: "sho.rt"},
+			req:     dto.Request{FullURL: "https://www.youtube.com", Domain: "evil.example"},
+			wantErr: errs.ErrUnknownDomain,
+		},
+		{
+			name:    "Link to another short domain",
+			origin:  proxy.Origin{Host: "sho.rt"},
+			req:     dto.Request{FullURL: "https://brand.ly/qxDvSD"},
+			wantErr: errs.ErrSelfReferential,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+
+			if tt.wantErr == nil {
+				mockRepo.EXPECT().CreateShortURL(gomock.Any(), "user1", tt.wantBase, tt.req.FullURL, gomock.Any(), models.URLOptions{Domain: tt.wantDomain}).
+					Return(tt.wantBase+"qxDvSD", nil)
+			}
+
+			s := New(Params{Storage: mockRepo, Domains: domains, Logger: zap.NewNop()})
+
+			shortURL, err := s.CreateShortURL(context.Background(), "user1", tt.origin, tt.req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBase+"qxDvSD", shortURL)
+		})
+	}
+}
+
+func TestService_ResolveOnDomain(t *testing.T) {
+	domains, err := domain.New("sho.rt", "brand.ly")
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockstorage.NewMockRepo(ctrl)
+	mockRepo.EXPECT().GetLongURL(gomock.Any(), "brand.ly", "qxDvSD").Return(models.Link{ShortURL: "qxDvSD", Domain: "brand.ly"}, nil).Times(2)
+	mockRepo.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{ShortURL: "qxDvSD"}, nil).Times(2)
+
+	s := New(Params{Storage: mockRepo, Domains: domains, Logger: zap.NewNop()})
+
+	link, err := s.Resolve(context.Background(), "brand.ly:443", "qxDvSD")
+	require.NoError(t, err)
+	assert.Equal(t, "https://brand.ly/qxDvSD", s.ShortURL(proxy.Origin{HTTPS: true, Host: "sho.rt"}, link))
+
+	_, err = s.Resolve(context.Background(), "sho.rt", "https://brand.ly/qxDvSD")
+	require.NoError(t, err)
+
+	link, err = s.Resolve(context.Background(), "sho.rt", "qxDvSD")
+	require.NoError(t, err)
+	assert.Equal(t, "https://sho.rt/qxDvSD", s.ShortURL(proxy.Origin{HTTPS: true, Host: "brand.ly"}, link))
+
+	// Hosts that are not short domains are served from the default one.
+	_, err = s.Resolve(context.Background(), "localhost:8080", "qxDvSD")
+	require.NoError(t, err)
+}
+
+func TestService_ReportLink(t *testing.T) {
+	tests := []struct {
+		name        string
+		reason      string
+		link        models.Link
+		reporters   int
+		wantDisable bool
+		wantErr     error
+	}{
+		{
+			name:    "No reason",
+			reason:  "  ",
+			wantErr: errs.ErrEmptyReportReason,
+		},
+		{
+			name:      "Below threshold",
+			reason:    "Phishing",
+			link:      models.Link{ShortURL: "qxDvSD"},
+			reporters: 2,
+		},
+		{
+			name:        "Threshold reached",
+			reason:      "Phishing",
+			link:        models.Link{ShortURL: "qxDvSD"},
+			reporters:   3,
+			wantDisable: true,
+		},
+		{
+			name:      "Already disabled",
+			reason:    "Phishing",
+			link:      models.Link{ShortURL: "qxDvSD", Takedown: models.Takedown{Status: 451, Reason: "Court order"}},
+			reporters: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+
+			if tt.wantErr == nil {
+				mockRepo.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(tt.link, nil)
+				mockRepo.EXPECT().CreateReport(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, r models.Report, since time.Time) (int, error) {
+						assert.Equal(t, "10.0.0.1", r.Reporter)
+						assert.Equal(t, models.ReportPending, r.Status)
+						assert.WithinDuration(t, r.CreatedAt.Add(-time.Hour), since, time.Second)
+
+						return tt.reporters, nil
+					})
+			}
+
+			if tt.wantDisable {
+				mockRepo.EXPECT().DisableLinks(gomock.Any(), models.LinkQuery{ShortURL: "qxDvSD", Domains: []string{""}}, reportedTakedown).Return(1, nil)
+			}
+
+			s := New(Params{Config: &config.Config{ReportThreshold: 3, ReportWindow: time.Hour}, Storage: mockRepo, Logger: zap.NewNop()})
+
+			err := s.ReportLink(context.Background(), "localhost:8080", "qxDvSD", netip.MustParseAddr("10.0.0.1"), tt.reason, "")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestService_DismissReport(t *testing.T) {
+	const reportID = "5d0c8f3a-2b7e-4f61-9a8d-3c4b5e6f7a8b"
+
+	tests := []struct {
+		name       string
+		takedown   models.Takedown
+		wantEnable bool
+	}{
+		{
+			name:       "Disabled by reports",
+			takedown:   reportedTakedown,
+			wantEnable: true,
+		},
+		{
+			name:     "Disabled by moderator",
+			takedown: models.Takedown{Status: 451, Reason: "Court order"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().GetReport(gomock.Any(), reportID).
+				Return(models.Report{ID: reportID, ShortURL: "qxDvSD", Status: models.ReportPending}, nil)
+			mockRepo.EXPECT().ResolveReports(gomock.Any(), "", "qxDvSD", models.ReportDismissed).Return(2, nil)
+			mockRepo.EXPECT().GetLongURL(gomock.Any(), "", "qxDvSD").Return(models.Link{ShortURL: "qxDvSD", Takedown: tt.takedown}, nil).MinTimes(1)
+
+			if tt.wantEnable {
+				mockRepo.EXPECT().DisableLinks(gomock.Any(), models.LinkQuery{ShortURL: "qxDvSD", Domains: []string{""}}, models.Takedown{}).Return(1, nil)
+			}
+
+			s := New(Params{Storage: mockRepo, Logger: zap.NewNop()})
+
+			err := s.DismissReport(context.Background(), reportID)
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// SubscriptionTTL is how long the webhook events a user is subscribed to are cached for hot paths.
+	// Changes made by other instances are seen once it runs out.
+	SubscriptionTTL = 30 * time.Second
+	// maxSubscriptions caps the number of users whose subscriptions are cached.
+	maxSubscriptions = 10000
+	// MaxPendingNotifications caps webhook events queued in the background. Past it events are queued
+	// in the request, which slows callers down instead of piling goroutines up.
+	MaxPendingNotifications = 256
+)
+
+type subscription struct {
+	events  []string
+	expires time.Time
+}
+
+// subscriptions caches the webhook events users are subscribed to, so that redirects of users without
+// webhooks don't query storage.
+type subscriptions struct {
+	m     sync.Mutex
+	users map[string]subscription
+}
+
+func newSubscriptions() *subscriptions {
+	return &subscriptions{users: make(map[string]subscription)}
+}
+
+// get returns the events userID is subscribed to, ok is false if they aren't cached.
+func (c *subscriptions) get(userID string) (events []string, ok bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	sub, ok := c.users[userID]
+	if !ok || time.Now().After(sub.expires) {
+		return nil, false
+	}
+
+	return sub.events, true
+}
+
+func (c *subscriptions) set(userID string, events []string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	now := time.Now()
+
+	if len(c.users) >= maxSubscriptions {
+		for ID, sub := range c.users {
+			if now.After(sub.expires) {
+				delete(c.users, ID)
+			}
+		}
+	}
+
+	if len(c.users) >= maxSubscriptions {
+		clear(c.users)
+	}
+
+	c.users[userID] = subscription{events: events, expires: now.Add(SubscriptionTTL)}
+}
+
+// forget drops the cached subscriptions of userID after they changed.
+func (c *subscriptions) forget(userID string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	delete(c.users, userID)
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/clicks"
+	contextI "github.com/MukizuL/shortener/internal/context"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/webhook"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxWebhooks caps the number of webhooks of a user.
+	MaxWebhooks = 10
+	// DeliveriesLimit is the number of the newest deliveries listed for a webhook.
+	DeliveriesLimit = 100
+)
+
+// CreateWebhook subscribes userID to events about links they created, to every event if events is empty.
+// Deliveries are posted to rawURL signed with the secret of the returned webhook, it isn't shown again.
+func (s Service) CreateWebhook(ctx context.Context, userID, rawURL string, events []string) (models.Webhook, error) {
+	if s.webhooks == nil {
+		return models.Webhook{}, errs.ErrWebhooksUnavailable
+	}
+
+	err := s.checkWebhookURL(rawURL)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	if len(events) == 0 {
+		events = models.WebhookEvents
+	}
+
+	var subscribed []string
+	for _, event := range events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return models.Webhook{}, errs.ErrUnknownWebhookEvent
+		}
+
+		if !slices.Contains(subscribed, event) {
+			subscribed = append(subscribed, event)
+		}
+	}
+
+	existing, err := s.webhooks.GetWebhooks(ctx, userID)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	if len(existing) >= MaxWebhooks {
+		return models.Webhook{}, errs.ErrTooManyWebhooks
+	}
+
+	w := models.Webhook{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		URL:       rawURL,
+		Secret:    webhook.NewSecret(),
+		Events:    subscribed,
+		CreatedAt: time.Now(),
+	}
+
+	err = s.webhooks.CreateWebhook(ctx, w)
+	if err != nil {
+		return models.Webhook{}, err
+	}
+
+	s.subscriptions.forget(userID)
+
+	s.audit(ctx, models.AuditEvent{
+		Action:  models.AuditWebhookCreate,
+		ActorID: userID,
+		After:   map[string]any{"webhook_id": w.ID, "url": w.URL, "events": w.Events},
+	})
+
+	return w, nil
+}
+
+// checkWebhookURL returns errs.ErrMalformedWebhookURL unless rawURL is an absolute http(s) URL, and
+// errs.ErrPrivateAddress if it points inside the network while that isn't allowed.
+func (s Service) checkWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errs.ErrMalformedWebhookURL
+	}
+
+	if s.allowPrivateHooks {
+		return nil
+	}
+
+	return policy.PrivateAddress{}.Check(u, "")
+}
+
+// Webhooks returns webhooks of userID, oldest first, without their secrets.
+func (s Service) Webhooks(ctx context.Context, userID string) ([]models.Webhook, error) {
+	if s.webhooks == nil {
+		return nil, errs.ErrWebhooksUnavailable
+	}
+
+	webhooks, err := s.webhooks.GetWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+
+	return webhooks, nil
+}
+
+// DeleteWebhook unsubscribes the webhook with ID of userID and drops its queued deliveries.
+func (s Service) DeleteWebhook(ctx context.Context, userID, ID string) error {
+	if s.webhooks == nil {
+		return errs.ErrWebhooksUnavailable
+	}
+
+	if uuid.Validate(ID) != nil {
+		return errs.ErrWebhookNotFound
+	}
+
+	err := s.webhooks.DeleteWebhook(ctx, userID, ID)
+	if err != nil {
+		return err
+	}
+
+	s.subscriptions.forget(userID)
+
+	s.audit(ctx, models.AuditEvent{
+		Action:  models.AuditWebhookDelete,
+		ActorID: userID,
+		Before:  map[string]any{"webhook_id": ID},
+	})
+
+	return nil
+}
+
+// WebhookDeliveries returns the newest deliveries of the webhook with ID of userID with status, any status if it is empty.
+func (s Service) WebhookDeliveries(ctx context.Context, userID, ID string, status models.DeliveryStatus) ([]models.Delivery, error) {
+	if s.webhooks == nil {
+		return nil, errs.ErrWebhooksUnavailable
+	}
+
+	if status != "" && !status.Valid() {
+		return nil, errs.ErrUnknownDeliveryStatus
+	}
+
+	if uuid.Validate(ID) != nil {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	return s.webhooks.GetDeliveries(ctx, userID, ID, status, DeliveriesLimit)
+}
+
+// Redeliver queues the delivery with deliveryID of the webhook with ID of userID again with fresh attempts,
+// like after it was dead-lettered.
+func (s Service) Redeliver(ctx context.Context, userID, ID, deliveryID string) error {
+	if s.webhooks == nil {
+		return errs.ErrWebhooksUnavailable
+	}
+
+	if uuid.Validate(ID) != nil || uuid.Validate(deliveryID) != nil {
+		return errs.ErrDeliveryNotFound
+	}
+
+	err := s.webhooks.RedeliverDelivery(ctx, userID, ID, deliveryID, time.Now())
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:  models.AuditWebhookRedeliver,
+		ActorID: userID,
+		After:   map[string]any{"webhook_id": ID, "delivery_id": deliveryID},
+	})
+
+	return nil
+}
+
+// notify publishes e to the live subscribers of userID and queues it for their webhooks subscribed to it.
+// The change is already made when it is queued, so failures are logged rather than returned.
+func (s Service) notify(ctx context.Context, userID string, e dto.WebhookEvent) {
+	payload, ok := s.publish(userID, &e)
+	if ok && s.hooked(ctx, userID, e.Event) {
+		s.enqueue(ctx, userID, e, payload)
+	}
+}
+
+// notifyAsync is notify for hot paths like redirects and deletes of many links, e is queued in the background.
+func (s Service) notifyAsync(ctx context.Context, userID string, e dto.WebhookEvent) {
+	payload, ok := s.publish(userID, &e)
+	if ok && s.hooked(ctx, userID, e.Event) {
+		s.enqueueAsync(ctx, userID, e, payload)
+	}
+}
+
+// publish sends e to the live subscribers of userID, setting its time if it's zero. It returns e encoded
+// for webhooks, ok is false if there are neither subscribers nor webhooks to receive it or it can't be encoded.
+func (s Service) publish(userID string, e *dto.WebhookEvent) (payload []byte, ok bool) {
+	if (s.webhooks == nil && s.clicks == nil) || userID == "" {
+		return nil, false
+	}
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		s.logger.Error("Error encoding webhook event", zap.String("event", e.Event), zap.Error(err))
+		return nil, false
+	}
+
+	ev := clicks.Event{Type: e.Event, ShortURL: e.Link.ID, UserID: userID, Time: e.Time, Data: payload}
+	if e.Click != nil {
+		// Formatted from a valid address or empty, so an unknown address stays invalid.
+		ev.ClientIP, _ = netip.ParseAddr(e.Click.ClientIP)
+		ev.Referer = e.Click.Referer
+		ev.UserAgent = e.Click.UserAgent
+	}
+
+	s.clicks.Publish(ev)
+
+	return payload, true
+}
+
+// enqueue queues the encoded e for the webhooks of userID subscribed to it.
+func (s Service) enqueue(ctx context.Context, userID string, e dto.WebhookEvent, payload []byte) {
+	if s.webhooks == nil {
+		return
+	}
+
+	// Queued even if the client went away in the meantime.
+	_, err := s.webhooks.EnqueueDeliveries(context.WithoutCancel(ctx), userID, e.Event, payload, e.Time)
+	if err != nil {
+		s.logger.Error("Error queueing webhook deliveries", zap.String("event", e.Event),
+			zap.String("request_id", contextI.RequestFrom(ctx).ID), zap.Error(err))
+	}
+}
+
+// enqueueAsync is enqueue for hot paths like redirects, e is queued in the background. Once MaxPendingNotifications
+// are queued it waits like enqueue.
+func (s Service) enqueueAsync(ctx context.Context, userID string, e dto.WebhookEvent, payload []byte) {
+	select {
+	case s.pending <- struct{}{}:
+		go func() {
+			defer func() { <-s.pending }()
+			s.enqueue(context.WithoutCancel(ctx), userID, e, payload)
+		}()
+	default:
+		s.enqueue(ctx, userID, e, payload)
+	}
+}
+
+// subscribed reports whether userID watches their link events or has a webhook subscribed to event,
+// to skip building events no one receives.
+func (s Service) subscribed(ctx context.Context, userID, event string) bool {
+	return s.clicks.Watched(userID) || s.hooked(ctx, userID, event)
+}
+
+// hooked reports whether userID has a webhook subscribed to event. Webhooks are looked up in a cache for SubscriptionTTL.
+func (s Service) hooked(ctx context.Context, userID, event string) bool {
+	if s.webhooks == nil || userID == "" {
+		return false
+	}
+
+	events, ok := s.subscriptions.get(userID)
+	if !ok {
+		webhooks, err := s.webhooks.GetWebhooks(ctx, userID)
+		if err != nil {
+			// Let enqueue try and log the failure.
+			return true
+		}
+
+		events = []string{}
+		for _, w := range webhooks {
+			for _, e := range w.Events {
+				if !slices.Contains(events, e) {
+					events = append(events, e)
+				}
+			}
+		}
+
+		s.subscriptions.set(userID, events)
+	}
+
+	return slices.Contains(events, event)
+}
+
+// webhookLink describes link in webhook events.
+func (s Service) webhookLink(link models.Link) dto.WebhookLink {
+	return dto.WebhookLink{
+		ID:          link.ShortURL,
+		Domain:      s.domains.Name(link.Domain),
+		OriginalURL: link.OriginalURL,
+		WorkspaceID: link.WorkspaceID,
+	}
+}
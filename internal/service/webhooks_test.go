@@ -0,0 +1,328 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/clicks"
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/policy"
+	"github.com/MukizuL/shortener/internal/proxy"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+func TestService_CreateWebhook(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		events       []string
+		allowPrivate bool
+		existing     int
+		wantEvents   []string
+		wantErr      error
+	}{
+		{
+			name:       "Every event",
+			url:        "https://crm.example/hooks",
+			wantEvents: models.WebhookEvents,
+		},
+		{
+			name:       "Some events",
+			url:        "https://crm.example/hooks",
+			events:     []string{models.WebhookLinkDeleted, models.WebhookLinkCreated, models.WebhookLinkDeleted},
+			wantEvents: []string{models.WebhookLinkDeleted, models.WebhookLinkCreated},
+		},
+		{
+			name:    "Unknown event",
+			url:     "https://crm.example/hooks",
+			events:  []string{"link.updated"},
+			wantErr: errs.ErrUnknownWebhookEvent,
+		},
+		{
+			name:    "Not http",
+			url:     "ftp://crm.example/hooks",
+			wantErr: errs.ErrMalformedWebhookURL,
+		},
+		{
+			name:    "Relative",
+			url:     "/hooks",
+			wantErr: errs.ErrMalformedWebhookURL,
+		},
+		{
+			name:    "Private address",
+			url:     "http://10.0.0.5/hooks",
+			wantErr: errs.ErrPrivateAddress,
+		},
+		{
+			name:         "Private address allowed",
+			url:          "http://10.0.0.5/hooks",
+			allowPrivate: true,
+			wantEvents:   models.WebhookEvents,
+		},
+		{
+			name:     "Too many",
+			url:      "https://crm.example/hooks",
+			existing: MaxWebhooks,
+			wantErr:  errs.ErrTooManyWebhooks,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockHooks := mockstorage.NewMockWebhooks(ctrl)
+			if tt.wantErr == nil || tt.existing > 0 {
+				mockHooks.EXPECT().GetWebhooks(gomock.Any(), "user1").Return(make([]models.Webhook, tt.existing), nil)
+			}
+
+			if tt.wantErr == nil {
+				mockHooks.EXPECT().CreateWebhook(gomock.Any(), gomock.Any()).Return(nil)
+			}
+
+			s := New(Params{
+				Config:   &config.Config{WebhookAllowPrivate: tt.allowPrivate},
+				Storage:  mockstorage.NewMockRepo(ctrl),
+				Webhooks: mockHooks,
+				Logger:   zap.NewNop(),
+			})
+
+			hook, err := s.CreateWebhook(context.Background(), "user1", tt.url, tt.events)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.url, hook.URL)
+			assert.Equal(t, tt.wantEvents, hook.Events)
+			assert.NotEmpty(t, hook.ID)
+			assert.NotEmpty(t, hook.Secret)
+		})
+	}
+}
+
+func TestService_NotifyCreated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mockstorage.NewMockRepo(ctrl)
+	mockRepo.EXPECT().CreateShortURL(gomock.Any(), "user1", "http://short.example/", "https://www.youtube.com", gomock.Any(), gomock.Any()).
+		Return("http://short.example/qxDvSD", nil)
+
+	var payload []byte
+	mockHooks := mockstorage.NewMockWebhooks(ctrl)
+	mockHooks.EXPECT().GetWebhooks(gomock.Any(), "user1").
+		Return([]models.Webhook{{ID: "1", Events: []string{models.WebhookLinkCreated}}}, nil)
+	mockHooks.EXPECT().EnqueueDeliveries(gomock.Any(), "user1", models.WebhookLinkCreated, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, p []byte, _ time.Time) (int, error) {
+			payload = p
+			return 1, nil
+		})
+
+	s := New(Params{
+		Storage:  mockRepo,
+		Webhooks: mockHooks,
+		Policy:   policy.New(zap.NewNop(), policy.PrivateAddress{}),
+		Logger:   zap.NewNop(),
+	})
+
+	_, err := s.CreateShortURL(context.Background(), "user1", proxy.Origin{Host: "short.example"}, dto.Request{FullURL: "https://www.youtube.com"})
+	require.NoError(t, err)
+
+	var e dto.WebhookEvent
+	require.NoError(t, json.Unmarshal(payload, &e))
+	assert.Equal(t, models.WebhookLinkCreated, e.Event)
+	assert.WithinDuration(t, time.Now(), e.Time, time.Second)
+	assert.Equal(t, dto.WebhookLink{ID: "qxDvSD", ShortURL: "http://short.example/qxDvSD", OriginalURL: "https://www.youtube.com"}, e.Link)
+	assert.Nil(t, e.Click)
+}
+
+func TestService_NotifyDeleted(t *testing.T) {
+	link := models.Link{ShortURL: "qxDvSD", UserID: "user1", OriginalURL: "https://www.youtube.com"}
+
+	tests := []struct {
+		name     string
+		webhooks []models.Webhook
+		queued   bool
+	}{
+		{
+			name: "No webhooks",
+		},
+		{
+			name:     "Subscribed",
+			webhooks: []models.Webhook{{ID: "1", Events: []string{models.WebhookLinkDeleted}}},
+			queued:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mockstorage.NewMockRepo(ctrl)
+			mockRepo.EXPECT().CreateShortURL(gomock.Any(), "user1", gomock.Any(), "https://www.youtube.com", gomock.Any(), gomock.Any()).
+				Return("http://short.example/qxDvSD", nil)
+			mockRepo.EXPECT().GetLongURL(gomock.Any(), gomock.Any(), "qxDvSD").Return(link, nil)
+			mockRepo.EXPECT().DeleteURLs(gomock.Any(), "user1", gomock.Any(), []string{"qxDvSD"}).Return(nil)
+
+			// Looked up once for both events, created isn't queued either way.
+			mockHooks := mockstorage.NewMockWebhooks(ctrl)
+			mockHooks.EXPECT().GetWebhooks(gomock.Any(), "user1").Return(tt.webhooks, nil)
+
+			queued := make(chan struct{}, 1)
+			if tt.queued {
+				mockHooks.EXPECT().EnqueueDeliveries(gomock.Any(), "user1", models.WebhookLinkDeleted, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(context.Context, string, string, []byte, time.Time) (int, error) {
+						queued <- struct{}{}
+						return 1, nil
+					})
+			}
+
+			s := New(Params{Storage: mockRepo, Webhooks: mockHooks, Logger: zap.NewNop()})
+
+			_, err := s.CreateShortURL(context.Background(), "user1", proxy.Origin{Host: "short.example"}, dto.Request{FullURL: "https://www.youtube.com"})
+			require.NoError(t, err)
+
+			require.NoError(t, s.DeleteURLs(context.Background(), "user1", "short.example", []string{"qxDvSD"}))
+
+			if !tt.queued {
+				return
+			}
+
+			select {
+			case <-queued:
+			case <-time.After(time.Second):
+				t.Fatal("delete wasn't queued")
+			}
+		})
+	}
+}
+
+func TestService_NotifyLive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	link := models.Link{ShortURL: "qxDvSD", UserID: "user1", OriginalURL: "https://www.youtube.com"}
+
+	mockRepo := mockstorage.NewMockRepo(ctrl)
+	mockRepo.EXPECT().BatchCreateShortURL(gomock.Any(), "user1", "http://short.example/", gomock.Len(1), models.URLOptions{}).
+		Return([]dto.BatchResponse{
+			{CorrelationID: "1", ShortURL: "http://short.example/qxDvSD", Status: dto.BatchStatusCreated},
+		}, nil)
+	mockRepo.EXPECT().GetLongURL(gomock.Any(), gomock.Any(), "qxDvSD").Return(link, nil)
+	mockRepo.EXPECT().DeleteURLs(gomock.Any(), "user1", gomock.Any(), []string{"qxDvSD"}).Return(nil)
+
+	hub := clicks.New(zap.NewNop())
+	_, events, cancel := hub.Subscribe("user1", 0)
+	defer cancel()
+
+	// Streamed without audit and webhooks.
+	s := New(Params{
+		Storage: mockRepo,
+		Clicks:  hub,
+		Policy:  policy.New(zap.NewNop(), policy.PrivateAddress{}),
+		Logger:  zap.NewNop(),
+	})
+
+	_, err := s.CreateBatch(context.Background(), "user1", proxy.Origin{Host: "short.example"}, []dto.BatchRequest{
+		{CorrelationID: "1", OriginalURL: "https://www.youtube.com"},
+	}, models.URLOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, s.Visit(context.Background(), link, netip.MustParseAddr("10.0.0.1"), "", "curl"))
+	require.NoError(t, s.DeleteURLs(context.Background(), "user1", "short.example", []string{"qxDvSD"}))
+
+	require.Len(t, events, 3)
+
+	created := <-events
+	assert.Equal(t, models.WebhookLinkCreated, created.Type)
+	assert.Equal(t, "user1", created.UserID)
+	assert.Equal(t, "qxDvSD", created.ShortURL)
+
+	var e dto.WebhookEvent
+	require.NoError(t, json.Unmarshal(created.Data, &e))
+	assert.Equal(t, dto.WebhookLink{ID: "qxDvSD", ShortURL: "http://short.example/qxDvSD", OriginalURL: "https://www.youtube.com"}, e.Link)
+
+	clicked := <-events
+	assert.Equal(t, models.WebhookLinkClicked, clicked.Type)
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), clicked.ClientIP)
+	assert.Equal(t, "curl", clicked.UserAgent)
+	assert.Equal(t, created.ID+1, clicked.ID)
+
+	assert.Equal(t, models.WebhookLinkDeleted, (<-events).Type)
+}
+
+func TestService_NotifyClicked(t *testing.T) {
+	link := models.Link{ShortURL: "qxDvSD", UserID: "user1", OriginalURL: "https://www.youtube.com"}
+
+	tests := []struct {
+		name     string
+		webhooks []models.Webhook
+		queued   bool
+	}{
+		{
+			name: "No webhooks",
+		},
+		{
+			name:     "Not subscribed to clicks",
+			webhooks: []models.Webhook{{ID: "1", Events: []string{models.WebhookLinkCreated}}},
+		},
+		{
+			name:     "Subscribed",
+			webhooks: []models.Webhook{{ID: "1", Events: models.WebhookEvents}},
+			queued:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Looked up once, redirects after the first one use the cache.
+			mockHooks := mockstorage.NewMockWebhooks(ctrl)
+			mockHooks.EXPECT().GetWebhooks(gomock.Any(), "user1").Return(tt.webhooks, nil)
+
+			queued := make(chan string, 2)
+			if tt.queued {
+				mockHooks.EXPECT().EnqueueDeliveries(gomock.Any(), "user1", models.WebhookLinkClicked, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, _, _ string, p []byte, _ time.Time) (int, error) {
+						queued <- string(p)
+						return 1, nil
+					}).Times(2)
+			}
+
+			s := New(Params{Webhooks: mockHooks, Logger: zap.NewNop()})
+
+			for range 2 {
+				require.NoError(t, s.Visit(context.Background(), link, netip.MustParseAddr("10.0.0.1"), "", "curl"))
+			}
+
+			if !tt.queued {
+				return
+			}
+
+			for range 2 {
+				select {
+				case payload := <-queued:
+					assert.Contains(t, payload, `"client_ip":"10.0.0.1"`)
+				case <-time.After(time.Second):
+					t.Fatal("click wasn't queued")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+)
+
+// CheckWorkspaceEditor returns errs.ErrForbidden if user can't create or delete links in a workspace.
+// Empty workspaceID means personal links and is always allowed.
+func (s Service) CheckWorkspaceEditor(ctx context.Context, workspaceID, userID string) error {
+	if workspaceID == "" {
+		return nil
+	}
+
+	if uuid.Validate(workspaceID) != nil {
+		return errs.ErrForbidden
+	}
+
+	role, err := s.storage.GetWorkspaceRole(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !role.CanEdit() {
+		return errs.ErrForbidden
+	}
+
+	return nil
+}
+
+// checkWorkspaceManager returns errs.ErrForbidden if user can't manage members of a workspace.
+func (s Service) checkWorkspaceManager(ctx context.Context, workspaceID, userID string) error {
+	if uuid.Validate(workspaceID) != nil {
+		return errs.ErrWorkspaceNotFound
+	}
+
+	role, err := s.storage.GetWorkspaceRole(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	if !role.CanManage() {
+		return errs.ErrForbidden
+	}
+
+	return nil
+}
+
+// CreateWorkspace creates a workspace owned by userID and returns its ID.
+func (s Service) CreateWorkspace(ctx context.Context, userID, name string) (string, error) {
+	if name == "" {
+		return "", errs.ErrEmptyWorkspaceName
+	}
+
+	ID, err := s.storage.CreateWorkspace(ctx, userID, name)
+	if err != nil {
+		return "", err
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:      models.AuditWorkspaceCreate,
+		ActorID:     userID,
+		WorkspaceID: ID,
+		After:       map[string]any{"name": name},
+	})
+
+	return ID, nil
+}
+
+// UserWorkspaces returns workspaces userID is a member of.
+func (s Service) UserWorkspaces(ctx context.Context, userID string) ([]dto.Workspace, error) {
+	return s.storage.GetUserWorkspaces(ctx, userID)
+}
+
+// AddWorkspaceMember adds memberID to a workspace managed by userID or changes their role.
+func (s Service) AddWorkspaceMember(ctx context.Context, userID, workspaceID, memberID string, role models.Role) error {
+	if !role.Valid() {
+		return errs.ErrUnknownRole
+	}
+
+	if uuid.Validate(memberID) != nil {
+		return errs.ErrInvalidUserID
+	}
+
+	err := s.checkWorkspaceManager(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	before := s.memberValues(ctx, workspaceID, memberID)
+
+	err = s.storage.AddWorkspaceMember(ctx, workspaceID, memberID, role)
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:      models.AuditMemberAdd,
+		ActorID:     userID,
+		WorkspaceID: workspaceID,
+		Before:      before,
+		After:       map[string]any{"user_id": memberID, "role": string(role)},
+	})
+
+	return nil
+}
+
+// RemoveWorkspaceMember removes memberID from a workspace managed by userID.
+func (s Service) RemoveWorkspaceMember(ctx context.Context, userID, workspaceID, memberID string) error {
+	err := s.checkWorkspaceManager(ctx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	before := s.memberValues(ctx, workspaceID, memberID)
+
+	err = s.storage.RemoveWorkspaceMember(ctx, workspaceID, memberID)
+	if err != nil {
+		return err
+	}
+
+	s.audit(ctx, models.AuditEvent{
+		Action:      models.AuditMemberRemove,
+		ActorID:     userID,
+		WorkspaceID: workspaceID,
+		Before:      before,
+	})
+
+	return nil
+}
+
+// memberValues describes membership of memberID in a workspace for audit events, nil if they aren't a member.
+func (s Service) memberValues(ctx context.Context, workspaceID, memberID string) map[string]any {
+	if s.auditor == nil {
+		return nil
+	}
+
+	role, err := s.storage.GetWorkspaceRole(ctx, workspaceID, memberID)
+	if err != nil {
+		return nil
+	}
+
+	return map[string]any{"user_id": memberID, "role": string(role)}
+}
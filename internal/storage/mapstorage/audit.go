@@ -0,0 +1,136 @@
+package mapstorage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"go.uber.org/zap"
+)
+
+// maxAuditLine caps the length of a line of the audit log file.
+const maxAuditLine = 1 << 20
+
+// AppendAudit adds events to the audit log. Events are written to the JSONL file right away,
+// one per line, the file is only ever appended to. The rest of the storage isn't locked while writing.
+func (s *MapStorage) AppendAudit(ctx context.Context, events ...models.AuditEvent) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, e := range events {
+		err := encoder.Encode(e)
+		if err != nil {
+			s.logger.Error("mapstorage:AppendAudit Error encoding event", zap.Error(err))
+			return errs.ErrInternalServerError
+		}
+	}
+
+	// Held until events are in memory too, so that they are listed in the order of the file.
+	s.auditM.Lock()
+	defer s.auditM.Unlock()
+
+	file, err := os.OpenFile(s.auditPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		s.logger.Error("mapstorage:AppendAudit Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	_, err = file.Write(buf.Bytes())
+	if err != nil {
+		s.logger.Error("mapstorage:AppendAudit Error writing file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	s.m.Lock()
+	s.Audit = append(s.Audit, events...)
+	s.m.Unlock()
+
+	return nil
+}
+
+// QueryAudit returns audit events matching q, newest first.
+func (s *MapStorage) QueryAudit(ctx context.Context, q models.AuditQuery) ([]models.AuditEvent, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var result []models.AuditEvent
+	for _, e := range slices.Backward(s.Audit) {
+		if q.Limit > 0 && len(result) == q.Limit {
+			break
+		}
+
+		if auditMatches(e, q) {
+			result = append(result, e)
+		}
+	}
+
+	return result, nil
+}
+
+func auditMatches(e models.AuditEvent, q models.AuditQuery) bool {
+	switch {
+	case q.ActorID != "" && q.ActorID != e.ActorID:
+		return false
+	case q.ShortURL != "" && q.ShortURL != e.ShortURL:
+		return false
+	case len(q.Domains) > 0 && !slices.Contains(q.Domains, e.Domain):
+		return false
+	case q.Action != "" && q.Action != e.Action:
+		return false
+	case !q.From.IsZero() && e.Time.Before(q.From):
+		return false
+	case !q.To.IsZero() && !e.Time.Before(q.To):
+		return false
+	default:
+		return true
+	}
+}
+
+func auditPath(filepath string) string {
+	return filepath + ".audit.jsonl"
+}
+
+// loadAudit reads the audit log file. A line that can't be decoded, like the last one after a crash
+// in the middle of a write, is skipped.
+func (s *MapStorage) loadAudit() error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	file, err := os.Open(s.auditPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		s.logger.Error("mapstorage:loadAudit Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxAuditLine)
+
+	for line := 1; scanner.Scan(); line++ {
+		var e models.AuditEvent
+		err = json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			s.logger.Warn("mapstorage:loadAudit Skipping malformed line", zap.Int("line", line), zap.Error(err))
+			continue
+		}
+
+		s.Audit = append(s.Audit, e)
+	}
+
+	if scanner.Err() != nil {
+		s.logger.Error("mapstorage:loadAudit Error reading file", zap.Error(scanner.Err()))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
@@ -1,27 +1,52 @@
 package mapstorage
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/models"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 )
 
 type MapStorage struct {
-	FullURLStorage  map[string]string            // FullURLStorage[ShortURL]FullURL
-	ShortURLStorage map[string]string            // ShortURLStorage[FullURL]ShortURL
-	UserLinkStorage map[string]map[string]string // UserLinkStorage[UserID][ShortURL]FullURL
-	m               sync.RWMutex
-	logger          *zap.Logger
+	FullURLStorage   map[string]string            // FullURLStorage[LinkKey]FullURL, see linkKey
+	ShortURLStorage  map[string]string            // ShortURLStorage[DedupeKey]ShortURL, see dedupeKey
+	UserLinkStorage  map[string]map[string]string // UserLinkStorage[UserID][LinkKey]FullURL
+	WorkspaceStorage map[string]*models.Workspace // WorkspaceStorage[WorkspaceID]Workspace
+	LinkOptions      map[string]models.URLOptions // LinkOptions[LinkKey]URLOptions
+	ClicksLeft       map[string]int               // ClicksLeft[LinkKey]RemainingClicks, only for limited links
+	LinkMeta         map[string]models.LinkMeta   // LinkMeta[LinkKey]LinkMeta
+	Takedowns        map[string]models.Takedown   // Takedowns[LinkKey]Takedown, only for disabled links
+	Reports          map[string]models.Report     // Reports[ReportID]Report
+	Audit            []models.AuditEvent          // oldest first, mirrors the audit log file
+	Webhooks         map[string]models.Webhook    // Webhooks[WebhookID]Webhook
+	Deliveries       map[string]models.Delivery   // Deliveries[DeliveryID]Delivery, the webhook outbox
+	auditPath        string
+	dedupe           models.DedupeScope
+	m                sync.RWMutex
+	logger           *zap.Logger
+	// auditM serializes appends to the audit log file, so that m isn't held while writing to disk.
+	auditM sync.Mutex
 }
 
 func newMapStorage(cfg *config.Config, logger *zap.Logger) (*MapStorage, error) {
 	storage := &MapStorage{
-		FullURLStorage:  make(map[string]string),
-		ShortURLStorage: make(map[string]string),
-		UserLinkStorage: make(map[string]map[string]string),
-		logger:          logger,
+		FullURLStorage:   make(map[string]string),
+		ShortURLStorage:  make(map[string]string),
+		UserLinkStorage:  make(map[string]map[string]string),
+		WorkspaceStorage: make(map[string]*models.Workspace),
+		LinkOptions:      make(map[string]models.URLOptions),
+		ClicksLeft:       make(map[string]int),
+		LinkMeta:         make(map[string]models.LinkMeta),
+		Takedowns:        make(map[string]models.Takedown),
+		Reports:          make(map[string]models.Report),
+		Webhooks:         make(map[string]models.Webhook),
+		Deliveries:       make(map[string]models.Delivery),
+		auditPath:        auditPath(cfg.Filepath),
+		dedupe:           models.DedupeScope(cfg.DedupeScope),
+		logger:           logger,
 	}
 
 	err := storage.LoadStorage(cfg.Filepath)
@@ -29,9 +54,48 @@ func newMapStorage(cfg *config.Config, logger *zap.Logger) (*MapStorage, error)
 		return nil, err
 	}
 
+	err = storage.loadWorkspaces(workspacesPath(cfg.Filepath))
+	if err != nil {
+		return nil, err
+	}
+
+	err = storage.loadReports(reportsPath(cfg.Filepath))
+	if err != nil {
+		return nil, err
+	}
+
+	err = storage.loadWebhooks(webhooksPath(cfg.Filepath))
+	if err != nil {
+		return nil, err
+	}
+
+	err = storage.loadAudit()
+	if err != nil {
+		return nil, err
+	}
+
 	return storage, nil
 }
 
 func Provide() fx.Option {
 	return fx.Provide(newMapStorage)
 }
+
+// linkKey returns the key a link with ID on domain is stored under. Links on the default domain
+// are keyed by ID alone, as they were before short domains.
+func linkKey(domain, ID string) string {
+	if domain == "" {
+		return ID
+	}
+
+	return domain + "/" + ID
+}
+
+// splitKey returns the domain and ID of a link stored under key.
+func splitKey(key string) (domain, ID string) {
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		return key[:i], key[i+1:]
+	}
+
+	return "", key
+}
@@ -6,6 +6,8 @@ import (
 	"errors"
 	"io"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/MukizuL/shortener/internal/dto"
 	"github.com/MukizuL/shortener/internal/errs"
@@ -14,87 +16,252 @@ import (
 	"go.uber.org/zap"
 )
 
-func (s *MapStorage) CreateShortURL(ctx context.Context, userID, urlBase, fullURL string) (string, error) {
+func (s *MapStorage) CreateShortURL(ctx context.Context, userID, urlBase, fullURL, canonicalURL string, opts models.URLOptions) (string, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	if v, exist := s.ShortURLStorage[fullURL]; exist {
-		return urlBase + v, errs.ErrDuplicate
+	if key, ok := s.dedupeKey(opts.Domain, userID, canonicalURL); ok {
+		if v, exist := s.ShortURLStorage[key]; exist {
+			return urlBase + v, errs.ErrDuplicate
+		}
 	}
 
-	ID := helpers.RandomString(6)
+	ID := s.newID(opts.Domain, nil)
 	shortURL := urlBase + ID
 
-	s.ShortURLStorage[fullURL] = ID
+	s.addLink(userID, ID, fullURL, canonicalURL, opts)
 
-	s.FullURLStorage[ID] = fullURL
+	return shortURL, nil
+}
 
-	if _, ok := s.UserLinkStorage[userID]; !ok {
-		s.UserLinkStorage[userID] = make(map[string]string)
+func (s *MapStorage) BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest, opts models.URLOptions) ([]dto.BatchResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	result := make([]dto.BatchResponse, 0, len(data))
+
+	// Results are resolved before anything is stored, so a failure can't leave the batch half-written.
+	created := make(map[string]string)
+	newIDs := make([]string, len(data))
+	// IDs of the batch aren't stored yet, they are kept apart so that two items can't get the same one.
+	reserved := make(map[string]struct{})
+
+	for i, v := range data {
+		key, dedupe := s.dedupeKey(opts.Domain, userID, v.CanonicalURL)
+		if dedupe {
+			if ID, exist := s.ShortURLStorage[key]; exist {
+				result = append(result, dto.BatchResponse{CorrelationID: v.CorrelationID, ShortURL: urlBase + ID, Status: dto.BatchStatusExisting})
+				continue
+			}
+
+			if ID, exist := created[key]; exist {
+				result = append(result, dto.BatchResponse{CorrelationID: v.CorrelationID, ShortURL: urlBase + ID, Status: dto.BatchStatusExisting})
+				continue
+			}
+		}
+
+		ID := s.newID(opts.Domain, reserved)
+		if dedupe {
+			created[key] = ID
+		}
+		newIDs[i] = ID
+
+		result = append(result, dto.BatchResponse{CorrelationID: v.CorrelationID, ShortURL: urlBase + ID, Status: dto.BatchStatusCreated})
 	}
 
-	s.UserLinkStorage[userID][ID] = fullURL
+	for i, v := range data {
+		if newIDs[i] != "" {
+			s.addLink(userID, newIDs[i], v.OriginalURL, v.CanonicalURL, opts)
+		}
+	}
 
-	return shortURL, nil
+	return result, nil
 }
 
-func (s *MapStorage) BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest) ([]dto.BatchResponse, error) {
+func (s *MapStorage) GetLongURL(ctx context.Context, domain, ID string) (models.Link, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	link, exist := s.link(linkKey(domain, ID))
+	if !exist {
+		return models.Link{}, errs.ErrURLNotFound
+	}
+
+	return link, nil
+}
+
+// link returns the link stored under key. Must be called under lock.
+func (s *MapStorage) link(key string) (models.Link, bool) {
+	val, exist := s.FullURLStorage[key]
+	if !exist {
+		return models.Link{}, false
+	}
+
+	opts := s.LinkOptions[key]
+	meta := s.LinkMeta[key]
+	domain, ID := splitKey(key)
+
+	return models.Link{
+		ShortURL:     ID,
+		Domain:       domain,
+		OriginalURL:  val,
+		UserID:       meta.UserID,
+		WorkspaceID:  opts.WorkspaceID,
+		CreatedAt:    meta.CreatedAt,
+		PasswordHash: opts.PasswordHash,
+		MaxClicks:    opts.MaxClicks,
+		ClicksLeft:   s.ClicksLeft[key],
+		RedirectType: opts.RedirectType,
+		Takedown:     s.Takedowns[key],
+	}, true
+}
+
+// ConsumeClick uses up one click of a limited link. Returns errs.ErrGone when no clicks are left.
+func (s *MapStorage) ConsumeClick(ctx context.Context, domain, ID string) (int, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	result := make([]dto.BatchResponse, 0, len(data))
+	key := linkKey(domain, ID)
 
-	for _, v := range data {
-		if _, exist := s.ShortURLStorage[v.OriginalURL]; exist {
-			return nil, errs.ErrDuplicate
-		}
+	if _, exist := s.FullURLStorage[key]; !exist {
+		return 0, errs.ErrURLNotFound
+	}
 
-		ID := helpers.RandomString(6)
-		shortURL := urlBase + ID
+	left, ok := s.ClicksLeft[key]
+	if !ok || left <= 0 {
+		return 0, errs.ErrGone
+	}
 
-		s.ShortURLStorage[v.OriginalURL] = ID
+	left--
+	s.ClicksLeft[key] = left
 
-		result = append(result, dto.BatchResponse{CorrelationID: v.CorrelationID, ShortURL: shortURL})
+	return left, nil
+}
+
+// newID returns a random short URL ID that is not taken on domain yet nor in reserved, and adds it to reserved
+// unless it is nil. Must be called under lock.
+func (s *MapStorage) newID(domain string, reserved map[string]struct{}) string {
+	for {
+		ID := helpers.RandomString(6)
+		if _, taken := s.FullURLStorage[linkKey(domain, ID)]; taken {
+			continue
+		}
 
-		s.FullURLStorage[ID] = v.OriginalURL
+		if _, taken := reserved[ID]; taken {
+			continue
+		}
 
-		if _, ok := s.UserLinkStorage[userID]; !ok {
-			s.UserLinkStorage[userID] = make(map[string]string)
+		if reserved != nil {
+			reserved[ID] = struct{}{}
 		}
 
-		s.UserLinkStorage[userID][ID] = v.OriginalURL
+		return ID
+	}
+}
+
+// addLink stores a new link with ID on opts.Domain created now. Must be called under lock.
+func (s *MapStorage) addLink(userID, ID, fullURL, canonicalURL string, opts models.URLOptions) {
+	key := linkKey(opts.Domain, ID)
+
+	if dedupeKey, ok := s.dedupeKey(opts.Domain, userID, canonicalURL); ok {
+		s.ShortURLStorage[dedupeKey] = ID
 	}
 
-	return result, nil
+	s.FullURLStorage[key] = fullURL
+
+	if _, ok := s.UserLinkStorage[userID]; !ok {
+		s.UserLinkStorage[userID] = make(map[string]string)
+	}
+
+	s.UserLinkStorage[userID][key] = fullURL
+
+	s.LinkMeta[key] = models.LinkMeta{UserID: userID, CanonicalURL: canonicalURL, CreatedAt: time.Now()}
+
+	s.setOptions(key, opts)
 }
 
-func (s *MapStorage) GetLongURL(ctx context.Context, ID string) (string, error) {
-	s.m.RLock()
-	defer s.m.RUnlock()
+// dedupeKey returns the key under which existing links to canonicalURL on domain are looked up for userID.
+// Reports false when links are not deduplicated.
+func (s *MapStorage) dedupeKey(domain, userID, canonicalURL string) (string, bool) {
+	// URLs can't contain spaces, so the key is unambiguous.
+	var key string
+
+	switch s.dedupe {
+	case models.DedupeNone:
+		return "", false
+	case models.DedupePerUser:
+		key = userID + " " + canonicalURL
+	default:
+		key = canonicalURL
+	}
+
+	if domain != "" {
+		key = domain + " " + key
+	}
+
+	return key, true
+}
+
+// setOptions stores non-default options of a new link. Must be called under lock.
+func (s *MapStorage) setOptions(key string, opts models.URLOptions) {
+	if opts == (models.URLOptions{}) {
+		return
+	}
 
-	if val, exist := s.FullURLStorage[ID]; !exist {
-		return "", errs.ErrURLNotFound
-	} else {
-		return val, nil
+	s.LinkOptions[key] = opts
+
+	if opts.MaxClicks > 0 {
+		s.ClicksLeft[key] = opts.MaxClicks
 	}
 }
 
-func (s *MapStorage) GetUserURLs(ctx context.Context, userID string) ([]dto.URLPair, error) {
+func (s *MapStorage) GetUserURLs(ctx context.Context, userID string, domains []string) ([]dto.URLPair, error) {
 	s.m.RLock()
 	defer s.m.RUnlock()
 
 	var result []dto.URLPair
 
+	workspaces := s.userWorkspaces(userID)
+
 	data, ok := s.UserLinkStorage[userID]
-	if !ok {
+	if !ok && len(workspaces) == 0 {
 		return nil, errs.ErrURLNotFound
 	}
 
 	for k := range data {
+		domain, ID := splitKey(k)
+		if len(domains) > 0 && !slices.Contains(domains, domain) {
+			continue
+		}
+
 		fullURL := s.FullURLStorage[k]
 		pair := dto.URLPair{
-			ShortURL:    k,
+			ShortURL:    ID,
 			OriginalURL: fullURL,
+			Domain:      domain,
+		}
+
+		result = append(result, pair)
+	}
+
+	for k, opts := range s.LinkOptions {
+		if _, own := data[k]; own || opts.WorkspaceID == "" {
+			continue
+		}
+
+		if _, member := workspaces[opts.WorkspaceID]; !member {
+			continue
+		}
+
+		domain, ID := splitKey(k)
+		if len(domains) > 0 && !slices.Contains(domains, domain) {
+			continue
+		}
+
+		pair := dto.URLPair{
+			ShortURL:    ID,
+			OriginalURL: s.FullURLStorage[k],
+			Domain:      domain,
 		}
 
 		result = append(result, pair)
@@ -103,25 +270,53 @@ func (s *MapStorage) GetUserURLs(ctx context.Context, userID string) ([]dto.URLP
 	return result, nil
 }
 
-func (s *MapStorage) DeleteURLs(ctx context.Context, userID string, urls []string) error {
-	userURLs, ok := s.UserLinkStorage[userID]
-	if !ok {
-		return errs.ErrURLNotFound
+// WalkUserURLs calls fn for a snapshot of the user's URLs, so the storage isn't locked while fn runs.
+func (s *MapStorage) WalkUserURLs(ctx context.Context, userID string, domains []string, fn func(dto.URLPair) error) error {
+	data, err := s.GetUserURLs(ctx, userID, domains)
+	if err != nil {
+		if errors.Is(err, errs.ErrURLNotFound) {
+			return nil
+		}
+
+		return err
 	}
 
+	for _, pair := range data {
+		err = fn(pair)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *MapStorage) DeleteURLs(ctx context.Context, userID, domain string, urls []string) error {
 	s.m.Lock()
 	defer s.m.Unlock()
 
 	for _, url := range urls {
-		if _, ok = userURLs[url]; !ok {
+		if !s.canModify(userID, linkKey(domain, url)) {
 			return errs.ErrUserMismatch
 		}
+	}
 
-		fullURL := s.FullURLStorage[url]
+	for _, url := range urls {
+		key := linkKey(domain, url)
+		meta := s.LinkMeta[key]
+
+		for _, userURLs := range s.UserLinkStorage {
+			delete(userURLs, key)
+		}
 
-		delete(s.UserLinkStorage[userID], url)
-		delete(s.FullURLStorage, fullURL)
-		delete(s.ShortURLStorage, url)
+		delete(s.FullURLStorage, key)
+		if dedupeKey, ok := s.dedupeKey(domain, meta.UserID, meta.CanonicalURL); ok && s.ShortURLStorage[dedupeKey] == url {
+			delete(s.ShortURLStorage, dedupeKey)
+		}
+		delete(s.LinkOptions, key)
+		delete(s.ClicksLeft, key)
+		delete(s.LinkMeta, key)
+		delete(s.Takedowns, key)
 	}
 
 	return nil
@@ -131,7 +326,7 @@ func (s *MapStorage) GetStats(ctx context.Context) (int, int, error) {
 	s.m.Lock()
 	defer s.m.Unlock()
 
-	return len(s.ShortURLStorage), len(s.UserLinkStorage), nil
+	return len(s.FullURLStorage), len(s.UserLinkStorage), nil
 }
 
 func (s *MapStorage) LoadStorage(filepath string) error {
@@ -166,9 +361,36 @@ func (s *MapStorage) LoadStorage(filepath string) error {
 			s.UserLinkStorage[entry.UserID] = make(map[string]string)
 		}
 
-		s.FullURLStorage[entry.ShortURL] = entry.OriginalURL
-		s.ShortURLStorage[entry.OriginalURL] = entry.ShortURL
-		s.UserLinkStorage[entry.UserID][entry.ShortURL] = entry.OriginalURL
+		// Files written before canonicalization have no canonical form.
+		canonicalURL := entry.CanonicalURL
+		if canonicalURL == "" {
+			canonicalURL = entry.OriginalURL
+		}
+
+		key := linkKey(entry.Domain, entry.ShortURL)
+
+		s.FullURLStorage[key] = entry.OriginalURL
+		if dedupeKey, ok := s.dedupeKey(entry.Domain, entry.UserID, canonicalURL); ok {
+			s.ShortURLStorage[dedupeKey] = entry.ShortURL
+		}
+		s.UserLinkStorage[entry.UserID][key] = entry.OriginalURL
+		s.LinkMeta[key] = models.LinkMeta{UserID: entry.UserID, CanonicalURL: canonicalURL, CreatedAt: entry.CreatedAt}
+
+		s.setOptions(key, models.URLOptions{
+			Domain:       entry.Domain,
+			WorkspaceID:  entry.WorkspaceID,
+			PasswordHash: entry.PasswordHash,
+			MaxClicks:    entry.MaxClicks,
+			RedirectType: entry.RedirectType,
+		})
+
+		if entry.MaxClicks > 0 {
+			s.ClicksLeft[key] = entry.ClicksLeft
+		}
+
+		if entry.DisabledStatus != 0 {
+			s.Takedowns[key] = models.Takedown{Status: entry.DisabledStatus, Reason: entry.DisabledReason}
+		}
 	}
 
 	return nil
@@ -188,10 +410,23 @@ func (s *MapStorage) OffloadStorage(ctx context.Context, filepath string) error
 	var data []models.Urls
 	for k, v := range s.UserLinkStorage {
 		for kInner, vInner := range v {
+			opts := s.LinkOptions[kInner]
+			domain, ID := splitKey(kInner)
+
 			data = append(data, models.Urls{
-				UserID:      k,
-				ShortURL:    kInner,
-				OriginalURL: vInner,
+				UserID:         k,
+				ShortURL:       ID,
+				Domain:         domain,
+				OriginalURL:    vInner,
+				CanonicalURL:   s.LinkMeta[kInner].CanonicalURL,
+				WorkspaceID:    opts.WorkspaceID,
+				PasswordHash:   opts.PasswordHash,
+				MaxClicks:      opts.MaxClicks,
+				ClicksLeft:     s.ClicksLeft[kInner],
+				RedirectType:   opts.RedirectType,
+				CreatedAt:      s.LinkMeta[kInner].CreatedAt,
+				DisabledStatus: s.Takedowns[kInner].Status,
+				DisabledReason: s.Takedowns[kInner].Reason,
 			})
 		}
 	}
@@ -202,7 +437,17 @@ func (s *MapStorage) OffloadStorage(ctx context.Context, filepath string) error
 		return errs.ErrInternalServerError
 	}
 
-	return nil
+	err = s.offloadWorkspaces(workspacesPath(filepath))
+	if err != nil {
+		return err
+	}
+
+	err = s.offloadReports(reportsPath(filepath))
+	if err != nil {
+		return err
+	}
+
+	return s.offloadWebhooks(webhooksPath(filepath))
 }
 
 func (s *MapStorage) Ping(ctx context.Context) error {
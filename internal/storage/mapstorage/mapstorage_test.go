@@ -0,0 +1,552 @@
+package mapstorage
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestStorage(t *testing.T) *MapStorage {
+	t.Helper()
+
+	s, err := newMapStorage(&config.Config{Filepath: t.TempDir() + "/storage.json"}, zap.NewNop())
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestMapStorage_ConsumeClickConcurrent(t *testing.T) {
+	const (
+		maxClicks = 10
+		requests  = 100
+	)
+
+	s := newTestStorage(t)
+
+	shortURL, err := s.CreateShortURL(context.Background(), "user1", "", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{MaxClicks: maxClicks})
+	require.NoError(t, err)
+
+	var (
+		wg      sync.WaitGroup
+		success atomic.Int32
+		gone    atomic.Int32
+	)
+
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := s.ConsumeClick(context.Background(), "", shortURL)
+			switch {
+			case err == nil:
+				success.Add(1)
+			case errors.Is(err, errs.ErrGone):
+				gone.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(maxClicks), success.Load())
+	assert.Equal(t, int32(requests-maxClicks), gone.Load())
+
+	link, err := s.GetLongURL(context.Background(), "", shortURL)
+	require.NoError(t, err)
+	assert.True(t, link.Exhausted())
+}
+
+func TestMapStorage_ConsumeClickUnlimited(t *testing.T) {
+	s := newTestStorage(t)
+
+	shortURL, err := s.CreateShortURL(context.Background(), "user1", "", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{})
+	require.NoError(t, err)
+
+	_, err = s.ConsumeClick(context.Background(), "", shortURL)
+	assert.ErrorIs(t, err, errs.ErrGone)
+
+	link, err := s.GetLongURL(context.Background(), "", shortURL)
+	require.NoError(t, err)
+	assert.False(t, link.Limited())
+}
+
+func TestMapStorage_LinkMetaPersisted(t *testing.T) {
+	path := t.TempDir() + "/storage.json"
+
+	s, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	shortURL, err := s.CreateShortURL(context.Background(), "user1", "", "https://www.youtube.com", "https://www.youtube.com/", models.URLOptions{})
+	require.NoError(t, err)
+
+	created, err := s.GetLongURL(context.Background(), "", shortURL)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", created.UserID)
+	assert.False(t, created.CreatedAt.IsZero())
+
+	err = s.OffloadStorage(context.Background(), path)
+	require.NoError(t, err)
+
+	loaded, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	link, err := loaded.GetLongURL(context.Background(), "", shortURL)
+	require.NoError(t, err)
+	assert.Equal(t, "user1", link.UserID)
+	assert.True(t, created.CreatedAt.Equal(link.CreatedAt))
+}
+
+func TestMapStorage_DedupeOnCanonical(t *testing.T) {
+	s := newTestStorage(t)
+
+	shortURL, err := s.CreateShortURL(context.Background(), "user1", "", "HTTP://Example.com:80/a", "http://example.com/a", models.URLOptions{})
+	require.NoError(t, err)
+
+	duplicate, err := s.CreateShortURL(context.Background(), "user2", "", "http://example.com/a", "http://example.com/a", models.URLOptions{})
+	assert.ErrorIs(t, err, errs.ErrDuplicate)
+	assert.Equal(t, shortURL, duplicate)
+
+	link, err := s.GetLongURL(context.Background(), "", shortURL)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP://Example.com:80/a", link.OriginalURL)
+
+	err = s.DeleteURLs(context.Background(), "user1", "", []string{shortURL})
+	require.NoError(t, err)
+
+	_, err = s.CreateShortURL(context.Background(), "user2", "", "http://example.com/a", "http://example.com/a", models.URLOptions{})
+	assert.NoError(t, err)
+}
+
+func TestMapStorage_DedupeScope(t *testing.T) {
+	const canonicalURL = "https://www.youtube.com/"
+
+	tests := []struct {
+		name          string
+		scope         models.DedupeScope
+		wantSameUser  error
+		wantOtherUser string
+	}{
+		{
+			name:          "Global",
+			scope:         models.DedupeGlobal,
+			wantSameUser:  errs.ErrDuplicate,
+			wantOtherUser: dto.BatchStatusExisting,
+		},
+		{
+			name:          "Per user",
+			scope:         models.DedupePerUser,
+			wantSameUser:  errs.ErrDuplicate,
+			wantOtherUser: dto.BatchStatusCreated,
+		},
+		{
+			name:          "None",
+			scope:         models.DedupeNone,
+			wantOtherUser: dto.BatchStatusCreated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Filepath: t.TempDir() + "/storage.json", DedupeScope: string(tt.scope)}
+
+			s, err := newMapStorage(cfg, zap.NewNop())
+			require.NoError(t, err)
+
+			_, err = s.CreateShortURL(context.Background(), "user1", "", canonicalURL, canonicalURL, models.URLOptions{})
+			require.NoError(t, err)
+
+			_, err = s.CreateShortURL(context.Background(), "user1", "", canonicalURL, canonicalURL, models.URLOptions{})
+			assert.ErrorIs(t, err, tt.wantSameUser)
+
+			result, err := s.BatchCreateShortURL(context.Background(), "user2", "", []dto.BatchRequest{
+				{CorrelationID: "1", OriginalURL: canonicalURL, CanonicalURL: canonicalURL},
+			}, models.URLOptions{})
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, tt.wantOtherUser, result[0].Status)
+		})
+	}
+}
+
+func TestMapStorage_BatchPartial(t *testing.T) {
+	cfg := &config.Config{Filepath: t.TempDir() + "/storage.json", DedupeScope: string(models.DedupeGlobal)}
+
+	s, err := newMapStorage(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	existing, err := s.CreateShortURL(context.Background(), "user1", "http://localhost:8080/", "https://a.example/", "https://a.example/", models.URLOptions{})
+	require.NoError(t, err)
+
+	result, err := s.BatchCreateShortURL(context.Background(), "user1", "http://localhost:8080/", []dto.BatchRequest{
+		{CorrelationID: "1", OriginalURL: "https://a.example/", CanonicalURL: "https://a.example/"},
+		{CorrelationID: "2", OriginalURL: "https://b.example/", CanonicalURL: "https://b.example/"},
+		{CorrelationID: "3", OriginalURL: "https://B.example", CanonicalURL: "https://b.example/"},
+	}, models.URLOptions{})
+	require.NoError(t, err)
+	require.Len(t, result, 3)
+
+	assert.Equal(t, dto.BatchResponse{CorrelationID: "1", ShortURL: existing, Status: dto.BatchStatusExisting}, result[0])
+	assert.Equal(t, dto.BatchStatusCreated, result[1].Status)
+	assert.Equal(t, dto.BatchResponse{CorrelationID: "3", ShortURL: result[1].ShortURL, Status: dto.BatchStatusExisting}, result[2])
+
+	link, err := s.GetLongURL(context.Background(), "", result[1].ShortURL[len("http://localhost:8080/"):])
+	require.NoError(t, err)
+	assert.Equal(t, "https://b.example/", link.OriginalURL)
+}
+
+func TestMapStorage_BatchDistinctIDs(t *testing.T) {
+	cfg := &config.Config{Filepath: t.TempDir() + "/storage.json", DedupeScope: string(models.DedupeNone)}
+
+	s, err := newMapStorage(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	const n = 20000
+	data := make([]dto.BatchRequest, 0, n)
+	for i := range n {
+		url := "https://a.example/" + strconv.Itoa(i)
+		data = append(data, dto.BatchRequest{CorrelationID: strconv.Itoa(i), OriginalURL: url, CanonicalURL: url})
+	}
+
+	result, err := s.BatchCreateShortURL(context.Background(), "user1", "", data, models.URLOptions{})
+	require.NoError(t, err)
+
+	// Every item keeps its own link, none overwrote another one created in the batch.
+	seen := make(map[string]struct{}, n)
+	for _, res := range result {
+		seen[res.ShortURL] = struct{}{}
+	}
+	assert.Len(t, seen, n)
+	assert.Len(t, s.FullURLStorage, n)
+}
+
+func TestMapStorage_Domains(t *testing.T) {
+	path := t.TempDir() + "/storage.json"
+	cfg := &config.Config{Filepath: path, DedupeScope: string(models.DedupeGlobal)}
+
+	s, err := newMapStorage(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	const fullURL = "https://www.youtube.com/"
+
+	def, err := s.CreateShortURL(context.Background(), "user1", "", fullURL, fullURL, models.URLOptions{})
+	require.NoError(t, err)
+
+	// The same URL is shortened again on another domain instead of returning the link of the default one.
+	branded, err := s.CreateShortURL(context.Background(), "user1", "", fullURL, fullURL, models.URLOptions{Domain: "brand.ly"})
+	require.NoError(t, err)
+
+	_, err = s.CreateShortURL(context.Background(), "user2", "", fullURL, fullURL, models.URLOptions{Domain: "brand.ly"})
+	assert.ErrorIs(t, err, errs.ErrDuplicate)
+
+	link, err := s.GetLongURL(context.Background(), "brand.ly", branded)
+	require.NoError(t, err)
+	assert.Equal(t, "brand.ly", link.Domain)
+
+	if branded != def {
+		_, err = s.GetLongURL(context.Background(), "", branded)
+		assert.ErrorIs(t, err, errs.ErrURLNotFound)
+	}
+
+	pairs, err := s.GetUserURLs(context.Background(), "user1", []string{"brand.ly"})
+	require.NoError(t, err)
+	assert.Equal(t, []dto.URLPair{{ShortURL: branded, OriginalURL: fullURL, Domain: "brand.ly"}}, pairs)
+
+	pairs, err = s.GetUserURLs(context.Background(), "user1", nil)
+	require.NoError(t, err)
+	assert.Len(t, pairs, 2)
+
+	err = s.OffloadStorage(context.Background(), path)
+	require.NoError(t, err)
+
+	loaded, err := newMapStorage(cfg, zap.NewNop())
+	require.NoError(t, err)
+
+	link, err = loaded.GetLongURL(context.Background(), "brand.ly", branded)
+	require.NoError(t, err)
+	assert.Equal(t, fullURL, link.OriginalURL)
+
+	err = loaded.DeleteURLs(context.Background(), "user1", "brand.ly", []string{branded})
+	require.NoError(t, err)
+
+	_, err = loaded.GetLongURL(context.Background(), "", def)
+	assert.NoError(t, err)
+}
+
+func TestMapStorage_LastOwner(t *testing.T) {
+	s := newTestStorage(t)
+	ctx := context.Background()
+
+	ID, err := s.CreateWorkspace(ctx, "owner1", "Marketing")
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, s.AddWorkspaceMember(ctx, ID, "owner1", models.RoleEditor), errs.ErrLastOwner)
+	assert.ErrorIs(t, s.RemoveWorkspaceMember(ctx, ID, "owner1"), errs.ErrLastOwner)
+
+	require.NoError(t, s.AddWorkspaceMember(ctx, ID, "owner2", models.RoleOwner))
+	require.NoError(t, s.AddWorkspaceMember(ctx, ID, "owner1", models.RoleViewer))
+	assert.ErrorIs(t, s.RemoveWorkspaceMember(ctx, ID, "owner2"), errs.ErrLastOwner)
+
+	require.NoError(t, s.RemoveWorkspaceMember(ctx, ID, "owner1"))
+
+	role, err := s.GetWorkspaceRole(ctx, ID, "owner2")
+	require.NoError(t, err)
+	assert.Equal(t, models.RoleOwner, role)
+}
+
+func TestMapStorage_Moderation(t *testing.T) {
+	path := t.TempDir() + "/storage.json"
+
+	s, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	evil, err := s.CreateShortURL(ctx, "user1", "", "https://evil.example/a", "https://evil.example/a", models.URLOptions{})
+	require.NoError(t, err)
+	sub, err := s.CreateShortURL(ctx, "user2", "", "https://cdn.Evil.example/b", "https://cdn.evil.example/b", models.URLOptions{})
+	require.NoError(t, err)
+	_, err = s.CreateShortURL(ctx, "user1", "", "https://notevil.example/c", "https://notevil.example/c", models.URLOptions{})
+	require.NoError(t, err)
+
+	links, err := s.FindLinks(ctx, models.LinkQuery{Host: "evil.example"})
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+
+	links, err = s.FindLinks(ctx, models.LinkQuery{UserID: "user1"})
+	require.NoError(t, err)
+	assert.Len(t, links, 2)
+
+	takedown := models.Takedown{Status: 451, Reason: "Court order"}
+	n, err := s.DisableLinks(ctx, models.LinkQuery{Host: "evil.example"}, takedown)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	n, err = s.DisableLinks(ctx, models.LinkQuery{ShortURL: sub, Domains: []string{""}}, models.Takedown{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	err = s.OffloadStorage(ctx, path)
+	require.NoError(t, err)
+
+	loaded, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	link, err := loaded.GetLongURL(ctx, "", evil)
+	require.NoError(t, err)
+	assert.Equal(t, takedown, link.Takedown)
+
+	link, err = loaded.GetLongURL(ctx, "", sub)
+	require.NoError(t, err)
+	assert.False(t, link.Disabled())
+}
+
+func TestMapStorage_Reports(t *testing.T) {
+	path := t.TempDir() + "/storage.json"
+
+	s, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now()
+	since := now.Add(-time.Hour)
+
+	report := func(ID, reporter string, createdAt time.Time) models.Report {
+		return models.Report{ID: ID, ShortURL: "qxDvSD", Reason: "Phishing", Reporter: reporter, Status: models.ReportPending, CreatedAt: createdAt}
+	}
+
+	n, err := s.CreateReport(ctx, report("1", "10.0.0.1", now.Add(-2*time.Hour)), since)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n, "reports older than the window are not counted")
+
+	n, err = s.CreateReport(ctx, report("2", "10.0.0.2", now), since)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	n, err = s.CreateReport(ctx, report("3", "10.0.0.2", now), since)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "a reporter is counted once")
+
+	n, err = s.CreateReport(ctx, report("4", "10.0.0.3", now), since)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	pending, err := s.GetReports(ctx, models.ReportPending)
+	require.NoError(t, err)
+	require.Len(t, pending, 3)
+	assert.Equal(t, "1", pending[0].ID)
+
+	n, err = s.ResolveReports(ctx, "", "qxDvSD", models.ReportDismissed)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	err = s.OffloadStorage(ctx, path)
+	require.NoError(t, err)
+
+	loaded, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	dismissed, err := loaded.GetReport(ctx, "4")
+	require.NoError(t, err)
+	assert.Equal(t, models.ReportDismissed, dismissed.Status)
+
+	_, err = loaded.GetReport(ctx, "3")
+	assert.ErrorIs(t, err, errs.ErrReportNotFound)
+}
+
+func TestMapStorage_Audit(t *testing.T) {
+	path := t.TempDir() + "/storage.json"
+
+	s, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+
+	err = s.AppendAudit(ctx,
+		models.AuditEvent{ID: "1", Time: now.Add(-2 * time.Hour), Action: models.AuditLinkCreate, ActorID: "user1", ShortURL: "qxDvSD"},
+		models.AuditEvent{ID: "2", Time: now.Add(-time.Hour), Action: models.AuditLinkCreate, ActorID: "user2", ShortURL: "Ab12Cd"},
+	)
+	require.NoError(t, err)
+
+	err = s.AppendAudit(ctx, models.AuditEvent{ID: "3", Time: now, Action: models.AuditLinkDelete, ActorID: "user1", ShortURL: "qxDvSD",
+		Before: map[string]any{"original_url": "https://www.youtube.com"}})
+	require.NoError(t, err)
+
+	ids := func(events []models.AuditEvent) []string {
+		var result []string
+		for _, e := range events {
+			result = append(result, e.ID)
+		}
+		return result
+	}
+
+	events, err := s.QueryAudit(ctx, models.AuditQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "2", "1"}, ids(events), "newest first")
+
+	events, err = s.QueryAudit(ctx, models.AuditQuery{ActorID: "user1", ShortURL: "qxDvSD"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"3", "1"}, ids(events))
+
+	events, err = s.QueryAudit(ctx, models.AuditQuery{From: now.Add(-time.Hour), To: now})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, ids(events), "from is inclusive, to is exclusive")
+
+	events, err = s.QueryAudit(ctx, models.AuditQuery{Action: models.AuditLinkCreate, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2"}, ids(events))
+
+	// A write cut short by a crash leaves a partial line behind.
+	file, err := os.OpenFile(path+".audit.jsonl", os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"id":"4","act` + "\n")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	s, err = newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	events, err = s.QueryAudit(ctx, models.AuditQuery{})
+	require.NoError(t, err)
+	require.Equal(t, []string{"3", "2", "1"}, ids(events))
+	assert.Equal(t, "https://www.youtube.com", events[0].Before["original_url"])
+	assert.True(t, now.Equal(events[0].Time))
+}
+
+func TestMapStorage_Webhooks(t *testing.T) {
+	path := t.TempDir() + "/storage.json"
+
+	s, err := newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Millisecond)
+
+	clicked := models.Webhook{ID: "1", UserID: "user1", URL: "https://crm.example/hooks", Secret: "whsec_1",
+		Events: []string{models.WebhookLinkClicked}, CreatedAt: now}
+	all := models.Webhook{ID: "2", UserID: "user1", URL: "https://crm.example/all", Secret: "whsec_2",
+		Events: models.WebhookEvents, CreatedAt: now.Add(time.Second)}
+	other := models.Webhook{ID: "3", UserID: "user2", URL: "https://other.example/", Secret: "whsec_3",
+		Events: models.WebhookEvents, CreatedAt: now}
+
+	for _, w := range []models.Webhook{all, clicked, other} {
+		require.NoError(t, s.CreateWebhook(ctx, w))
+	}
+
+	webhooks, err := s.GetWebhooks(ctx, "user1")
+	require.NoError(t, err)
+	require.Len(t, webhooks, 2)
+	assert.Equal(t, "1", webhooks[0].ID, "oldest first")
+
+	n, err := s.EnqueueDeliveries(ctx, "user1", models.WebhookLinkCreated, []byte(`{"event":"link.created"}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n, "only subscribed webhooks get the event")
+
+	n, err = s.EnqueueDeliveries(ctx, "user1", models.WebhookLinkClicked, []byte(`{"event":"link.clicked"}`), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	claimed, err := s.ClaimDeliveries(ctx, now, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1, "deliveries that aren't due are left")
+	assert.Equal(t, all.URL, claimed[0].URL)
+	assert.Equal(t, all.Secret, claimed[0].Secret)
+
+	claimedAgain, err := s.ClaimDeliveries(ctx, now, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, claimedAgain, "claimed deliveries are leased")
+
+	d := claimed[0]
+	d.Status = models.DeliveryDead
+	d.Attempts = 8
+	d.LastError = "receiver answered 500 Internal Server Error"
+	d.ResponseStatus = 500
+	require.NoError(t, s.UpdateDelivery(ctx, d))
+
+	dead, err := s.GetDeliveries(ctx, "user1", "2", models.DeliveryDead, 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 8, dead[0].Attempts)
+
+	_, err = s.GetDeliveries(ctx, "user2", "2", "", 10)
+	assert.ErrorIs(t, err, errs.ErrWebhookNotFound)
+
+	err = s.RedeliverDelivery(ctx, "user2", "2", d.ID, now)
+	assert.ErrorIs(t, err, errs.ErrDeliveryNotFound, "deliveries of other users can't be redelivered")
+
+	err = s.RedeliverDelivery(ctx, "user1", "2", d.ID, now.Add(2*time.Minute))
+	require.NoError(t, err)
+
+	require.NoError(t, s.OffloadStorage(ctx, path))
+
+	s, err = newMapStorage(&config.Config{Filepath: path}, zap.NewNop())
+	require.NoError(t, err)
+
+	claimed, err = s.ClaimDeliveries(ctx, now.Add(2*time.Minute), 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, claimed, 3, "the outbox survives restarts")
+	assert.Equal(t, d.ID, claimed[len(claimed)-1].ID, "redelivered last")
+	assert.Zero(t, claimed[len(claimed)-1].Attempts)
+	assert.JSONEq(t, `{"event":"link.created"}`, string(claimed[len(claimed)-1].Payload))
+
+	require.NoError(t, s.DeleteWebhook(ctx, "user1", "2"))
+	assert.ErrorIs(t, s.DeleteWebhook(ctx, "user1", "2"), errs.ErrWebhookNotFound)
+
+	deliveries, err := s.GetDeliveries(ctx, "user1", "1", "", 10)
+	require.NoError(t, err)
+	assert.Len(t, deliveries, 1, "deliveries of deleted webhooks are dropped")
+}
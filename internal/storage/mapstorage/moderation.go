@@ -0,0 +1,90 @@
+package mapstorage
+
+import (
+	"context"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/models"
+)
+
+// FindLinks returns links of every user matching q, newest first.
+func (s *MapStorage) FindLinks(ctx context.Context, q models.LinkQuery) ([]models.Link, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var result []models.Link
+	for key := range s.FullURLStorage {
+		if !s.matches(key, q) {
+			continue
+		}
+
+		link, _ := s.link(key)
+		result = append(result, link)
+	}
+
+	slices.SortFunc(result, func(a, b models.Link) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+
+	return result, nil
+}
+
+// DisableLinks sets the takedown of every link matching q, the zero Takedown enables them again.
+// Returns the number of links changed.
+func (s *MapStorage) DisableLinks(ctx context.Context, q models.LinkQuery, t models.Takedown) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var n int
+	for key := range s.FullURLStorage {
+		if !s.matches(key, q) {
+			continue
+		}
+
+		if t == (models.Takedown{}) {
+			delete(s.Takedowns, key)
+		} else {
+			s.Takedowns[key] = t
+		}
+
+		n++
+	}
+
+	return n, nil
+}
+
+// matches reports whether the link stored under key matches q. Must be called under lock.
+func (s *MapStorage) matches(key string, q models.LinkQuery) bool {
+	domain, ID := splitKey(key)
+	fullURL := s.FullURLStorage[key]
+	meta := s.LinkMeta[key]
+
+	switch {
+	case q.ShortURL != "" && q.ShortURL != ID:
+		return false
+	case len(q.Domains) > 0 && !slices.Contains(q.Domains, domain):
+		return false
+	case q.Destination != "" && q.Destination != fullURL && q.Destination != meta.CanonicalURL:
+		return false
+	case q.UserID != "" && q.UserID != meta.UserID:
+		return false
+	case q.Host != "" && !inHost(fullURL, q.Host):
+		return false
+	default:
+		return true
+	}
+}
+
+// inHost reports whether rawURL points to host or one of its subdomains.
+func inHost(rawURL, host string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	name := strings.ToLower(u.Hostname())
+
+	return name == host || strings.HasSuffix(name, "."+host)
+}
@@ -0,0 +1,164 @@
+package mapstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"go.uber.org/zap"
+)
+
+// CreateReport queues r, unless its reporter already has a pending report about the link. Returns the number
+// of distinct reporters with pending reports about the link filed since since.
+func (s *MapStorage) CreateReport(ctx context.Context, r models.Report, since time.Time) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	reporters := make(map[string]struct{})
+	queued := false
+	for _, report := range s.Reports {
+		if !pendingAbout(report, r.Domain, r.ShortURL) {
+			continue
+		}
+
+		if report.Reporter == r.Reporter {
+			queued = true
+		}
+
+		if !report.CreatedAt.Before(since) {
+			reporters[report.Reporter] = struct{}{}
+		}
+	}
+
+	if !queued {
+		r.Status = models.ReportPending
+		s.Reports[r.ID] = r
+
+		if !r.CreatedAt.Before(since) {
+			reporters[r.Reporter] = struct{}{}
+		}
+	}
+
+	return len(reporters), nil
+}
+
+// GetReports returns reports with status, oldest first.
+func (s *MapStorage) GetReports(ctx context.Context, status models.ReportStatus) ([]models.Report, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var result []models.Report
+	for _, report := range s.Reports {
+		if report.Status == status {
+			result = append(result, report)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b models.Report) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return result, nil
+}
+
+func (s *MapStorage) GetReport(ctx context.Context, ID string) (models.Report, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	report, ok := s.Reports[ID]
+	if !ok {
+		return models.Report{}, errs.ErrReportNotFound
+	}
+
+	return report, nil
+}
+
+// ResolveReports sets status of every pending report about the link with ID on domain.
+// Returns the number of reports changed.
+func (s *MapStorage) ResolveReports(ctx context.Context, domain, ID string, status models.ReportStatus) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var n int
+	for reportID, report := range s.Reports {
+		if !pendingAbout(report, domain, ID) {
+			continue
+		}
+
+		report.Status = status
+		s.Reports[reportID] = report
+		n++
+	}
+
+	return n, nil
+}
+
+// pendingAbout reports whether r is a pending report about the link with ID on domain.
+func pendingAbout(r models.Report, domain, ID string) bool {
+	return r.Status == models.ReportPending && r.Domain == domain && r.ShortURL == ID
+}
+
+func reportsPath(filepath string) string {
+	return filepath + ".reports"
+}
+
+func (s *MapStorage) loadReports(filepath string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		s.logger.Error("mapstorage:loadReports Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	var data []models.Report
+	err = json.NewDecoder(file).Decode(&data)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		s.logger.Error("mapstorage:loadReports Error decoding file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	for _, report := range data {
+		s.Reports[report.ID] = report
+	}
+
+	return nil
+}
+
+func (s *MapStorage) offloadReports(filepath string) error {
+	file, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		s.logger.Error("mapstorage:offloadReports Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	data := make([]models.Report, 0, len(s.Reports))
+	for _, report := range s.Reports {
+		data = append(data, report)
+	}
+
+	err = json.NewEncoder(file).Encode(&data)
+	if err != nil {
+		s.logger.Error("mapstorage:offloadReports Error encoding data", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
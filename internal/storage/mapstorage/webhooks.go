@@ -0,0 +1,272 @@
+package mapstorage
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// webhooksFile is the layout of the webhooks file: subscriptions and the outbox of their deliveries.
+type webhooksFile struct {
+	Webhooks   []models.Webhook  `json:"webhooks"`
+	Deliveries []models.Delivery `json:"deliveries"`
+}
+
+func (s *MapStorage) CreateWebhook(ctx context.Context, w models.Webhook) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	s.Webhooks[w.ID] = w
+
+	return nil
+}
+
+// GetWebhooks returns webhooks of userID, oldest first.
+func (s *MapStorage) GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var result []models.Webhook
+	for _, w := range s.Webhooks {
+		if w.UserID == userID {
+			result = append(result, w)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b models.Webhook) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	return result, nil
+}
+
+// DeleteWebhook deletes the webhook with ID of userID along with its deliveries.
+func (s *MapStorage) DeleteWebhook(ctx context.Context, userID, ID string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	w, ok := s.Webhooks[ID]
+	if !ok || w.UserID != userID {
+		return errs.ErrWebhookNotFound
+	}
+
+	delete(s.Webhooks, ID)
+
+	for deliveryID, d := range s.Deliveries {
+		if d.WebhookID == ID {
+			delete(s.Deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries queues a delivery of payload, due at, to every webhook of userID subscribed to event.
+func (s *MapStorage) EnqueueDeliveries(ctx context.Context, userID, event string, payload []byte, at time.Time) (int, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	n := 0
+	for _, w := range s.Webhooks {
+		if w.UserID != userID || !slices.Contains(w.Events, event) {
+			continue
+		}
+
+		d := models.Delivery{
+			ID:            uuid.NewString(),
+			WebhookID:     w.ID,
+			UserID:        userID,
+			Event:         event,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: at,
+			CreatedAt:     at,
+		}
+		s.Deliveries[d.ID] = d
+		n++
+	}
+
+	return n, nil
+}
+
+// ClaimDeliveries returns up to limit pending deliveries due by now, earliest first, and postpones them until now+lease.
+func (s *MapStorage) ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.Delivery, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var due []models.Delivery
+	for _, d := range s.Deliveries {
+		if d.Status == models.DeliveryPending && !d.NextAttemptAt.After(now) {
+			due = append(due, d)
+		}
+	}
+
+	slices.SortFunc(due, func(a, b models.Delivery) int {
+		return a.NextAttemptAt.Compare(b.NextAttemptAt)
+	})
+
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	for i := range due {
+		due[i].NextAttemptAt = now.Add(lease)
+		s.Deliveries[due[i].ID] = due[i]
+
+		w := s.Webhooks[due[i].WebhookID]
+		due[i].URL, due[i].Secret = w.URL, w.Secret
+	}
+
+	return due, nil
+}
+
+// UpdateDelivery saves the outcome of an attempt to send d. Deliveries of deleted webhooks are ignored.
+func (s *MapStorage) UpdateDelivery(ctx context.Context, d models.Delivery) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	stored, ok := s.Deliveries[d.ID]
+	if !ok {
+		return nil
+	}
+
+	stored.Status = d.Status
+	stored.Attempts = d.Attempts
+	stored.NextAttemptAt = d.NextAttemptAt
+	stored.LastError = d.LastError
+	stored.ResponseStatus = d.ResponseStatus
+	stored.DeliveredAt = d.DeliveredAt
+	s.Deliveries[d.ID] = stored
+
+	return nil
+}
+
+// GetDeliveries returns up to limit deliveries of the webhook with webhookID of userID with status, newest first.
+func (s *MapStorage) GetDeliveries(ctx context.Context, userID, webhookID string, status models.DeliveryStatus, limit int) ([]models.Delivery, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	w, ok := s.Webhooks[webhookID]
+	if !ok || w.UserID != userID {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	var result []models.Delivery
+	for _, d := range s.Deliveries {
+		if d.WebhookID == webhookID && (status == "" || d.Status == status) {
+			result = append(result, d)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b models.Delivery) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+
+	if len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result, nil
+}
+
+// RedeliverDelivery queues the delivery with ID of the webhook with webhookID of userID again, due at.
+func (s *MapStorage) RedeliverDelivery(ctx context.Context, userID, webhookID, ID string, at time.Time) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	d, ok := s.Deliveries[ID]
+	if !ok || d.WebhookID != webhookID || s.Webhooks[webhookID].UserID != userID {
+		return errs.ErrDeliveryNotFound
+	}
+
+	d.Status = models.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = at
+	d.LastError = ""
+	d.ResponseStatus = 0
+	d.DeliveredAt = time.Time{}
+	s.Deliveries[ID] = d
+
+	return nil
+}
+
+func webhooksPath(filepath string) string {
+	return filepath + ".webhooks"
+}
+
+func (s *MapStorage) loadWebhooks(filepath string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		s.logger.Error("mapstorage:loadWebhooks Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	var data webhooksFile
+	err = json.NewDecoder(file).Decode(&data)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		s.logger.Error("mapstorage:loadWebhooks Error decoding file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	for _, w := range data.Webhooks {
+		s.Webhooks[w.ID] = w
+	}
+
+	for _, d := range data.Deliveries {
+		s.Deliveries[d.ID] = d
+	}
+
+	return nil
+}
+
+func (s *MapStorage) offloadWebhooks(filepath string) error {
+	file, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		s.logger.Error("mapstorage:offloadWebhooks Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	data := webhooksFile{
+		Webhooks:   make([]models.Webhook, 0, len(s.Webhooks)),
+		Deliveries: make([]models.Delivery, 0, len(s.Deliveries)),
+	}
+
+	for _, w := range s.Webhooks {
+		data.Webhooks = append(data.Webhooks, w)
+	}
+
+	for _, d := range s.Deliveries {
+		data.Deliveries = append(data.Deliveries, d)
+	}
+
+	err = json.NewEncoder(file).Encode(&data)
+	if err != nil {
+		s.logger.Error("mapstorage:offloadWebhooks Error encoding data", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package mapstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// CreateWorkspace creates a workspace and makes userID its owner. Returns ID of the workspace.
+func (s *MapStorage) CreateWorkspace(ctx context.Context, userID, name string) (string, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	ID := uuid.NewString()
+
+	s.WorkspaceStorage[ID] = &models.Workspace{
+		ID:      ID,
+		Name:    name,
+		OwnerID: userID,
+		Members: map[string]models.Role{userID: models.RoleOwner},
+	}
+
+	return ID, nil
+}
+
+// AddWorkspaceMember adds userID to a workspace or changes the role of an existing member.
+func (s *MapStorage) AddWorkspaceMember(ctx context.Context, workspaceID, userID string, role models.Role) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	workspace, ok := s.WorkspaceStorage[workspaceID]
+	if !ok {
+		return errs.ErrWorkspaceNotFound
+	}
+
+	if role != models.RoleOwner && lastOwner(workspace, userID) {
+		return errs.ErrLastOwner
+	}
+
+	workspace.Members[userID] = role
+
+	return nil
+}
+
+// RemoveWorkspaceMember removes userID from a workspace.
+func (s *MapStorage) RemoveWorkspaceMember(ctx context.Context, workspaceID, userID string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	workspace, ok := s.WorkspaceStorage[workspaceID]
+	if !ok {
+		return errs.ErrWorkspaceNotFound
+	}
+
+	if _, ok = workspace.Members[userID]; !ok {
+		return errs.ErrWorkspaceNotFound
+	}
+
+	if lastOwner(workspace, userID) {
+		return errs.ErrLastOwner
+	}
+
+	delete(workspace.Members, userID)
+
+	return nil
+}
+
+// lastOwner reports whether userID is the only owner of workspace.
+func lastOwner(workspace *models.Workspace, userID string) bool {
+	if workspace.Members[userID] != models.RoleOwner {
+		return false
+	}
+
+	for ID, role := range workspace.Members {
+		if ID != userID && role == models.RoleOwner {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetWorkspaceRole returns the role userID has in a workspace.
+func (s *MapStorage) GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (models.Role, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	workspace, ok := s.WorkspaceStorage[workspaceID]
+	if !ok {
+		return "", errs.ErrForbidden
+	}
+
+	role, ok := workspace.Members[userID]
+	if !ok {
+		return "", errs.ErrForbidden
+	}
+
+	return role, nil
+}
+
+// GetUserWorkspaces returns all workspaces userID is a member of.
+func (s *MapStorage) GetUserWorkspaces(ctx context.Context, userID string) ([]dto.Workspace, error) {
+	s.m.RLock()
+	defer s.m.RUnlock()
+
+	var result []dto.Workspace
+	for _, workspace := range s.WorkspaceStorage {
+		if role, ok := workspace.Members[userID]; ok {
+			result = append(result, dto.Workspace{
+				ID:   workspace.ID,
+				Name: workspace.Name,
+				Role: string(role),
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// userWorkspaces returns roles of userID keyed by workspace ID. Must be called under lock.
+func (s *MapStorage) userWorkspaces(userID string) map[string]models.Role {
+	result := make(map[string]models.Role)
+	for ID, workspace := range s.WorkspaceStorage {
+		if role, ok := workspace.Members[userID]; ok {
+			result[ID] = role
+		}
+	}
+
+	return result
+}
+
+// canModify reports whether userID created the link stored under key or may edit it through a workspace.
+// Must be called under lock.
+func (s *MapStorage) canModify(userID, key string) bool {
+	if _, ok := s.UserLinkStorage[userID][key]; ok {
+		return true
+	}
+
+	workspace, ok := s.WorkspaceStorage[s.LinkOptions[key].WorkspaceID]
+	if !ok {
+		return false
+	}
+
+	return workspace.Members[userID].CanEdit()
+}
+
+func workspacesPath(filepath string) string {
+	return filepath + ".workspaces"
+}
+
+func (s *MapStorage) loadWorkspaces(filepath string) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	file, err := os.Open(filepath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+
+		s.logger.Error("mapstorage:loadWorkspaces Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	var data []models.Workspace
+	err = json.NewDecoder(file).Decode(&data)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		s.logger.Error("mapstorage:loadWorkspaces Error decoding file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	for _, workspace := range data {
+		if workspace.Members == nil {
+			workspace.Members = make(map[string]models.Role)
+		}
+
+		s.WorkspaceStorage[workspace.ID] = &workspace
+	}
+
+	return nil
+}
+
+func (s *MapStorage) offloadWorkspaces(filepath string) error {
+	file, err := os.OpenFile(filepath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		s.logger.Error("mapstorage:offloadWorkspaces Error opening file", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer file.Close()
+
+	data := make([]models.Workspace, 0, len(s.WorkspaceStorage))
+	for _, workspace := range s.WorkspaceStorage {
+		data = append(data, *workspace)
+	}
+
+	err = json.NewEncoder(file).Encode(&data)
+	if err != nil {
+		s.logger.Error("mapstorage:offloadWorkspaces Error encoding data", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
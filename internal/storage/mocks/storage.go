@@ -12,8 +12,10 @@ package mockstorage
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	dto "github.com/MukizuL/shortener/internal/dto"
+	models "github.com/MukizuL/shortener/internal/models"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,63 +43,182 @@ func (m *MockRepo) EXPECT() *MockRepoMockRecorder {
 	return m.recorder
 }
 
+// AddWorkspaceMember mocks base method.
+func (m *MockRepo) AddWorkspaceMember(ctx context.Context, workspaceID, userID string, role models.Role) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWorkspaceMember", ctx, workspaceID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWorkspaceMember indicates an expected call of AddWorkspaceMember.
+func (mr *MockRepoMockRecorder) AddWorkspaceMember(ctx, workspaceID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWorkspaceMember", reflect.TypeOf((*MockRepo)(nil).AddWorkspaceMember), ctx, workspaceID, userID, role)
+}
+
 // BatchCreateShortURL mocks base method.
-func (m *MockRepo) BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest) ([]dto.BatchResponse, error) {
+func (m *MockRepo) BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest, opts models.URLOptions) ([]dto.BatchResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BatchCreateShortURL", ctx, userID, urlBase, data)
+	ret := m.ctrl.Call(m, "BatchCreateShortURL", ctx, userID, urlBase, data, opts)
 	ret0, _ := ret[0].([]dto.BatchResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // BatchCreateShortURL indicates an expected call of BatchCreateShortURL.
-func (mr *MockRepoMockRecorder) BatchCreateShortURL(ctx, userID, urlBase, data any) *gomock.Call {
+func (mr *MockRepoMockRecorder) BatchCreateShortURL(ctx, userID, urlBase, data, opts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreateShortURL", reflect.TypeOf((*MockRepo)(nil).BatchCreateShortURL), ctx, userID, urlBase, data, opts)
+}
+
+// ConsumeClick mocks base method.
+func (m *MockRepo) ConsumeClick(ctx context.Context, domain, ID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeClick", ctx, domain, ID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeClick indicates an expected call of ConsumeClick.
+func (mr *MockRepoMockRecorder) ConsumeClick(ctx, domain, ID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeClick", reflect.TypeOf((*MockRepo)(nil).ConsumeClick), ctx, domain, ID)
+}
+
+// CreateReport mocks base method.
+func (m *MockRepo) CreateReport(ctx context.Context, r models.Report, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReport", ctx, r, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReport indicates an expected call of CreateReport.
+func (mr *MockRepoMockRecorder) CreateReport(ctx, r, since any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchCreateShortURL", reflect.TypeOf((*MockRepo)(nil).BatchCreateShortURL), ctx, userID, urlBase, data)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockRepo)(nil).CreateReport), ctx, r, since)
 }
 
 // CreateShortURL mocks base method.
-func (m *MockRepo) CreateShortURL(ctx context.Context, userID, urlBase, fullURL string) (string, error) {
+func (m *MockRepo) CreateShortURL(ctx context.Context, userID, urlBase, fullURL, canonicalURL string, opts models.URLOptions) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateShortURL", ctx, userID, urlBase, fullURL)
+	ret := m.ctrl.Call(m, "CreateShortURL", ctx, userID, urlBase, fullURL, canonicalURL, opts)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateShortURL indicates an expected call of CreateShortURL.
-func (mr *MockRepoMockRecorder) CreateShortURL(ctx, userID, urlBase, fullURL any) *gomock.Call {
+func (mr *MockRepoMockRecorder) CreateShortURL(ctx, userID, urlBase, fullURL, canonicalURL, opts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShortURL", reflect.TypeOf((*MockRepo)(nil).CreateShortURL), ctx, userID, urlBase, fullURL)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateShortURL", reflect.TypeOf((*MockRepo)(nil).CreateShortURL), ctx, userID, urlBase, fullURL, canonicalURL, opts)
+}
+
+// CreateWorkspace mocks base method.
+func (m *MockRepo) CreateWorkspace(ctx context.Context, userID, name string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWorkspace", ctx, userID, name)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWorkspace indicates an expected call of CreateWorkspace.
+func (mr *MockRepoMockRecorder) CreateWorkspace(ctx, userID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorkspace", reflect.TypeOf((*MockRepo)(nil).CreateWorkspace), ctx, userID, name)
 }
 
 // DeleteURLs mocks base method.
-func (m *MockRepo) DeleteURLs(ctx context.Context, userID string, urls []string) error {
+func (m *MockRepo) DeleteURLs(ctx context.Context, userID, domain string, urls []string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteURLs", ctx, userID, urls)
+	ret := m.ctrl.Call(m, "DeleteURLs", ctx, userID, domain, urls)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteURLs indicates an expected call of DeleteURLs.
-func (mr *MockRepoMockRecorder) DeleteURLs(ctx, userID, urls any) *gomock.Call {
+func (mr *MockRepoMockRecorder) DeleteURLs(ctx, userID, domain, urls any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLs", reflect.TypeOf((*MockRepo)(nil).DeleteURLs), ctx, userID, urls)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteURLs", reflect.TypeOf((*MockRepo)(nil).DeleteURLs), ctx, userID, domain, urls)
+}
+
+// DisableLinks mocks base method.
+func (m *MockRepo) DisableLinks(ctx context.Context, q models.LinkQuery, t models.Takedown) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableLinks", ctx, q, t)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableLinks indicates an expected call of DisableLinks.
+func (mr *MockRepoMockRecorder) DisableLinks(ctx, q, t any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableLinks", reflect.TypeOf((*MockRepo)(nil).DisableLinks), ctx, q, t)
+}
+
+// FindLinks mocks base method.
+func (m *MockRepo) FindLinks(ctx context.Context, q models.LinkQuery) ([]models.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindLinks", ctx, q)
+	ret0, _ := ret[0].([]models.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindLinks indicates an expected call of FindLinks.
+func (mr *MockRepoMockRecorder) FindLinks(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindLinks", reflect.TypeOf((*MockRepo)(nil).FindLinks), ctx, q)
 }
 
 // GetLongURL mocks base method.
-func (m *MockRepo) GetLongURL(ctx context.Context, ID string) (string, error) {
+func (m *MockRepo) GetLongURL(ctx context.Context, domain, ID string) (models.Link, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLongURL", ctx, ID)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "GetLongURL", ctx, domain, ID)
+	ret0, _ := ret[0].(models.Link)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetLongURL indicates an expected call of GetLongURL.
-func (mr *MockRepoMockRecorder) GetLongURL(ctx, ID any) *gomock.Call {
+func (mr *MockRepoMockRecorder) GetLongURL(ctx, domain, ID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongURL", reflect.TypeOf((*MockRepo)(nil).GetLongURL), ctx, ID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLongURL", reflect.TypeOf((*MockRepo)(nil).GetLongURL), ctx, domain, ID)
+}
+
+// GetReport mocks base method.
+func (m *MockRepo) GetReport(ctx context.Context, ID string) (models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReport", ctx, ID)
+	ret0, _ := ret[0].(models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReport indicates an expected call of GetReport.
+func (mr *MockRepoMockRecorder) GetReport(ctx, ID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReport", reflect.TypeOf((*MockRepo)(nil).GetReport), ctx, ID)
+}
+
+// GetReports mocks base method.
+func (m *MockRepo) GetReports(ctx context.Context, status models.ReportStatus) ([]models.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReports", ctx, status)
+	ret0, _ := ret[0].([]models.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReports indicates an expected call of GetReports.
+func (mr *MockRepoMockRecorder) GetReports(ctx, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReports", reflect.TypeOf((*MockRepo)(nil).GetReports), ctx, status)
 }
 
 // GetStats mocks base method.
@@ -117,18 +238,48 @@ func (mr *MockRepoMockRecorder) GetStats(ctx any) *gomock.Call {
 }
 
 // GetUserURLs mocks base method.
-func (m *MockRepo) GetUserURLs(ctx context.Context, userID string) ([]dto.URLPair, error) {
+func (m *MockRepo) GetUserURLs(ctx context.Context, userID string, domains []string) ([]dto.URLPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserURLs", ctx, userID)
+	ret := m.ctrl.Call(m, "GetUserURLs", ctx, userID, domains)
 	ret0, _ := ret[0].([]dto.URLPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetUserURLs indicates an expected call of GetUserURLs.
-func (mr *MockRepoMockRecorder) GetUserURLs(ctx, userID any) *gomock.Call {
+func (mr *MockRepoMockRecorder) GetUserURLs(ctx, userID, domains any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserURLs", reflect.TypeOf((*MockRepo)(nil).GetUserURLs), ctx, userID, domains)
+}
+
+// GetUserWorkspaces mocks base method.
+func (m *MockRepo) GetUserWorkspaces(ctx context.Context, userID string) ([]dto.Workspace, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWorkspaces", ctx, userID)
+	ret0, _ := ret[0].([]dto.Workspace)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserWorkspaces indicates an expected call of GetUserWorkspaces.
+func (mr *MockRepoMockRecorder) GetUserWorkspaces(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWorkspaces", reflect.TypeOf((*MockRepo)(nil).GetUserWorkspaces), ctx, userID)
+}
+
+// GetWorkspaceRole mocks base method.
+func (m *MockRepo) GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (models.Role, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWorkspaceRole", ctx, workspaceID, userID)
+	ret0, _ := ret[0].(models.Role)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWorkspaceRole indicates an expected call of GetWorkspaceRole.
+func (mr *MockRepoMockRecorder) GetWorkspaceRole(ctx, workspaceID, userID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserURLs", reflect.TypeOf((*MockRepo)(nil).GetUserURLs), ctx, userID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWorkspaceRole", reflect.TypeOf((*MockRepo)(nil).GetWorkspaceRole), ctx, workspaceID, userID)
 }
 
 // OffloadStorage mocks base method.
@@ -158,3 +309,244 @@ func (mr *MockRepoMockRecorder) Ping(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockRepo)(nil).Ping), ctx)
 }
+
+// RemoveWorkspaceMember mocks base method.
+func (m *MockRepo) RemoveWorkspaceMember(ctx context.Context, workspaceID, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveWorkspaceMember", ctx, workspaceID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveWorkspaceMember indicates an expected call of RemoveWorkspaceMember.
+func (mr *MockRepoMockRecorder) RemoveWorkspaceMember(ctx, workspaceID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWorkspaceMember", reflect.TypeOf((*MockRepo)(nil).RemoveWorkspaceMember), ctx, workspaceID, userID)
+}
+
+// ResolveReports mocks base method.
+func (m *MockRepo) ResolveReports(ctx context.Context, domain, ID string, status models.ReportStatus) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveReports", ctx, domain, ID, status)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveReports indicates an expected call of ResolveReports.
+func (mr *MockRepoMockRecorder) ResolveReports(ctx, domain, ID, status any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveReports", reflect.TypeOf((*MockRepo)(nil).ResolveReports), ctx, domain, ID, status)
+}
+
+// WalkUserURLs mocks base method.
+func (m *MockRepo) WalkUserURLs(ctx context.Context, userID string, domains []string, fn func(dto.URLPair) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WalkUserURLs", ctx, userID, domains, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WalkUserURLs indicates an expected call of WalkUserURLs.
+func (mr *MockRepoMockRecorder) WalkUserURLs(ctx, userID, domains, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WalkUserURLs", reflect.TypeOf((*MockRepo)(nil).WalkUserURLs), ctx, userID, domains, fn)
+}
+
+// MockAuditLog is a mock of AuditLog interface.
+type MockAuditLog struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditLogMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditLogMockRecorder is the mock recorder for MockAuditLog.
+type MockAuditLogMockRecorder struct {
+	mock *MockAuditLog
+}
+
+// NewMockAuditLog creates a new mock instance.
+func NewMockAuditLog(ctrl *gomock.Controller) *MockAuditLog {
+	mock := &MockAuditLog{ctrl: ctrl}
+	mock.recorder = &MockAuditLogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditLog) EXPECT() *MockAuditLogMockRecorder {
+	return m.recorder
+}
+
+// AppendAudit mocks base method.
+func (m *MockAuditLog) AppendAudit(ctx context.Context, events ...models.AuditEvent) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AppendAudit", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AppendAudit indicates an expected call of AppendAudit.
+func (mr *MockAuditLogMockRecorder) AppendAudit(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendAudit", reflect.TypeOf((*MockAuditLog)(nil).AppendAudit), varargs...)
+}
+
+// QueryAudit mocks base method.
+func (m *MockAuditLog) QueryAudit(ctx context.Context, q models.AuditQuery) ([]models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryAudit", ctx, q)
+	ret0, _ := ret[0].([]models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryAudit indicates an expected call of QueryAudit.
+func (mr *MockAuditLogMockRecorder) QueryAudit(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryAudit", reflect.TypeOf((*MockAuditLog)(nil).QueryAudit), ctx, q)
+}
+
+// MockWebhooks is a mock of Webhooks interface.
+type MockWebhooks struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhooksMockRecorder
+	isgomock struct{}
+}
+
+// MockWebhooksMockRecorder is the mock recorder for MockWebhooks.
+type MockWebhooksMockRecorder struct {
+	mock *MockWebhooks
+}
+
+// NewMockWebhooks creates a new mock instance.
+func NewMockWebhooks(ctrl *gomock.Controller) *MockWebhooks {
+	mock := &MockWebhooks{ctrl: ctrl}
+	mock.recorder = &MockWebhooksMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWebhooks) EXPECT() *MockWebhooksMockRecorder {
+	return m.recorder
+}
+
+// ClaimDeliveries mocks base method.
+func (m *MockWebhooks) ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", ctx, now, limit, lease)
+	ret0, _ := ret[0].([]models.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries.
+func (mr *MockWebhooksMockRecorder) ClaimDeliveries(ctx, now, limit, lease any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockWebhooks)(nil).ClaimDeliveries), ctx, now, limit, lease)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockWebhooks) CreateWebhook(ctx context.Context, w models.Webhook) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", ctx, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockWebhooksMockRecorder) CreateWebhook(ctx, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockWebhooks)(nil).CreateWebhook), ctx, w)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockWebhooks) DeleteWebhook(ctx context.Context, userID, ID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", ctx, userID, ID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockWebhooksMockRecorder) DeleteWebhook(ctx, userID, ID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhooks)(nil).DeleteWebhook), ctx, userID, ID)
+}
+
+// EnqueueDeliveries mocks base method.
+func (m *MockWebhooks) EnqueueDeliveries(ctx context.Context, userID, event string, payload []byte, at time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnqueueDeliveries", ctx, userID, event, payload, at)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnqueueDeliveries indicates an expected call of EnqueueDeliveries.
+func (mr *MockWebhooksMockRecorder) EnqueueDeliveries(ctx, userID, event, payload, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueDeliveries", reflect.TypeOf((*MockWebhooks)(nil).EnqueueDeliveries), ctx, userID, event, payload, at)
+}
+
+// GetDeliveries mocks base method.
+func (m *MockWebhooks) GetDeliveries(ctx context.Context, userID, webhookID string, status models.DeliveryStatus, limit int) ([]models.Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveries", ctx, userID, webhookID, status, limit)
+	ret0, _ := ret[0].([]models.Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveries indicates an expected call of GetDeliveries.
+func (mr *MockWebhooksMockRecorder) GetDeliveries(ctx, userID, webhookID, status, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveries", reflect.TypeOf((*MockWebhooks)(nil).GetDeliveries), ctx, userID, webhookID, status, limit)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockWebhooks) GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", ctx, userID)
+	ret0, _ := ret[0].([]models.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockWebhooksMockRecorder) GetWebhooks(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockWebhooks)(nil).GetWebhooks), ctx, userID)
+}
+
+// RedeliverDelivery mocks base method.
+func (m *MockWebhooks) RedeliverDelivery(ctx context.Context, userID, webhookID, ID string, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeliverDelivery", ctx, userID, webhookID, ID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeliverDelivery indicates an expected call of RedeliverDelivery.
+func (mr *MockWebhooksMockRecorder) RedeliverDelivery(ctx, userID, webhookID, ID, at any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverDelivery", reflect.TypeOf((*MockWebhooks)(nil).RedeliverDelivery), ctx, userID, webhookID, ID, at)
+}
+
+// UpdateDelivery mocks base method.
+func (m *MockWebhooks) UpdateDelivery(ctx context.Context, d models.Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", ctx, d)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery.
+func (mr *MockWebhooksMockRecorder) UpdateDelivery(ctx, d any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockWebhooks)(nil).UpdateDelivery), ctx, d)
+}
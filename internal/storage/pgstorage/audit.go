@@ -0,0 +1,118 @@
+package pgstorage
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// AppendAudit adds events to the audit log. Events are never changed or removed.
+func (s *PGStorage) AppendAudit(ctx context.Context, events ...models.AuditEvent) error {
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		batch.Queue(`INSERT INTO audit_log (id, created_at, action, actor_id, actor_ip, transport, request_id,
+												domain, short_url, workspace_id, before, after)
+						VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+			e.ID, e.Time, e.Action, nullString(e.ActorID), nullString(e.ActorIP), e.Transport, nullString(e.RequestID),
+			e.Domain, nullString(e.ShortURL), nullString(e.WorkspaceID), nullableJSON(e.Before), nullableJSON(e.After))
+	}
+
+	err := s.conn.SendBatch(ctx, batch).Close()
+	if err != nil {
+		s.logger.Error("pgstorage:AppendAudit ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// QueryAudit returns audit events matching q, newest first.
+func (s *PGStorage) QueryAudit(ctx context.Context, q models.AuditQuery) ([]models.AuditEvent, error) {
+	conds := []string{"TRUE"}
+	var args []any
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if q.ActorID != "" {
+		conds = append(conds, "actor_id = "+arg(q.ActorID))
+	}
+
+	if q.ShortURL != "" {
+		conds = append(conds, "short_url = "+arg(q.ShortURL))
+	}
+
+	if len(q.Domains) > 0 {
+		conds = append(conds, "domain = ANY("+arg(pq.Array(q.Domains))+")")
+	}
+
+	if q.Action != "" {
+		conds = append(conds, "action = "+arg(q.Action))
+	}
+
+	if !q.From.IsZero() {
+		conds = append(conds, "created_at >= "+arg(q.From))
+	}
+
+	if !q.To.IsZero() {
+		conds = append(conds, "created_at < "+arg(q.To))
+	}
+
+	query := `SELECT id, created_at, action, actor_id, host(actor_ip), transport, request_id,
+				domain, short_url, workspace_id, before, after
+				FROM audit_log WHERE ` + strings.Join(conds, " AND ") + ` ORDER BY created_at DESC, id`
+	if q.Limit > 0 {
+		query += " LIMIT " + arg(q.Limit)
+	}
+
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("pgstorage:QueryAudit ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	var result []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		var actorID, actorIP, requestID, shortURL, workspaceID *string
+
+		err = rows.Scan(&e.ID, &e.Time, &e.Action, &actorID, &actorIP, &e.Transport, &requestID,
+			&e.Domain, &shortURL, &workspaceID, &e.Before, &e.After)
+		if err != nil {
+			s.logger.Error("pgstorage:QueryAudit Error in row", zap.Error(err))
+			return nil, errs.ErrInternalServerError
+		}
+
+		e.ActorID = deref(actorID)
+		e.ActorIP = deref(actorIP)
+		e.RequestID = deref(requestID)
+		e.ShortURL = deref(shortURL)
+		e.WorkspaceID = deref(workspaceID)
+
+		result = append(result, e)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:QueryAudit Error in rows", zap.Error(rows.Err()))
+		return nil, errs.ErrInternalServerError
+	}
+
+	return result, nil
+}
+
+func deref(v *string) string {
+	if v == nil {
+		return ""
+	}
+
+	return *v
+}
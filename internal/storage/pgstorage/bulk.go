@@ -0,0 +1,184 @@
+package pgstorage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// copyThreshold is the number of new links from which they are inserted with COPY.
+	// Below it creating a temporary table costs more than it saves.
+	copyThreshold = 100
+	// valuesChunkSize is the number of rows in one multi-row INSERT, it keeps parameters under the limit of 65535.
+	valuesChunkSize = 1000
+	valuesNumCols   = 11
+)
+
+// newLink is a batch item that gets a new short URL.
+type newLink struct {
+	ID   string
+	item dto.BatchRequest
+	// idx is the position of the item in the batch.
+	idx int
+}
+
+// insertFunc inserts links skipping those that conflict with existing ones and returns IDs of inserted links.
+type insertFunc func(ctx context.Context, tx pgx.Tx, userID string, links []newLink, opts models.URLOptions) (map[string]struct{}, error)
+
+// insertLinks inserts links with insert until every one is either inserted or turns out to exist.
+// Links that lost their generated ID to another link get a new one, links created concurrently by the same user
+// in per-user scope get the short URL of that link. Returns the final short URLs keyed by position in the batch.
+func (s *PGStorage) insertLinks(ctx context.Context, tx pgx.Tx, userID string, links []newLink, opts models.URLOptions, insert insertFunc) (map[int]dto.BatchResponse, error) {
+	result := make(map[int]dto.BatchResponse, len(links))
+
+	for len(links) > 0 {
+		inserted, err := insert(ctx, tx, userID, links, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		var conflicts []newLink
+		var canonicalURLs []string
+		for _, link := range links {
+			if _, ok := inserted[link.ID]; ok {
+				result[link.idx] = dto.BatchResponse{CorrelationID: link.item.CorrelationID, ShortURL: link.ID, Status: dto.BatchStatusCreated}
+				continue
+			}
+
+			conflicts = append(conflicts, link)
+			canonicalURLs = append(canonicalURLs, link.item.CanonicalURL)
+		}
+
+		if len(conflicts) == 0 {
+			break
+		}
+
+		existing, err := s.findExisting(ctx, tx, userID, opts.Domain, canonicalURLs...)
+		if err != nil {
+			return nil, err
+		}
+
+		links = links[:0]
+		taken := make(map[string]struct{}, len(conflicts))
+		for _, link := range conflicts {
+			if ID, ok := existing[link.item.CanonicalURL]; ok {
+				result[link.idx] = dto.BatchResponse{CorrelationID: link.item.CorrelationID, ShortURL: ID, Status: dto.BatchStatusExisting}
+				continue
+			}
+
+			link.ID = uniqueID(taken)
+			links = append(links, link)
+		}
+	}
+
+	return result, nil
+}
+
+// insertValues inserts links with multi-row INSERT statements.
+func (s *PGStorage) insertValues(ctx context.Context, tx pgx.Tx, userID string, links []newLink, opts models.URLOptions) (map[string]struct{}, error) {
+	inserted := make(map[string]struct{}, len(links))
+	dedupe := s.dedupe != models.DedupeNone
+
+	for start := 0; start < len(links); start += valuesChunkSize {
+		chunk := links[start:min(start+valuesChunkSize, len(links))]
+
+		args := make([]interface{}, 0, len(chunk)*valuesNumCols)
+		for _, link := range chunk {
+			args = append(args, userID, opts.Domain, link.ID, link.item.OriginalURL, link.item.CanonicalURL, dedupe, nullString(opts.WorkspaceID),
+				nullString(opts.PasswordHash), nullInt(opts.MaxClicks), nullInt(opts.MaxClicks), redirectType(opts))
+		}
+
+		query := fmt.Sprintf(`INSERT INTO urls (user_id, domain, short_url, full_url, canonical_url, dedupe, workspace_id, password_hash, max_clicks, clicks_left, redirect_type)
+								VALUES %s ON CONFLICT DO NOTHING RETURNING short_url`, helpers.BuildValuePlaceholders(valuesNumCols, len(chunk)))
+
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		err = collectIDs(rows, inserted)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return inserted, nil
+}
+
+// insertCopy copies links into a temporary table and moves them into urls with a single INSERT ... SELECT.
+func (s *PGStorage) insertCopy(ctx context.Context, tx pgx.Tx, userID string, links []newLink, opts models.URLOptions) (map[string]struct{}, error) {
+	// The table is reused when links are inserted again in the same transaction.
+	_, err := tx.Exec(ctx, `CREATE TEMP TABLE IF NOT EXISTS urls_import (
+								short_url TEXT NOT NULL,
+								full_url TEXT NOT NULL,
+								canonical_url TEXT NOT NULL
+							) ON COMMIT DROP`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `TRUNCATE urls_import`)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{"urls_import"}, []string{"short_url", "full_url", "canonical_url"},
+		pgx.CopyFromSlice(len(links), func(i int) ([]any, error) {
+			return []any{links[i].ID, links[i].item.OriginalURL, links[i].item.CanonicalURL}, nil
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `INSERT INTO urls (user_id, domain, short_url, full_url, canonical_url, dedupe, workspace_id, password_hash, max_clicks, clicks_left, redirect_type)
+								SELECT $1::uuid, $7::text, short_url, full_url, canonical_url, $2::boolean, $3::uuid, $4::text, $5::integer, $5::integer, $6::smallint
+								FROM urls_import
+								ON CONFLICT DO NOTHING RETURNING short_url`,
+		userID, s.dedupe != models.DedupeNone, nullString(opts.WorkspaceID), nullString(opts.PasswordHash), nullInt(opts.MaxClicks), redirectType(opts), opts.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted := make(map[string]struct{}, len(links))
+
+	err = collectIDs(rows, inserted)
+	if err != nil {
+		return nil, err
+	}
+
+	return inserted, nil
+}
+
+// collectIDs adds short URLs returned by rows to ids.
+func collectIDs(rows pgx.Rows, ids map[string]struct{}) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		var ID string
+		err := rows.Scan(&ID)
+		if err != nil {
+			return err
+		}
+
+		ids[ID] = struct{}{}
+	}
+
+	return rows.Err()
+}
+
+// uniqueID generates an ID that isn't in taken and adds it there.
+// Links inserted by one statement need distinct IDs to tell which of them were skipped.
+func uniqueID(taken map[string]struct{}) string {
+	for {
+		ID := helpers.RandomString(6)
+		if _, ok := taken[ID]; !ok {
+			taken[ID] = struct{}{}
+			return ID
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package pgstorage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// BenchmarkInsertLinks compares multi-row INSERT with COPY.
+// Requires DATABASE_DSN of a database migrated by the server, every insert is rolled back.
+func BenchmarkInsertLinks(b *testing.B) {
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		b.Skip("DATABASE_DSN is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	require.NoError(b, err)
+	defer pool.Close()
+
+	s := &PGStorage{conn: pool, dedupe: models.DedupeNone, logger: zap.NewNop()}
+
+	const userID = "00000000-0000-0000-0000-000000000001"
+
+	for _, size := range []int{10, 1000, 100000} {
+		for _, bb := range []struct {
+			name   string
+			insert insertFunc
+		}{
+			{name: "values", insert: s.insertValues},
+			{name: "copy", insert: s.insertCopy},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", bb.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					links := make([]newLink, size)
+					taken := make(map[string]struct{}, size)
+					for j := range links {
+						url := fmt.Sprintf("https://bench.example/%d/%d", i, j)
+						links[j] = newLink{ID: uniqueID(taken), item: dto.BatchRequest{OriginalURL: url, CanonicalURL: url}, idx: j}
+					}
+
+					tx, err := pool.Begin(context.Background())
+					require.NoError(b, err)
+					b.StartTimer()
+
+					_, err = s.insertLinks(context.Background(), tx, userID, links, models.URLOptions{}, bb.insert)
+					require.NoError(b, err)
+
+					b.StopTimer()
+					require.NoError(b, tx.Rollback(context.Background()))
+					b.StartTimer()
+				}
+			})
+		}
+	}
+}
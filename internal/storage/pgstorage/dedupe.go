@@ -0,0 +1,81 @@
+package pgstorage
+
+import (
+	"context"
+	"slices"
+
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// domainLockSpace keeps domain locks apart from URL locks, which are keyed by a single bigint.
+const domainLockSpace = 1
+
+// lockCanonical serializes creation of links to the same URLs on domain by different users in global scope.
+// Per-user scope is enforced by the unique index on (domain, user_id, canonical_url) instead.
+//
+// A single URL is locked by itself under a shared lock of the domain. Batches take the domain lock exclusively
+// instead of a lock per URL, large ones would run out of lock slots and round trips.
+func (s *PGStorage) lockCanonical(ctx context.Context, tx pgx.Tx, domain string, canonicalURLs ...string) error {
+	if s.dedupe != models.DedupeGlobal || len(canonicalURLs) == 0 {
+		return nil
+	}
+
+	urls := slices.Compact(slices.Sorted(slices.Values(canonicalURLs)))
+	if len(urls) > 1 {
+		_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1, hashtext($2))`, domainLockSpace, domain)
+		return err
+	}
+
+	// Always the domain lock first, a batch never waits on a URL lock while holding it.
+	_, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock_shared($1, hashtext($2))`, domainLockSpace, domain)
+	if err != nil {
+		return err
+	}
+
+	// URLs can't contain spaces, so the key is unambiguous.
+	_, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1 || ' ' || $2))`, domain, urls[0])
+
+	return err
+}
+
+// findExisting returns short URLs of links to canonicalURLs on domain that userID would get instead of new ones,
+// keyed by canonical URL. Deleted links are skipped, their URLs get new links.
+func (s *PGStorage) findExisting(ctx context.Context, tx pgx.Tx, userID, domain string, canonicalURLs ...string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	var (
+		rows pgx.Rows
+		err  error
+	)
+
+	switch s.dedupe {
+	case models.DedupeNone:
+		return result, nil
+	case models.DedupePerUser:
+		rows, err = tx.Query(ctx, `SELECT canonical_url, short_url FROM urls
+									WHERE canonical_url = ANY($1) AND dedupe AND NOT deleted_flag AND domain = $2 AND user_id = $3`, canonicalURLs, domain, userID)
+	default:
+		rows, err = tx.Query(ctx, `SELECT canonical_url, short_url FROM urls
+									WHERE canonical_url = ANY($1) AND dedupe AND NOT deleted_flag AND domain = $2`, canonicalURLs, domain)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var canonicalURL, shortURL string
+		err = rows.Scan(&canonicalURL, &shortURL)
+		if err != nil {
+			return nil, err
+		}
+
+		// In global scope several users may have links to the same URL created before, any of them will do.
+		if _, ok := result[canonicalURL]; !ok {
+			result[canonicalURL] = shortURL
+		}
+	}
+
+	return result, rows.Err()
+}
@@ -0,0 +1,59 @@
+package pgstorage
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// TestPGStorage_DedupeSkipsDeleted requires DATABASE_DSN of a database migrated by the server.
+func TestPGStorage_DedupeSkipsDeleted(t *testing.T) {
+	dsn := os.Getenv("DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("DATABASE_DSN is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	for _, scope := range []models.DedupeScope{models.DedupeGlobal, models.DedupePerUser} {
+		t.Run(string(scope), func(t *testing.T) {
+			ctx := context.Background()
+			s := &PGStorage{conn: pool, dedupe: scope, logger: zap.NewNop()}
+
+			userID := uuid.NewString()
+			url := "https://dedupe.example/" + uuid.NewString()
+			t.Cleanup(func() {
+				_, err := pool.Exec(context.Background(), `DELETE FROM urls WHERE canonical_url = $1`, url)
+				assert.NoError(t, err)
+			})
+
+			first, err := s.CreateShortURL(ctx, userID, "", url, url, models.URLOptions{})
+			require.NoError(t, err)
+
+			again, err := s.CreateShortURL(ctx, userID, "", url, url, models.URLOptions{})
+			require.ErrorIs(t, err, errs.ErrDuplicate)
+			assert.Equal(t, first, again)
+
+			require.NoError(t, s.DeleteURLs(ctx, userID, "", []string{first}))
+
+			second, err := s.CreateShortURL(ctx, userID, "", url, url, models.URLOptions{})
+			require.NoError(t, err)
+			assert.NotEqual(t, first, second)
+
+			// The new link answers rather than 410.
+			link, err := s.GetLongURL(ctx, "", second)
+			require.NoError(t, err)
+			assert.Equal(t, url, link.OriginalURL)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package pgstorage
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// hostExpr extracts the lowercased host of the destination URL, skipping user info.
+const hostExpr = `lower(substring(full_url from '^[^:]+://(?:[^@/?#]*@)?([^:/?#]*)'))`
+
+// FindLinks returns links of every user matching q, newest first. Deleted links are not included.
+func (s *PGStorage) FindLinks(ctx context.Context, q models.LinkQuery) ([]models.Link, error) {
+	where, args := linkQueryWhere(q)
+
+	rows, err := s.conn.Query(ctx, `SELECT short_url, domain, full_url, user_id, workspace_id, created_at,
+										password_hash, max_clicks, clicks_left, redirect_type,
+										disabled_status, disabled_reason
+										FROM urls WHERE `+where+` ORDER BY created_at DESC, id DESC`, args...)
+	if err != nil {
+		s.logger.Error("pgstorage:FindLinks ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	var result []models.Link
+	for rows.Next() {
+		var link models.Link
+		var userID, workspaceID, passwordHash, disabledReason *string
+		var createdAt *time.Time
+		var maxClicks, clicksLeft, disabledStatus *int
+
+		err = rows.Scan(&link.ShortURL, &link.Domain, &link.OriginalURL, &userID, &workspaceID, &createdAt,
+			&passwordHash, &maxClicks, &clicksLeft, &link.RedirectType,
+			&disabledStatus, &disabledReason)
+		if err != nil {
+			s.logger.Error("pgstorage:FindLinks Error in row", zap.Error(err))
+			return nil, errs.ErrInternalServerError
+		}
+
+		if userID != nil {
+			link.UserID = *userID
+		}
+
+		if workspaceID != nil {
+			link.WorkspaceID = *workspaceID
+		}
+
+		if createdAt != nil {
+			link.CreatedAt = *createdAt
+		}
+
+		if passwordHash != nil {
+			link.PasswordHash = *passwordHash
+		}
+
+		if maxClicks != nil && clicksLeft != nil {
+			link.MaxClicks = *maxClicks
+			link.ClicksLeft = *clicksLeft
+		}
+
+		link.Takedown = takedown(disabledStatus, disabledReason)
+
+		result = append(result, link)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:FindLinks Error in rows", zap.Error(rows.Err()))
+		return nil, errs.ErrInternalServerError
+	}
+
+	return result, nil
+}
+
+// DisableLinks sets the takedown of every link matching q, the zero Takedown enables them again.
+// Returns the number of links changed.
+func (s *PGStorage) DisableLinks(ctx context.Context, q models.LinkQuery, t models.Takedown) (int, error) {
+	where, args := linkQueryWhere(q)
+
+	var status, reason any
+	if t.Status != 0 {
+		status, reason = t.Status, t.Reason
+	}
+
+	args = append(args, status, reason)
+	query := `UPDATE urls SET disabled_status = $` + strconv.Itoa(len(args)-1) + `, disabled_reason = $` + strconv.Itoa(len(args)) +
+		` WHERE ` + where
+
+	result, err := s.conn.Exec(ctx, query, args...)
+	if err != nil {
+		s.logger.Error("pgstorage:DisableLinks ", zap.Error(err))
+		return 0, errs.ErrInternalServerError
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// linkQueryWhere returns the condition selecting links matching q and its arguments, numbered from $1.
+func linkQueryWhere(q models.LinkQuery) (string, []any) {
+	conds := []string{"deleted_flag = FALSE"}
+	var args []any
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if q.ShortURL != "" {
+		conds = append(conds, "short_url = "+arg(q.ShortURL))
+	}
+
+	if len(q.Domains) > 0 {
+		conds = append(conds, "domain = ANY("+arg(pq.Array(q.Domains))+")")
+	}
+
+	if q.Destination != "" {
+		p := arg(q.Destination)
+		conds = append(conds, "(full_url = "+p+" OR canonical_url = "+p+")")
+	}
+
+	if q.Host != "" {
+		// right() instead of LIKE, host names may contain the _ wildcard.
+		p := arg(q.Host)
+		conds = append(conds, "("+hostExpr+" = "+p+" OR right("+hostExpr+", length("+p+") + 1) = '.' || "+p+")")
+	}
+
+	if q.UserID != "" {
+		conds = append(conds, "user_id = "+arg(q.UserID))
+	}
+
+	return strings.Join(conds, " AND "), args
+}
+
+// takedown builds a Takedown from nullable columns.
+func takedown(status *int, reason *string) models.Takedown {
+	var t models.Takedown
+	if status != nil {
+		t.Status = *status
+	}
+
+	if reason != nil {
+		t.Reason = *reason
+	}
+
+	return t
+}
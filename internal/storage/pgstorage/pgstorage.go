@@ -3,12 +3,12 @@ package pgstorage
 import (
 	"context"
 	"errors"
-	"fmt"
-	"slices"
+	"time"
 
 	"github.com/MukizuL/shortener/internal/dto"
 	"github.com/MukizuL/shortener/internal/errs"
 	"github.com/MukizuL/shortener/internal/helpers"
+	"github.com/MukizuL/shortener/internal/models"
 	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -16,11 +16,8 @@ import (
 	"go.uber.org/zap"
 )
 
-func (s *PGStorage) BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest) ([]dto.BatchResponse, error) {
-	const batchSize = 2
-	const numCols = 3
-
-	result := make([]dto.BatchResponse, 0, len(data))
+func (s *PGStorage) BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest, opts models.URLOptions) ([]dto.BatchResponse, error) {
+	result := make([]dto.BatchResponse, len(data))
 
 	tx, err := s.conn.Begin(ctx)
 	if err != nil {
@@ -29,33 +26,63 @@ func (s *PGStorage) BatchCreateShortURL(ctx context.Context, userID, urlBase str
 	}
 	defer tx.Rollback(ctx)
 
-	for chunk := range slices.Chunk(data, batchSize) {
-		numRows := len(chunk)
-		args := make([]interface{}, 0, numRows*numCols)
-		for _, item := range chunk {
-			ID := helpers.RandomString(6)
-			args = append(args, userID, ID, item.OriginalURL)
+	canonicalURLs := make([]string, 0, len(data))
+	for _, item := range data {
+		canonicalURLs = append(canonicalURLs, item.CanonicalURL)
+	}
 
-			result = append(result, dto.BatchResponse{CorrelationID: item.CorrelationID, ShortURL: urlBase + ID})
-		}
+	err = s.lockCanonical(ctx, tx, opts.Domain, canonicalURLs...)
+	if err != nil {
+		s.logger.Error("pgstorage:BatchCreateShortURL ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
 
-		valuesPart := helpers.BuildValuePlaceholders(numCols, numRows)
+	existing, err := s.findExisting(ctx, tx, userID, opts.Domain, canonicalURLs...)
+	if err != nil {
+		s.logger.Error("pgstorage:BatchCreateShortURL ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
 
-		query := fmt.Sprintf("INSERT INTO urls (user_id, short_url, full_url) VALUES %s", valuesPart)
+	dedupe := s.dedupe != models.DedupeNone
 
-		_, err = tx.Exec(ctx, query, args...)
-		if err != nil {
-			var pgErr *pgconn.PgError
-			if errors.As(err, &pgErr) {
-				switch pgErr.Code {
-				case pgerrcode.UniqueViolation:
-					return nil, errs.ErrDuplicate
-				}
-			}
-
-			s.logger.Error("pgstorage:BatchCreateShortURL other pg error", zap.Error(pgErr))
-			return nil, errs.ErrInternalServerError
+	var links []newLink
+	// Items repeating a URL of an earlier item get the link created for it, keyed by position of that item.
+	repeats := make(map[int]int)
+	first := make(map[string]int)
+	taken := make(map[string]struct{}, len(data))
+	for i, item := range data {
+		if ID, ok := existing[item.CanonicalURL]; ok {
+			result[i] = dto.BatchResponse{CorrelationID: item.CorrelationID, ShortURL: urlBase + ID, Status: dto.BatchStatusExisting}
+			continue
+		}
+
+		if j, ok := first[item.CanonicalURL]; ok && dedupe {
+			repeats[i] = j
+			continue
 		}
+
+		first[item.CanonicalURL] = i
+		links = append(links, newLink{ID: uniqueID(taken), item: item, idx: i})
+	}
+
+	insert := s.insertValues
+	if len(links) >= copyThreshold {
+		insert = s.insertCopy
+	}
+
+	created, err := s.insertLinks(ctx, tx, userID, links, opts, insert)
+	if err != nil {
+		s.logger.Error("pgstorage:BatchCreateShortURL ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+
+	for i, res := range created {
+		res.ShortURL = urlBase + res.ShortURL
+		result[i] = res
+	}
+
+	for i, j := range repeats {
+		result[i] = dto.BatchResponse{CorrelationID: data[i].CorrelationID, ShortURL: result[j].ShortURL, Status: dto.BatchStatusExisting}
 	}
 
 	err = tx.Commit(ctx)
@@ -67,8 +94,10 @@ func (s *PGStorage) BatchCreateShortURL(ctx context.Context, userID, urlBase str
 	return result, nil
 }
 
-func (s *PGStorage) CreateShortURL(ctx context.Context, userID, urlBase, fullURL string) (string, error) {
-	ID := helpers.RandomString(6)
+// createAttempts caps retries of an insert that collided with an existing short URL.
+const createAttempts = 5
+
+func (s *PGStorage) CreateShortURL(ctx context.Context, userID, urlBase, fullURL, canonicalURL string, opts models.URLOptions) (string, error) {
 	tx, err := s.conn.Begin(ctx)
 	if err != nil {
 		s.logger.Error("pgstorage:CreateShortURL Failed to start a transaction", zap.Error(err))
@@ -76,101 +105,204 @@ func (s *PGStorage) CreateShortURL(ctx context.Context, userID, urlBase, fullURL
 	}
 	defer tx.Rollback(ctx)
 
-	var rows int
-	var rowUserID, rowShortURL string
-	err = tx.QueryRow(ctx, `SELECT COUNT(*), user_id, short_url FROM urls WHERE full_url = $1 GROUP BY user_id, short_url`, fullURL).Scan(&rows, &rowUserID, &rowShortURL)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	err = s.lockCanonical(ctx, tx, opts.Domain, canonicalURL)
+	if err != nil {
 		s.logger.Error("pgstorage:CreateShortURL ", zap.Error(err))
 		return "", errs.ErrInternalServerError
 	}
 
-	if rows > 0 && rowUserID != userID {
-		return urlBase + rowShortURL, errs.ErrDuplicate
-	}
+	for range createAttempts {
+		existing, err := s.findExisting(ctx, tx, userID, opts.Domain, canonicalURL)
+		if err != nil {
+			s.logger.Error("pgstorage:CreateShortURL ", zap.Error(err))
+			return "", errs.ErrInternalServerError
+		}
 
-	err = tx.QueryRow(ctx, `INSERT INTO urls (user_id, short_url, full_url)
-										VALUES ($1, $2, $3)
-										ON CONFLICT(full_url)
-										DO UPDATE SET full_url = urls.full_url
-										RETURNING short_url`, userID, ID, fullURL).Scan(&ID)
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			if pgErr.Code == pgerrcode.UniqueViolation {
-				return s.CreateShortURL(ctx, userID, urlBase, fullURL)
-			}
+		if ID, ok := existing[canonicalURL]; ok {
+			return urlBase + ID, errs.ErrDuplicate
 		}
 
-		s.logger.Error("pgstorage:CreateShortURL ", zap.Error(err))
-		return "", errs.ErrInternalServerError
+		ID := helpers.RandomString(6)
+		inserted, err := s.insertLink(ctx, tx, userID, ID, fullURL, canonicalURL, opts)
+		if err != nil {
+			s.logger.Error("pgstorage:CreateShortURL ", zap.Error(err))
+			return "", errs.ErrInternalServerError
+		}
+
+		if !inserted {
+			continue
+		}
+
+		err = tx.Commit(ctx)
+		if err != nil {
+			s.logger.Error("pgstorage:CreateShortURL ", zap.Error(err))
+			return "", errs.ErrInternalServerError
+		}
+
+		return urlBase + ID, nil
 	}
 
-	err = tx.Commit(ctx)
+	s.logger.Error("pgstorage:CreateShortURL Out of attempts to insert a unique link")
+	return "", errs.ErrInternalServerError
+}
+
+// insertLink inserts a link with ID within a savepoint of tx. Reports false if it clashed with a unique index,
+// either the short URL is taken or the same user created the link concurrently, tx stays usable to retry.
+func (s *PGStorage) insertLink(ctx context.Context, tx pgx.Tx, userID, ID, fullURL, canonicalURL string, opts models.URLOptions) (bool, error) {
+	sp, err := tx.Begin(ctx)
 	if err != nil {
-		s.logger.Error("pgstorage:CreateShortURL ", zap.Error(err))
-		return "", errs.ErrInternalServerError
+		return false, err
+	}
+	defer sp.Rollback(ctx)
+
+	_, err = sp.Exec(ctx, `INSERT INTO urls (user_id, domain, short_url, full_url, canonical_url, dedupe, workspace_id, password_hash, max_clicks, clicks_left, redirect_type)
+										VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9, $10)`,
+		userID, opts.Domain, ID, fullURL, canonicalURL, s.dedupe != models.DedupeNone, nullString(opts.WorkspaceID), nullString(opts.PasswordHash), nullInt(opts.MaxClicks), redirectType(opts))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return false, nil
+		}
+
+		return false, err
 	}
 
-	return urlBase + ID, nil
+	return true, sp.Commit(ctx)
 }
 
-func (s *PGStorage) GetLongURL(ctx context.Context, ID string) (string, error) {
-	var result string
+func (s *PGStorage) GetLongURL(ctx context.Context, domain, ID string) (models.Link, error) {
+	result := models.Link{ShortURL: ID, Domain: domain}
 	var deleted bool
-	err := s.conn.QueryRow(ctx, `SELECT full_url, deleted_flag FROM urls WHERE short_url = $1`, ID).Scan(&result, &deleted)
+	var userID, workspaceID, passwordHash, disabledReason *string
+	var createdAt *time.Time
+	var maxClicks, clicksLeft, disabledStatus *int
+	err := s.conn.QueryRow(ctx, `SELECT full_url, deleted_flag, user_id, workspace_id, created_at,
+									password_hash, max_clicks, clicks_left, redirect_type,
+									disabled_status, disabled_reason
+									FROM urls WHERE domain = $1 AND short_url = $2`, domain, ID).
+		Scan(&result.OriginalURL, &deleted, &userID, &workspaceID, &createdAt,
+			&passwordHash, &maxClicks, &clicksLeft, &result.RedirectType,
+			&disabledStatus, &disabledReason)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", errs.ErrURLNotFound
+			return models.Link{}, errs.ErrURLNotFound
 		}
 
 		s.logger.Error("pgstorage:GetLongURL ", zap.Error(err))
-		return "", errs.ErrInternalServerError
+		return models.Link{}, errs.ErrInternalServerError
 	}
 
 	if deleted {
-		return "", errs.ErrGone
+		return models.Link{}, errs.ErrGone
 	}
 
+	if userID != nil {
+		result.UserID = *userID
+	}
+
+	if workspaceID != nil {
+		result.WorkspaceID = *workspaceID
+	}
+
+	if createdAt != nil {
+		result.CreatedAt = *createdAt
+	}
+
+	if passwordHash != nil {
+		result.PasswordHash = *passwordHash
+	}
+
+	if maxClicks != nil && clicksLeft != nil {
+		result.MaxClicks = *maxClicks
+		result.ClicksLeft = *clicksLeft
+	}
+
+	result.Takedown = takedown(disabledStatus, disabledReason)
+
 	return result, nil
 }
 
-func (s *PGStorage) GetUserURLs(ctx context.Context, userID string) ([]dto.URLPair, error) {
+// ConsumeClick uses up one click of a limited link. Returns errs.ErrGone when no clicks are left.
+// The conditional update makes concurrent redirects unable to overshoot the limit.
+func (s *PGStorage) ConsumeClick(ctx context.Context, domain, ID string) (int, error) {
+	var left int
+	err := s.conn.QueryRow(ctx, `UPDATE urls SET clicks_left = clicks_left - 1
+									WHERE domain = $1 AND short_url = $2 AND clicks_left > 0 AND deleted_flag = FALSE
+									RETURNING clicks_left`, domain, ID).Scan(&left)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errs.ErrGone
+		}
+
+		s.logger.Error("pgstorage:ConsumeClick ", zap.Error(err))
+		return 0, errs.ErrInternalServerError
+	}
+
+	return left, nil
+}
+
+func (s *PGStorage) GetUserURLs(ctx context.Context, userID string, domains []string) ([]dto.URLPair, error) {
 	var result []dto.URLPair
-	rows, err := s.conn.Query(ctx, "SELECT short_url, full_url FROM urls WHERE user_id = $1 AND deleted_flag = FALSE", userID)
+
+	err := s.WalkUserURLs(ctx, userID, domains, func(pair dto.URLPair) error {
+		result = append(result, pair)
+		return nil
+	})
 	if err != nil {
-		s.logger.Error("pgstorage:GetUserURLs ", zap.Error(err))
-		return nil, errs.ErrInternalServerError
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// WalkUserURLs streams rows of the user's URLs into fn.
+func (s *PGStorage) WalkUserURLs(ctx context.Context, userID string, domains []string, fn func(dto.URLPair) error) error {
+	query := `SELECT short_url, full_url, domain FROM urls
+				WHERE (user_id = $1 OR workspace_id IN (SELECT workspace_id FROM workspace_members WHERE user_id = $1))
+				AND (cardinality($2::text[]) = 0 OR domain = ANY($2))
+				AND deleted_flag = FALSE`
+
+	rows, err := s.conn.Query(ctx, query, userID, pq.Array(domains))
+	if err != nil {
+		s.logger.Error("pgstorage:WalkUserURLs ", zap.Error(err))
+		return errs.ErrInternalServerError
 	}
 	defer rows.Close()
 
-	var shortURL, fullURL string
+	var shortURL, fullURL, domain string
 	for rows.Next() {
-		err = rows.Scan(&shortURL, &fullURL)
+		err = rows.Scan(&shortURL, &fullURL, &domain)
 		if err != nil {
-			s.logger.Error("pgstorage:GetUserURLs Error in row", zap.Error(err))
+			s.logger.Error("pgstorage:WalkUserURLs Error in row", zap.Error(err))
 			continue
 		}
 
-		data := dto.URLPair{
+		err = fn(dto.URLPair{
 			ShortURL:    shortURL,
 			OriginalURL: fullURL,
+			Domain:      domain,
+		})
+		if err != nil {
+			return err
 		}
-
-		result = append(result, data)
 	}
 
 	if rows.Err() != nil {
-		s.logger.Error("pgstorage:GetUserURLs Error in rows", zap.Error(err))
-		return nil, rows.Err()
+		s.logger.Error("pgstorage:WalkUserURLs Error in rows", zap.Error(rows.Err()))
+		return errs.ErrInternalServerError
 	}
 
-	return result, nil
+	return nil
 }
 
-func (s *PGStorage) DeleteURLs(ctx context.Context, userID string, urls []string) error {
-	query := "UPDATE urls SET deleted_flag = TRUE WHERE user_id = $1 AND short_url ANY($2)"
+func (s *PGStorage) DeleteURLs(ctx context.Context, userID, domain string, urls []string) error {
+	query := `UPDATE urls SET deleted_flag = TRUE
+				WHERE domain = $4 AND short_url = ANY($2)
+				AND (user_id = $1 OR workspace_id IN (
+					SELECT workspace_id FROM workspace_members WHERE user_id = $1 AND role = ANY($3)
+				))`
 
-	result, err := s.conn.Exec(ctx, query, userID, pq.Array(urls))
+	result, err := s.conn.Exec(ctx, query, userID, pq.Array(urls), pq.Array(editorRoles), domain)
 	if err != nil {
 		s.logger.Error("pgstorage:DeleteURLs ", zap.Error(err))
 		return errs.ErrInternalServerError
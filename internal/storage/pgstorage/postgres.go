@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/models"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
@@ -11,6 +12,7 @@ import (
 
 type PGStorage struct {
 	conn   *pgxpool.Pool
+	dedupe models.DedupeScope
 	logger *zap.Logger
 }
 
@@ -22,6 +24,7 @@ func newPGStorage(cfg *config.Config, logger *zap.Logger) *PGStorage {
 
 	return &PGStorage{
 		conn:   dbpool,
+		dedupe: models.DedupeScope(cfg.DedupeScope),
 		logger: logger,
 	}
 }
@@ -29,3 +32,39 @@ func newPGStorage(cfg *config.Config, logger *zap.Logger) *PGStorage {
 func Provide() fx.Option {
 	return fx.Provide(newPGStorage)
 }
+
+// nullString converts an empty string into SQL NULL.
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+
+	return &s
+}
+
+// nullableJSON converts an empty map into SQL NULL instead of an empty JSON object.
+func nullableJSON(v map[string]any) any {
+	if len(v) == 0 {
+		return nil
+	}
+
+	return v
+}
+
+// nullInt converts zero into SQL NULL.
+func nullInt(i int) *int {
+	if i == 0 {
+		return nil
+	}
+
+	return &i
+}
+
+// redirectType returns redirect type to store, replacing unset one with the default.
+func redirectType(opts models.URLOptions) int {
+	if opts.RedirectType == 0 {
+		return models.DefaultRedirectType
+	}
+
+	return opts.RedirectType
+}
@@ -0,0 +1,118 @@
+package pgstorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// CreateReport queues r, unless its reporter already has a pending report about the link. Returns the number
+// of distinct reporters with pending reports about the link filed since since.
+func (s *PGStorage) CreateReport(ctx context.Context, r models.Report, since time.Time) (int, error) {
+	var contact any
+	if r.Contact != "" {
+		contact = r.Contact
+	}
+
+	_, err := s.conn.Exec(ctx, `INSERT INTO abuse_reports (id, domain, short_url, reason, contact, reporter, created_at)
+									VALUES ($1, $2, $3, $4, $5, $6, $7)
+									ON CONFLICT (domain, short_url, reporter) WHERE status = 'pending' DO NOTHING`,
+		r.ID, r.Domain, r.ShortURL, r.Reason, contact, r.Reporter, r.CreatedAt)
+	if err != nil {
+		s.logger.Error("pgstorage:CreateReport ", zap.Error(err))
+		return 0, errs.ErrInternalServerError
+	}
+
+	var n int
+	err = s.conn.QueryRow(ctx, `SELECT count(DISTINCT reporter) FROM abuse_reports
+									WHERE domain = $1 AND short_url = $2 AND status = 'pending' AND created_at >= $3`,
+		r.Domain, r.ShortURL, since).Scan(&n)
+	if err != nil {
+		s.logger.Error("pgstorage:CreateReport ", zap.Error(err))
+		return 0, errs.ErrInternalServerError
+	}
+
+	return n, nil
+}
+
+// GetReports returns reports with status, oldest first.
+func (s *PGStorage) GetReports(ctx context.Context, status models.ReportStatus) ([]models.Report, error) {
+	rows, err := s.conn.Query(ctx, `SELECT id, domain, short_url, reason, contact, reporter, status, created_at
+										FROM abuse_reports WHERE status = $1 ORDER BY created_at, id`, status)
+	if err != nil {
+		s.logger.Error("pgstorage:GetReports ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	var result []models.Report
+	for rows.Next() {
+		r, err := scanReport(rows)
+		if err != nil {
+			s.logger.Error("pgstorage:GetReports Error in row", zap.Error(err))
+			return nil, errs.ErrInternalServerError
+		}
+
+		result = append(result, r)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:GetReports Error in rows", zap.Error(rows.Err()))
+		return nil, errs.ErrInternalServerError
+	}
+
+	return result, nil
+}
+
+func (s *PGStorage) GetReport(ctx context.Context, ID string) (models.Report, error) {
+	r, err := scanReport(s.conn.QueryRow(ctx, `SELECT id, domain, short_url, reason, contact, reporter, status, created_at
+													FROM abuse_reports WHERE id = $1`, ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Report{}, errs.ErrReportNotFound
+		}
+
+		s.logger.Error("pgstorage:GetReport ", zap.Error(err))
+		return models.Report{}, errs.ErrInternalServerError
+	}
+
+	return r, nil
+}
+
+// ResolveReports sets status of every pending report about the link with ID on domain.
+// Returns the number of reports changed.
+func (s *PGStorage) ResolveReports(ctx context.Context, domain, ID string, status models.ReportStatus) (int, error) {
+	result, err := s.conn.Exec(ctx, `UPDATE abuse_reports SET status = $1, resolved_at = now()
+										WHERE domain = $2 AND short_url = $3 AND status = 'pending'`, status, domain, ID)
+	if err != nil {
+		s.logger.Error("pgstorage:ResolveReports ", zap.Error(err))
+		return 0, errs.ErrInternalServerError
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// scanReport scans a row of abuse_reports columns in the order the queries above select them.
+func scanReport(row pgx.Row) (models.Report, error) {
+	var r models.Report
+	var contact *string
+	var status string
+
+	err := row.Scan(&r.ID, &r.Domain, &r.ShortURL, &r.Reason, &contact, &r.Reporter, &status, &r.CreatedAt)
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	r.Status = models.ReportStatus(status)
+
+	if contact != nil {
+		r.Contact = *contact
+	}
+
+	return r, nil
+}
@@ -0,0 +1,245 @@
+package pgstorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// deliveryColumns are the columns scanDelivery reads, d is webhook_deliveries and w is webhooks.
+const deliveryColumns = `d.id, d.webhook_id, w.user_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+							d.last_error, d.response_status, d.created_at, d.delivered_at`
+
+func (s *PGStorage) CreateWebhook(ctx context.Context, w models.Webhook) error {
+	_, err := s.conn.Exec(ctx, `INSERT INTO webhooks (id, user_id, url, secret, events, created_at)
+									VALUES ($1, $2, $3, $4, $5, $6)`,
+		w.ID, w.UserID, w.URL, w.Secret, pq.Array(w.Events), w.CreatedAt)
+	if err != nil {
+		s.logger.Error("pgstorage:CreateWebhook ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// GetWebhooks returns webhooks of userID, oldest first.
+func (s *PGStorage) GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error) {
+	rows, err := s.conn.Query(ctx, `SELECT id, user_id, url, secret, events, created_at FROM webhooks
+										WHERE user_id = $1 ORDER BY created_at, id`, userID)
+	if err != nil {
+		s.logger.Error("pgstorage:GetWebhooks ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	var result []models.Webhook
+	for rows.Next() {
+		var w models.Webhook
+		err = rows.Scan(&w.ID, &w.UserID, &w.URL, &w.Secret, pq.Array(&w.Events), &w.CreatedAt)
+		if err != nil {
+			s.logger.Error("pgstorage:GetWebhooks Error in row", zap.Error(err))
+			return nil, errs.ErrInternalServerError
+		}
+
+		result = append(result, w)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:GetWebhooks Error in rows", zap.Error(rows.Err()))
+		return nil, errs.ErrInternalServerError
+	}
+
+	return result, nil
+}
+
+// DeleteWebhook deletes the webhook with ID of userID, its deliveries are deleted by cascade.
+func (s *PGStorage) DeleteWebhook(ctx context.Context, userID, ID string) error {
+	result, err := s.conn.Exec(ctx, `DELETE FROM webhooks WHERE id = $1 AND user_id = $2`, ID, userID)
+	if err != nil {
+		s.logger.Error("pgstorage:DeleteWebhook ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	if result.RowsAffected() == 0 {
+		return errs.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnqueueDeliveries queues a delivery of payload, due at, to every webhook of userID subscribed to event.
+// Returns the number of deliveries queued.
+func (s *PGStorage) EnqueueDeliveries(ctx context.Context, userID, event string, payload []byte, at time.Time) (int, error) {
+	result, err := s.conn.Exec(ctx, `INSERT INTO webhook_deliveries (id, webhook_id, event, payload, next_attempt_at, created_at)
+										SELECT gen_random_uuid(), id, $2, $3, $4, $4 FROM webhooks
+										WHERE user_id = $1 AND $2 = ANY(events)`,
+		userID, event, string(payload), at)
+	if err != nil {
+		s.logger.Error("pgstorage:EnqueueDeliveries ", zap.Error(err))
+		return 0, errs.ErrInternalServerError
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
+// ClaimDeliveries returns up to limit pending deliveries due by now and postpones them until now+lease.
+// Rows locked by another instance claiming at the same time are skipped.
+func (s *PGStorage) ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.Delivery, error) {
+	rows, err := s.conn.Query(ctx, `UPDATE webhook_deliveries d SET next_attempt_at = $2 FROM webhooks w
+										WHERE w.id = d.webhook_id AND d.id IN (
+											SELECT id FROM webhook_deliveries
+											WHERE status = 'pending' AND next_attempt_at <= $1
+											ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED)
+										RETURNING `+deliveryColumns+`, w.url, w.secret`,
+		now, now.Add(lease), limit)
+	if err != nil {
+		s.logger.Error("pgstorage:ClaimDeliveries ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	var result []models.Delivery
+	for rows.Next() {
+		var url, secret string
+		d, err := scanDelivery(rows, &url, &secret)
+		if err != nil {
+			s.logger.Error("pgstorage:ClaimDeliveries Error in row", zap.Error(err))
+			return nil, errs.ErrInternalServerError
+		}
+
+		d.URL, d.Secret = url, secret
+
+		result = append(result, d)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:ClaimDeliveries Error in rows", zap.Error(rows.Err()))
+		return nil, errs.ErrInternalServerError
+	}
+
+	return result, nil
+}
+
+// UpdateDelivery saves the outcome of an attempt to send d.
+func (s *PGStorage) UpdateDelivery(ctx context.Context, d models.Delivery) error {
+	var responseStatus, deliveredAt any
+	if d.ResponseStatus != 0 {
+		responseStatus = d.ResponseStatus
+	}
+
+	if !d.DeliveredAt.IsZero() {
+		deliveredAt = d.DeliveredAt
+	}
+
+	_, err := s.conn.Exec(ctx, `UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4,
+									last_error = $5, response_status = $6, delivered_at = $7 WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt, nullString(d.LastError), responseStatus, deliveredAt)
+	if err != nil {
+		s.logger.Error("pgstorage:UpdateDelivery ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// GetDeliveries returns up to limit deliveries of the webhook with webhookID of userID with status, newest first.
+// Fails with errs.ErrWebhookNotFound if userID has no such webhook.
+func (s *PGStorage) GetDeliveries(ctx context.Context, userID, webhookID string, status models.DeliveryStatus, limit int) ([]models.Delivery, error) {
+	var exists bool
+	err := s.conn.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = $1 AND user_id = $2)`,
+		webhookID, userID).Scan(&exists)
+	if err != nil {
+		s.logger.Error("pgstorage:GetDeliveries ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+
+	if !exists {
+		return nil, errs.ErrWebhookNotFound
+	}
+
+	rows, err := s.conn.Query(ctx, `SELECT `+deliveryColumns+` FROM webhook_deliveries d
+										JOIN webhooks w ON w.id = d.webhook_id
+										WHERE d.webhook_id = $1 AND ($2 = '' OR d.status = $2)
+										ORDER BY d.created_at DESC, d.id LIMIT $3`,
+		webhookID, string(status), limit)
+	if err != nil {
+		s.logger.Error("pgstorage:GetDeliveries ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	var result []models.Delivery
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			s.logger.Error("pgstorage:GetDeliveries Error in row", zap.Error(err))
+			return nil, errs.ErrInternalServerError
+		}
+
+		result = append(result, d)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:GetDeliveries Error in rows", zap.Error(rows.Err()))
+		return nil, errs.ErrInternalServerError
+	}
+
+	return result, nil
+}
+
+// RedeliverDelivery queues the delivery with ID again, due at and with no attempts made.
+// Fails with errs.ErrDeliveryNotFound if it isn't a delivery of the webhook with webhookID of userID.
+func (s *PGStorage) RedeliverDelivery(ctx context.Context, userID, webhookID, ID string, at time.Time) error {
+	result, err := s.conn.Exec(ctx, `UPDATE webhook_deliveries d SET status = 'pending', attempts = 0, next_attempt_at = $4,
+										last_error = NULL, response_status = NULL, delivered_at = NULL
+										FROM webhooks w
+										WHERE w.id = d.webhook_id AND d.id = $1 AND w.id = $2 AND w.user_id = $3`,
+		ID, webhookID, userID, at)
+	if err != nil {
+		s.logger.Error("pgstorage:RedeliverDelivery ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	if result.RowsAffected() == 0 {
+		return errs.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// scanDelivery scans a row of deliveryColumns followed by extra columns into extra.
+func scanDelivery(row pgx.Row, extra ...any) (models.Delivery, error) {
+	var d models.Delivery
+	var payload []byte
+	var status string
+	var lastError *string
+	var responseStatus *int
+	var deliveredAt *time.Time
+
+	dest := []any{&d.ID, &d.WebhookID, &d.UserID, &d.Event, &payload, &status, &d.Attempts, &d.NextAttemptAt,
+		&lastError, &responseStatus, &d.CreatedAt, &deliveredAt}
+
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return models.Delivery{}, err
+	}
+
+	d.Payload = payload
+	d.Status = models.DeliveryStatus(status)
+	d.LastError = deref(lastError)
+
+	if responseStatus != nil {
+		d.ResponseStatus = *responseStatus
+	}
+
+	if deliveredAt != nil {
+		d.DeliveredAt = *deliveredAt
+	}
+
+	return d, nil
+}
@@ -0,0 +1,207 @@
+package pgstorage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+// editorRoles are the workspace roles allowed to modify workspace links.
+var editorRoles = []string{string(models.RoleOwner), string(models.RoleEditor)}
+
+// CreateWorkspace creates a workspace and makes userID its owner. Returns ID of the workspace.
+func (s *PGStorage) CreateWorkspace(ctx context.Context, userID, name string) (string, error) {
+	ID := uuid.NewString()
+
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		s.logger.Error("pgstorage:CreateWorkspace Failed to start a transaction", zap.Error(err))
+		return "", errs.ErrInternalServerError
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `INSERT INTO workspaces (id, name, owner_id) VALUES ($1, $2, $3)`, ID, name, userID)
+	if err != nil {
+		s.logger.Error("pgstorage:CreateWorkspace ", zap.Error(err))
+		return "", errs.ErrInternalServerError
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role) VALUES ($1, $2, $3)`, ID, userID, models.RoleOwner)
+	if err != nil {
+		s.logger.Error("pgstorage:CreateWorkspace ", zap.Error(err))
+		return "", errs.ErrInternalServerError
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		s.logger.Error("pgstorage:CreateWorkspace ", zap.Error(err))
+		return "", errs.ErrInternalServerError
+	}
+
+	return ID, nil
+}
+
+// AddWorkspaceMember adds userID to a workspace or changes the role of an existing member.
+// Fails with errs.ErrLastOwner if userID is the only owner and role isn't owner.
+func (s *PGStorage) AddWorkspaceMember(ctx context.Context, workspaceID, userID string, role models.Role) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		s.logger.Error("pgstorage:AddWorkspaceMember Failed to start a transaction", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer tx.Rollback(ctx)
+
+	if role != models.RoleOwner {
+		err = s.checkLastOwner(ctx, tx, workspaceID, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO workspace_members (workspace_id, user_id, role)
+									VALUES ($1, $2, $3)
+									ON CONFLICT (workspace_id, user_id)
+									DO UPDATE SET role = EXCLUDED.role`, workspaceID, userID, role)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation {
+			return errs.ErrWorkspaceNotFound
+		}
+
+		s.logger.Error("pgstorage:AddWorkspaceMember ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		s.logger.Error("pgstorage:AddWorkspaceMember ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// RemoveWorkspaceMember removes userID from a workspace. Fails with errs.ErrLastOwner if userID is its only owner.
+func (s *PGStorage) RemoveWorkspaceMember(ctx context.Context, workspaceID, userID string) error {
+	tx, err := s.conn.Begin(ctx)
+	if err != nil {
+		s.logger.Error("pgstorage:RemoveWorkspaceMember Failed to start a transaction", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+	defer tx.Rollback(ctx)
+
+	err = s.checkLastOwner(ctx, tx, workspaceID, userID)
+	if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID)
+	if err != nil {
+		s.logger.Error("pgstorage:RemoveWorkspaceMember ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	if result.RowsAffected() == 0 {
+		return errs.ErrWorkspaceNotFound
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		s.logger.Error("pgstorage:RemoveWorkspaceMember ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// checkLastOwner returns errs.ErrLastOwner if userID is the only owner of a workspace. Owners stay locked
+// until tx ends, so owners demoting each other concurrently can't both succeed.
+func (s *PGStorage) checkLastOwner(ctx context.Context, tx pgx.Tx, workspaceID, userID string) error {
+	rows, err := tx.Query(ctx, `SELECT user_id FROM workspace_members
+									WHERE workspace_id = $1 AND role = $2
+									FOR UPDATE`, workspaceID, models.RoleOwner)
+	if err != nil {
+		s.logger.Error("pgstorage:checkLastOwner ", zap.Error(err))
+		return errs.ErrInternalServerError
+	}
+
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var owner string
+		err = rows.Scan(&owner)
+		if err != nil {
+			s.logger.Error("pgstorage:checkLastOwner Error in row", zap.Error(err))
+			return errs.ErrInternalServerError
+		}
+
+		owners = append(owners, owner)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:checkLastOwner Error in rows", zap.Error(rows.Err()))
+		return errs.ErrInternalServerError
+	}
+
+	if len(owners) == 1 && owners[0] == userID {
+		return errs.ErrLastOwner
+	}
+
+	return nil
+}
+
+// GetWorkspaceRole returns the role userID has in a workspace.
+func (s *PGStorage) GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (models.Role, error) {
+	var role string
+	err := s.conn.QueryRow(ctx, `SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`, workspaceID, userID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errs.ErrForbidden
+		}
+
+		s.logger.Error("pgstorage:GetWorkspaceRole ", zap.Error(err))
+		return "", errs.ErrInternalServerError
+	}
+
+	return models.Role(role), nil
+}
+
+// GetUserWorkspaces returns all workspaces userID is a member of.
+func (s *PGStorage) GetUserWorkspaces(ctx context.Context, userID string) ([]dto.Workspace, error) {
+	var result []dto.Workspace
+	rows, err := s.conn.Query(ctx, `SELECT w.id, w.name, m.role FROM workspaces w
+									JOIN workspace_members m ON m.workspace_id = w.id
+									WHERE m.user_id = $1`, userID)
+	if err != nil {
+		s.logger.Error("pgstorage:GetUserWorkspaces ", zap.Error(err))
+		return nil, errs.ErrInternalServerError
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workspace dto.Workspace
+		err = rows.Scan(&workspace.ID, &workspace.Name, &workspace.Role)
+		if err != nil {
+			s.logger.Error("pgstorage:GetUserWorkspaces Error in row", zap.Error(err))
+			continue
+		}
+
+		result = append(result, workspace)
+	}
+
+	if rows.Err() != nil {
+		s.logger.Error("pgstorage:GetUserWorkspaces Error in rows", zap.Error(rows.Err()))
+		return nil, errs.ErrInternalServerError
+	}
+
+	return result, nil
+}
@@ -2,9 +2,11 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/MukizuL/shortener/internal/config"
 	"github.com/MukizuL/shortener/internal/dto"
+	"github.com/MukizuL/shortener/internal/models"
 	"github.com/MukizuL/shortener/internal/storage/mapstorage"
 	"github.com/MukizuL/shortener/internal/storage/pgstorage"
 	"go.uber.org/fx"
@@ -13,14 +15,76 @@ import (
 //go:generate mockgen -source=storage.go -destination=mocks/storage.go -package=mockstorage
 
 type Repo interface {
-	CreateShortURL(ctx context.Context, userID, urlBase, fullURL string) (string, error)
-	BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest) ([]dto.BatchResponse, error)
-	GetLongURL(ctx context.Context, ID string) (string, error)
-	GetUserURLs(ctx context.Context, userID string) ([]dto.URLPair, error)
-	DeleteURLs(ctx context.Context, userID string, urls []string) error
+	// CreateShortURL creates a link on opts.Domain. Returns the existing short URL with errs.ErrDuplicate,
+	// if the URL was already shortened on that domain in the dedupe scope.
+	CreateShortURL(ctx context.Context, userID, urlBase, fullURL, canonicalURL string, opts models.URLOptions) (string, error)
+	// BatchCreateShortURL creates links for all items or none of them. Returns a result for every item in the same order,
+	// either created or existing, if the URL was already shortened in the dedupe scope.
+	BatchCreateShortURL(ctx context.Context, userID, urlBase string, data []dto.BatchRequest, opts models.URLOptions) ([]dto.BatchResponse, error)
+	// GetLongURL returns the link with ID on domain. The same ID may exist on several domains.
+	GetLongURL(ctx context.Context, domain, ID string) (models.Link, error)
+	ConsumeClick(ctx context.Context, domain, ID string) (int, error)
+	// GetUserURLs returns links of the user and of their workspaces on domains, on every domain if domains is empty.
+	GetUserURLs(ctx context.Context, userID string, domains []string) ([]dto.URLPair, error)
+	// WalkUserURLs calls fn for every URL GetUserURLs would return without loading them all at once.
+	// Stops at the first error returned by fn and returns it.
+	WalkUserURLs(ctx context.Context, userID string, domains []string, fn func(dto.URLPair) error) error
+	DeleteURLs(ctx context.Context, userID, domain string, urls []string) error
+	// FindLinks returns links of every user matching q, newest first. Deleted links are not included.
+	FindLinks(ctx context.Context, q models.LinkQuery) ([]models.Link, error)
+	// DisableLinks sets the takedown of every link matching q, the zero Takedown enables them again.
+	// Returns the number of links changed.
+	DisableLinks(ctx context.Context, q models.LinkQuery, t models.Takedown) (int, error)
+	// CreateReport queues r, unless its reporter already has a pending report about the link. Returns the number
+	// of distinct reporters with pending reports about the link filed since since.
+	CreateReport(ctx context.Context, r models.Report, since time.Time) (int, error)
+	// GetReports returns reports with status, oldest first.
+	GetReports(ctx context.Context, status models.ReportStatus) ([]models.Report, error)
+	GetReport(ctx context.Context, ID string) (models.Report, error)
+	// ResolveReports sets status of every pending report about the link with ID on domain.
+	// Returns the number of reports changed.
+	ResolveReports(ctx context.Context, domain, ID string, status models.ReportStatus) (int, error)
 	GetStats(ctx context.Context) (int, int, error)
 	OffloadStorage(ctx context.Context, filepath string) error
 	Ping(ctx context.Context) error
+
+	CreateWorkspace(ctx context.Context, userID, name string) (string, error)
+	AddWorkspaceMember(ctx context.Context, workspaceID, userID string, role models.Role) error
+	RemoveWorkspaceMember(ctx context.Context, workspaceID, userID string) error
+	GetWorkspaceRole(ctx context.Context, workspaceID, userID string) (models.Role, error)
+	GetUserWorkspaces(ctx context.Context, userID string) ([]dto.Workspace, error)
+}
+
+// AuditLog is the append-only log of changes made through the API, kept by the same storage as links.
+type AuditLog interface {
+	// AppendAudit adds events to the audit log. Events are never changed or removed.
+	AppendAudit(ctx context.Context, events ...models.AuditEvent) error
+	// QueryAudit returns audit events matching q, newest first.
+	QueryAudit(ctx context.Context, q models.AuditQuery) ([]models.AuditEvent, error)
+}
+
+// Webhooks keeps webhook subscriptions and the outbox of their deliveries, in the same storage as links.
+type Webhooks interface {
+	CreateWebhook(ctx context.Context, w models.Webhook) error
+	// GetWebhooks returns webhooks of userID, oldest first.
+	GetWebhooks(ctx context.Context, userID string) ([]models.Webhook, error)
+	// DeleteWebhook deletes the webhook with ID of userID along with its deliveries.
+	DeleteWebhook(ctx context.Context, userID, ID string) error
+	// EnqueueDeliveries queues a delivery of payload, due at, to every webhook of userID subscribed to event.
+	// Returns the number of deliveries queued.
+	EnqueueDeliveries(ctx context.Context, userID, event string, payload []byte, at time.Time) (int, error)
+	// ClaimDeliveries returns up to limit pending deliveries due by now with URL and Secret of their webhooks,
+	// and postpones them until now+lease, so that they aren't claimed again while they are being sent.
+	ClaimDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]models.Delivery, error)
+	// UpdateDelivery saves the outcome of an attempt: Status, Attempts, NextAttemptAt, LastError,
+	// ResponseStatus and DeliveredAt of d.
+	UpdateDelivery(ctx context.Context, d models.Delivery) error
+	// GetDeliveries returns up to limit deliveries of the webhook with webhookID of userID with status,
+	// any status if it is empty, newest first.
+	GetDeliveries(ctx context.Context, userID, webhookID string, status models.DeliveryStatus, limit int) ([]models.Delivery, error)
+	// RedeliverDelivery queues the delivery with ID of the webhook with webhookID of userID again, due at
+	// and with no attempts made, whatever its status.
+	RedeliverDelivery(ctx context.Context, userID, webhookID, ID string, at time.Time) error
 }
 
 type Repository struct {
@@ -35,6 +99,22 @@ func newRepository(cfg *config.Config, m *mapstorage.MapStorage, p *pgstorage.PG
 	return p
 }
 
+func newAuditLog(cfg *config.Config, m *mapstorage.MapStorage, p *pgstorage.PGStorage) AuditLog {
+	if cfg.DSN == "" {
+		return m
+	}
+
+	return p
+}
+
+func newWebhooks(cfg *config.Config, m *mapstorage.MapStorage, p *pgstorage.PGStorage) Webhooks {
+	if cfg.DSN == "" {
+		return m
+	}
+
+	return p
+}
+
 func Provide() fx.Option {
-	return fx.Provide(newRepository)
+	return fx.Provide(newRepository, newAuditLog, newWebhooks)
 }
@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/config"
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/migration"
+	"github.com/MukizuL/shortener/internal/models"
+	"github.com/MukizuL/shortener/internal/storage"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+)
+
+const (
+	// PollInterval is how often the outbox is checked for due deliveries.
+	PollInterval = time.Second
+	// BatchSize is the number of deliveries claimed and sent at once.
+	BatchSize = 16
+	// maxResponseBody caps the part of a response read before the connection is reused.
+	maxResponseBody = 64 << 10
+)
+
+// Dispatcher sends due deliveries from the outbox. Several instances may share an outbox,
+// a claimed delivery isn't claimed again until its lease runs out.
+type Dispatcher struct {
+	store       storage.Webhooks
+	client      *http.Client
+	maxAttempts int
+	lease       time.Duration
+	logger      *zap.Logger
+}
+
+// New returns a Dispatcher sending deliveries with client, dead-lettering them after maxAttempts failed attempts.
+func New(store storage.Webhooks, client *http.Client, maxAttempts int, logger *zap.Logger) *Dispatcher {
+	return &Dispatcher{
+		store:       store,
+		client:      client,
+		maxAttempts: maxAttempts,
+		lease:       client.Timeout + time.Minute,
+		logger:      logger,
+	}
+}
+
+// newDispatcher runs a Dispatcher for the lifetime of the app once migrations have created the outbox.
+func newDispatcher(lc fx.Lifecycle, cfg *config.Config, store storage.Webhooks, _ *migration.Migrator, logger *zap.Logger) *Dispatcher {
+	d := New(store, NewClient(cfg.WebhookTimeout, cfg.WebhookAllowPrivate), cfg.WebhookMaxAttempts, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				d.Run(ctx)
+			}()
+
+			return nil
+		},
+		OnStop: func(stopCtx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-stopCtx.Done():
+				return stopCtx.Err()
+			}
+		},
+	})
+
+	return d
+}
+
+func Provide() fx.Option {
+	return fx.Provide(newDispatcher)
+}
+
+// NewClient returns a client to send deliveries with. Unless allowPrivate, it refuses to connect to loopback,
+// private and link-local addresses, whatever the webhook's host resolves to at the time. Redirects aren't followed.
+func NewClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = publicOnly
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would connect on our behalf, past the address check.
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func publicOnly(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := addrPort.Addr().Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return errs.ErrPrivateAddress
+	}
+
+	return nil
+}
+
+// Run sends due deliveries every PollInterval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Keep going while full batches are claimed, the outbox may have a backlog.
+		for ctx.Err() == nil {
+			n, err := d.DeliverDue(ctx)
+			if err != nil || n < BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// DeliverDue claims a batch of due deliveries and sends them concurrently. Returns the number of deliveries claimed.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.store.ClaimDeliveries(ctx, time.Now(), BatchSize, d.lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			delivery = d.send(ctx, delivery)
+			// Interrupted by shutdown, it is sent again once its lease runs out.
+			if ctx.Err() != nil {
+				return
+			}
+
+			err := d.store.UpdateDelivery(ctx, delivery)
+			if err != nil {
+				d.logger.Error("Error saving webhook delivery", zap.String("delivery_id", delivery.ID), zap.Error(err))
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// send makes an attempt to deliver and returns the delivery with its outcome.
+func (d *Dispatcher) send(ctx context.Context, delivery models.Delivery) models.Delivery {
+	delivery.Attempts++
+
+	status, err := d.post(ctx, delivery)
+	delivery.ResponseStatus = status
+
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = time.Now()
+		delivery.LastError = ""
+
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = models.DeliveryDead
+		d.logger.Warn("Webhook delivery is dead", zap.String("delivery_id", delivery.ID),
+			zap.String("webhook_id", delivery.WebhookID), zap.Int("attempts", delivery.Attempts), zap.Error(err))
+
+		return delivery
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(Backoff(delivery.Attempts))
+
+	return delivery
+}
+
+// post sends the payload of delivery and returns the response status, 0 if there was no response.
+// Statuses other than 2xx are errors.
+func (d *Dispatcher) post(ctx context.Context, delivery models.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, now, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,83 @@
+// Package webhook sends events queued in the webhook outbox to the subscribers' URLs. Every request is signed
+// with the webhook's secret, failed deliveries are retried with exponential backoff and dead-lettered after
+// the last attempt.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of a delivery request.
+const (
+	// SignatureHeader holds "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret.
+	SignatureHeader = "X-Webhook-Signature"
+	// TimestampHeader holds the Unix time in seconds the request was signed at. Receivers should reject old
+	// timestamps to stop replays.
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+	// DeliveryHeader holds the delivery ID, the same for every attempt and redelivery, to deduplicate on.
+	DeliveryHeader = "X-Webhook-Delivery"
+)
+
+const (
+	// BaseBackoff is the delay after the first failed attempt, it doubles after every further one.
+	BaseBackoff = time.Minute
+	// MaxBackoff caps the delay between attempts.
+	MaxBackoff = 6 * time.Hour
+)
+
+// NewSecret returns a random secret to sign deliveries of a new webhook with.
+func NewSecret() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+
+	return "whsec_" + hex.EncodeToString(b)
+}
+
+// Sign returns the signature of body sent at t, the value of SignatureHeader.
+func Sign(secret string, t time.Time, body []byte) string {
+	return "sha256=" + hex.EncodeToString(mac(secret, strconv.FormatInt(t.Unix(), 10), body))
+}
+
+// Verify reports whether signature is a valid signature of body sent at timestamp, the values of
+// SignatureHeader and TimestampHeader.
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	sum, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+
+	return hmac.Equal(sum, mac(secret, timestamp, body))
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+
+	return h.Sum(nil)
+}
+
+// Backoff returns the delay before the next attempt after attempts failed ones.
+func Backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		return 0
+	}
+
+	d := BaseBackoff
+	for range attempts - 1 {
+		d *= 2
+		if d >= MaxBackoff {
+			return MaxBackoff
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,190 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/MukizuL/shortener/internal/errs"
+	"github.com/MukizuL/shortener/internal/models"
+	mockstorage "github.com/MukizuL/shortener/internal/storage/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"go.uber.org/zap"
+)
+
+const secret = "whsec_test"
+
+func TestSign(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	body := []byte(`{"event":"link.created"}`)
+
+	signature := Sign(secret, at, body)
+
+	assert.True(t, Verify(secret, "1700000000", body, signature))
+	assert.False(t, Verify(secret, "1700000001", body, signature), "timestamp is signed")
+	assert.False(t, Verify(secret, "1700000000", []byte(`{"event":"link.deleted"}`), signature), "body is signed")
+	assert.False(t, Verify("whsec_other", "1700000000", body, signature))
+	assert.False(t, Verify(secret, "1700000000", body, signature[len("sha256="):]), "prefix is required")
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 0},
+		{attempts: 1, want: time.Minute},
+		{attempts: 2, want: 2 * time.Minute},
+		{attempts: 4, want: 8 * time.Minute},
+		{attempts: 9, want: 256 * time.Minute},
+		{attempts: 10, want: MaxBackoff},
+		{attempts: 100, want: MaxBackoff},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.attempts), func(t *testing.T) {
+			assert.Equal(t, tt.want, Backoff(tt.attempts))
+		})
+	}
+}
+
+func TestDispatcher_DeliverDue(t *testing.T) {
+	payload := []byte(`{"event":"link.created","link":{"id":"qxDvSD"}}`)
+
+	tests := []struct {
+		name         string
+		attempts     int // made before this one
+		status       int // answered by the receiver, 0 closes the receiver
+		wantStatus   models.DeliveryStatus
+		wantResponse int
+		wantBackoff  time.Duration
+	}{
+		{
+			name:         "Delivered",
+			status:       http.StatusNoContent,
+			wantStatus:   models.DeliveryDelivered,
+			wantResponse: http.StatusNoContent,
+		},
+		{
+			name:         "Receiver fails",
+			attempts:     1,
+			status:       http.StatusInternalServerError,
+			wantStatus:   models.DeliveryPending,
+			wantResponse: http.StatusInternalServerError,
+			wantBackoff:  2 * time.Minute,
+		},
+		{
+			name:        "Receiver is down",
+			wantStatus:  models.DeliveryPending,
+			wantBackoff: time.Minute,
+		},
+		{
+			name:         "Redirects aren't followed",
+			status:       http.StatusFound,
+			wantStatus:   models.DeliveryPending,
+			wantResponse: http.StatusFound,
+			wantBackoff:  time.Minute,
+		},
+		{
+			name:         "Last attempt fails",
+			attempts:     2,
+			status:       http.StatusBadGateway,
+			wantStatus:   models.DeliveryDead,
+			wantResponse: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			received := make(chan *http.Request, 1)
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.Equal(t, payload, body)
+				assert.True(t, Verify(secret, r.Header.Get(TimestampHeader), body, r.Header.Get(SignatureHeader)))
+
+				received <- r
+				if tt.status == http.StatusFound {
+					http.Redirect(w, r, "/elsewhere", tt.status)
+					return
+				}
+
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			if tt.status == 0 {
+				receiver.Close()
+			}
+
+			delivery := models.Delivery{
+				ID:        "8a6e0804-2bd0-4672-b79d-d97b3c1c1337",
+				WebhookID: "4f3c2b1a-0e9d-4c8b-a7f6-e5d4c3b2a190",
+				Event:     models.WebhookLinkCreated,
+				Payload:   payload,
+				Status:    models.DeliveryPending,
+				Attempts:  tt.attempts,
+				URL:       receiver.URL,
+				Secret:    secret,
+			}
+
+			var saved models.Delivery
+			mockStore := mockstorage.NewMockWebhooks(ctrl)
+			mockStore.EXPECT().ClaimDeliveries(gomock.Any(), gomock.Any(), BatchSize, gomock.Any()).Return([]models.Delivery{delivery}, nil)
+			mockStore.EXPECT().UpdateDelivery(gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, d models.Delivery) error {
+					saved = d
+					return nil
+				})
+
+			d := New(mockStore, NewClient(time.Second, true), 3, zap.NewNop())
+
+			n, err := d.DeliverDue(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			if tt.status != 0 {
+				r := <-received
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, models.WebhookLinkCreated, r.Header.Get(EventHeader))
+				assert.Equal(t, delivery.ID, r.Header.Get(DeliveryHeader))
+			}
+
+			assert.Equal(t, tt.wantStatus, saved.Status)
+			assert.Equal(t, tt.attempts+1, saved.Attempts)
+			assert.Equal(t, tt.wantResponse, saved.ResponseStatus)
+
+			if tt.wantStatus == models.DeliveryDelivered {
+				assert.Empty(t, saved.LastError)
+				assert.WithinDuration(t, time.Now(), saved.DeliveredAt, time.Second)
+			} else {
+				assert.NotEmpty(t, saved.LastError)
+			}
+
+			if tt.wantBackoff != 0 {
+				assert.WithinDuration(t, time.Now().Add(tt.wantBackoff), saved.NextAttemptAt, time.Second)
+			}
+		})
+	}
+}
+
+func TestNewClient_PrivateAddress(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer receiver.Close()
+
+	_, err := NewClient(time.Second, false).Post(receiver.URL, "application/json", nil)
+	assert.ErrorIs(t, err, errs.ErrPrivateAddress)
+
+	resp, err := NewClient(time.Second, true).Post(receiver.URL, "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+}
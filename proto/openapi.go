@@ -0,0 +1,8 @@
+package proto
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI document of the REST gateway generated from the HTTP annotations.
+//
+//go:embed url.swagger.json
+var OpenAPI []byte
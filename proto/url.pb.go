@@ -7,6 +7,7 @@
 package proto
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	reflect "reflect"
@@ -23,8 +24,14 @@ const (
 
 // Simple create short URL
 type CreateShortURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OriginalUrl   string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	OriginalUrl  string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	WorkspaceId  string                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	Password     string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	MaxClicks    int32                  `protobuf:"varint,4,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	RedirectType int32                  `protobuf:"varint,5,opt,name=redirect_type,json=redirectType,proto3" json:"redirect_type,omitempty"`
+	// Short domain to create the link on, the domain the call was made to if empty
+	Domain        string `protobuf:"bytes,6,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +73,41 @@ func (x *CreateShortURLRequest) GetOriginalUrl() string {
 	return ""
 }
 
+func (x *CreateShortURLRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateShortURLRequest) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetRedirectType() int32 {
+	if x != nil {
+		return x.RedirectType
+	}
+	return 0
+}
+
+func (x *CreateShortURLRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
 type CreateShortURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
@@ -175,6 +217,10 @@ type BatchResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CorrelationId string                 `protobuf:"bytes,1,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
 	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// created, existing or invalid
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// Reason an invalid item was rejected
+	Error         string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -223,9 +269,25 @@ func (x *BatchResponse) GetShortUrl() string {
 	return ""
 }
 
+func (x *BatchResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BatchResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 type CreateBatchShortURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Batch         []*BatchRequest        `protobuf:"bytes,1,rep,name=batch,proto3" json:"batch,omitempty"`
+	WorkspaceId   string                 `protobuf:"bytes,2,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	Domain        string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -267,6 +329,20 @@ func (x *CreateBatchShortURLRequest) GetBatch() []*BatchRequest {
 	return nil
 }
 
+func (x *CreateBatchShortURLRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *CreateBatchShortURLRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
 type CreateBatchShortURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Batch         []*BatchResponse       `protobuf:"bytes,1,rep,name=batch,proto3" json:"batch,omitempty"`
@@ -323,6 +399,7 @@ func (x *CreateBatchShortURLResponse) GetAccessToken() string {
 type GetOriginalURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -364,9 +441,17 @@ func (x *GetOriginalURLRequest) GetShortUrl() string {
 	return ""
 }
 
+func (x *GetOriginalURLRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
 type GetOriginalURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OriginalUrl   string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	RedirectType  int32                  `protobuf:"varint,2,opt,name=redirect_type,json=redirectType,proto3" json:"redirect_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -408,11 +493,20 @@ func (x *GetOriginalURLResponse) GetOriginalUrl() string {
 	return ""
 }
 
+func (x *GetOriginalURLResponse) GetRedirectType() int32 {
+	if x != nil {
+		return x.RedirectType
+	}
+	return 0
+}
+
 // Get user urls
 type URLPair struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OriginalUrl   string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
-	ShortUrl      string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OriginalUrl string                 `protobuf:"bytes,1,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	ShortUrl    string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Short domain of the link, empty if no short domains are configured
+	Domain        string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -461,8 +555,17 @@ func (x *URLPair) GetShortUrl() string {
 	return ""
 }
 
+func (x *URLPair) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
 type GetUserURLRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Lists links on this short domain only, on every domain if empty
+	Domain        string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -497,6 +600,13 @@ func (*GetUserURLRequest) Descriptor() ([]byte, []int) {
 	return file_proto_url_proto_rawDescGZIP(), []int{9}
 }
 
+func (x *GetUserURLRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
 type GetUserURLResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Pairs         []*URLPair             `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
@@ -549,6 +659,121 @@ func (x *GetUserURLResponse) GetAccessToken() string {
 	return ""
 }
 
+// Watch clicks
+type WatchClicksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchClicksRequest) Reset() {
+	*x = WatchClicksRequest{}
+	mi := &file_proto_url_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchClicksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchClicksRequest) ProtoMessage() {}
+
+func (x *WatchClicksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchClicksRequest.ProtoReflect.Descriptor instead.
+func (*WatchClicksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{11}
+}
+
+type ClickEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Unix time in milliseconds
+	Timestamp int64  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Referer   string `protobuf:"bytes,3,opt,name=referer,proto3" json:"referer,omitempty"`
+	UserAgent string `protobuf:"bytes,4,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// Address of the visitor, empty if unknown
+	ClientIp      string `protobuf:"bytes,5,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClickEvent) Reset() {
+	*x = ClickEvent{}
+	mi := &file_proto_url_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClickEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClickEvent) ProtoMessage() {}
+
+func (x *ClickEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClickEvent.ProtoReflect.Descriptor instead.
+func (*ClickEvent) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ClickEvent) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *ClickEvent) GetReferer() string {
+	if x != nil {
+		return x.Referer
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *ClickEvent) GetClientIp() string {
+	if x != nil {
+		return x.ClientIp
+	}
+	return ""
+}
+
 // Delete short URL
 type DeleteShortURLRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -559,7 +784,7 @@ type DeleteShortURLRequest struct {
 
 func (x *DeleteShortURLRequest) Reset() {
 	*x = DeleteShortURLRequest{}
-	mi := &file_proto_url_proto_msgTypes[11]
+	mi := &file_proto_url_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -571,7 +796,7 @@ func (x *DeleteShortURLRequest) String() string {
 func (*DeleteShortURLRequest) ProtoMessage() {}
 
 func (x *DeleteShortURLRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_url_proto_msgTypes[11]
+	mi := &file_proto_url_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -584,7 +809,7 @@ func (x *DeleteShortURLRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteShortURLRequest.ProtoReflect.Descriptor instead.
 func (*DeleteShortURLRequest) Descriptor() ([]byte, []int) {
-	return file_proto_url_proto_rawDescGZIP(), []int{11}
+	return file_proto_url_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *DeleteShortURLRequest) GetShortUrls() []string {
@@ -603,7 +828,7 @@ type DeleteShortURLResponse struct {
 
 func (x *DeleteShortURLResponse) Reset() {
 	*x = DeleteShortURLResponse{}
-	mi := &file_proto_url_proto_msgTypes[12]
+	mi := &file_proto_url_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -615,7 +840,7 @@ func (x *DeleteShortURLResponse) String() string {
 func (*DeleteShortURLResponse) ProtoMessage() {}
 
 func (x *DeleteShortURLResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_url_proto_msgTypes[12]
+	mi := &file_proto_url_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -628,7 +853,7 @@ func (x *DeleteShortURLResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteShortURLResponse.ProtoReflect.Descriptor instead.
 func (*DeleteShortURLResponse) Descriptor() ([]byte, []int) {
-	return file_proto_url_proto_rawDescGZIP(), []int{12}
+	return file_proto_url_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeleteShortURLResponse) GetAccessToken() string {
@@ -647,7 +872,7 @@ type GetStatsRequest struct {
 
 func (x *GetStatsRequest) Reset() {
 	*x = GetStatsRequest{}
-	mi := &file_proto_url_proto_msgTypes[13]
+	mi := &file_proto_url_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -659,7 +884,7 @@ func (x *GetStatsRequest) String() string {
 func (*GetStatsRequest) ProtoMessage() {}
 
 func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_url_proto_msgTypes[13]
+	mi := &file_proto_url_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -672,7 +897,7 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
 func (*GetStatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_url_proto_rawDescGZIP(), []int{13}
+	return file_proto_url_proto_rawDescGZIP(), []int{15}
 }
 
 type GetStatsResponse struct {
@@ -685,7 +910,7 @@ type GetStatsResponse struct {
 
 func (x *GetStatsResponse) Reset() {
 	*x = GetStatsResponse{}
-	mi := &file_proto_url_proto_msgTypes[14]
+	mi := &file_proto_url_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -697,7 +922,7 @@ func (x *GetStatsResponse) String() string {
 func (*GetStatsResponse) ProtoMessage() {}
 
 func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_url_proto_msgTypes[14]
+	mi := &file_proto_url_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -710,7 +935,7 @@ func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
 func (*GetStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_url_proto_rawDescGZIP(), []int{14}
+	return file_proto_url_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetStatsResponse) GetUrls() int32 {
@@ -727,56 +952,2108 @@ func (x *GetStatsResponse) GetUsers() int32 {
 	return 0
 }
 
-var File_proto_url_proto protoreflect.FileDescriptor
+// Create workspace
+type CreateWorkspaceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_proto_url_proto_rawDesc = "" +
-	"\n" +
-	"\x0fproto/url.proto\x12\tshortener\":\n" +
-	"\x15CreateShortURLRequest\x12!\n" +
-	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\"X\n" +
-	"\x16CreateShortURLResponse\x12\x1b\n" +
-	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
-	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"X\n" +
-	"\fBatchRequest\x12%\n" +
-	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12!\n" +
-	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\"S\n" +
-	"\rBatchResponse\x12%\n" +
-	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12\x1b\n" +
-	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\"K\n" +
-	"\x1aCreateBatchShortURLRequest\x12-\n" +
-	"\x05batch\x18\x01 \x03(\v2\x17.shortener.BatchRequestR\x05batch\"p\n" +
-	"\x1bCreateBatchShortURLResponse\x12.\n" +
-	"\x05batch\x18\x01 \x03(\v2\x18.shortener.BatchResponseR\x05batch\x12!\n" +
-	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"4\n" +
-	"\x15GetOriginalURLRequest\x12\x1b\n" +
-	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\";\n" +
-	"\x16GetOriginalURLResponse\x12!\n" +
-	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\"I\n" +
-	"\aURLPair\x12!\n" +
-	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12\x1b\n" +
-	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\"\x13\n" +
-	"\x11GetUserURLRequest\"a\n" +
-	"\x12GetUserURLResponse\x12(\n" +
-	"\x05pairs\x18\x01 \x03(\v2\x12.shortener.URLPairR\x05pairs\x12!\n" +
-	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"6\n" +
-	"\x15DeleteShortURLRequest\x12\x1d\n" +
-	"\n" +
-	"short_urls\x18\x01 \x03(\tR\tshortUrls\";\n" +
-	"\x16DeleteShortURLResponse\x12!\n" +
-	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"\x11\n" +
-	"\x0fGetStatsRequest\"<\n" +
-	"\x10GetStatsResponse\x12\x12\n" +
-	"\x04urls\x18\x01 \x01(\x05R\x04urls\x12\x14\n" +
-	"\x05users\x18\x02 \x01(\x05R\x05users2\x87\x04\n" +
-	"\tShortener\x12Q\n" +
+func (x *CreateWorkspaceRequest) Reset() {
+	*x = CreateWorkspaceRequest{}
+	mi := &file_proto_url_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkspaceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkspaceRequest) ProtoMessage() {}
+
+func (x *CreateWorkspaceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkspaceRequest.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *CreateWorkspaceRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateWorkspaceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	AccessToken   string                 `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWorkspaceResponse) Reset() {
+	*x = CreateWorkspaceResponse{}
+	mi := &file_proto_url_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWorkspaceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWorkspaceResponse) ProtoMessage() {}
+
+func (x *CreateWorkspaceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWorkspaceResponse.ProtoReflect.Descriptor instead.
+func (*CreateWorkspaceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateWorkspaceResponse) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *CreateWorkspaceResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+// Add workspace member
+type AddWorkspaceMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWorkspaceMemberRequest) Reset() {
+	*x = AddWorkspaceMemberRequest{}
+	mi := &file_proto_url_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWorkspaceMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWorkspaceMemberRequest) ProtoMessage() {}
+
+func (x *AddWorkspaceMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWorkspaceMemberRequest.ProtoReflect.Descriptor instead.
+func (*AddWorkspaceMemberRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddWorkspaceMemberRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *AddWorkspaceMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AddWorkspaceMemberRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type AddWorkspaceMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddWorkspaceMemberResponse) Reset() {
+	*x = AddWorkspaceMemberResponse{}
+	mi := &file_proto_url_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddWorkspaceMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddWorkspaceMemberResponse) ProtoMessage() {}
+
+func (x *AddWorkspaceMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddWorkspaceMemberResponse.ProtoReflect.Descriptor instead.
+func (*AddWorkspaceMemberResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AddWorkspaceMemberResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+// Remove workspace member
+type RemoveWorkspaceMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveWorkspaceMemberRequest) Reset() {
+	*x = RemoveWorkspaceMemberRequest{}
+	mi := &file_proto_url_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWorkspaceMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWorkspaceMemberRequest) ProtoMessage() {}
+
+func (x *RemoveWorkspaceMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWorkspaceMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveWorkspaceMemberRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RemoveWorkspaceMemberRequest) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *RemoveWorkspaceMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RemoveWorkspaceMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccessToken   string                 `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveWorkspaceMemberResponse) Reset() {
+	*x = RemoveWorkspaceMemberResponse{}
+	mi := &file_proto_url_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveWorkspaceMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveWorkspaceMemberResponse) ProtoMessage() {}
+
+func (x *RemoveWorkspaceMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveWorkspaceMemberResponse.ProtoReflect.Descriptor instead.
+func (*RemoveWorkspaceMemberResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RemoveWorkspaceMemberResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+// Get workspaces
+type GetWorkspacesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkspacesRequest) Reset() {
+	*x = GetWorkspacesRequest{}
+	mi := &file_proto_url_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkspacesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkspacesRequest) ProtoMessage() {}
+
+func (x *GetWorkspacesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkspacesRequest.ProtoReflect.Descriptor instead.
+func (*GetWorkspacesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{23}
+}
+
+type Workspace struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Workspace) Reset() {
+	*x = Workspace{}
+	mi := &file_proto_url_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Workspace) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Workspace) ProtoMessage() {}
+
+func (x *Workspace) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Workspace.ProtoReflect.Descriptor instead.
+func (*Workspace) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *Workspace) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Workspace) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Workspace) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type GetWorkspacesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workspaces    []*Workspace           `protobuf:"bytes,1,rep,name=workspaces,proto3" json:"workspaces,omitempty"`
+	AccessToken   string                 `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWorkspacesResponse) Reset() {
+	*x = GetWorkspacesResponse{}
+	mi := &file_proto_url_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWorkspacesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWorkspacesResponse) ProtoMessage() {}
+
+func (x *GetWorkspacesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWorkspacesResponse.ProtoReflect.Descriptor instead.
+func (*GetWorkspacesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetWorkspacesResponse) GetWorkspaces() []*Workspace {
+	if x != nil {
+		return x.Workspaces
+	}
+	return nil
+}
+
+func (x *GetWorkspacesResponse) GetAccessToken() string {
+	if x != nil {
+		return x.AccessToken
+	}
+	return ""
+}
+
+// Get QR code
+type GetQRCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl      string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Format        string                 `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	Size          int32                  `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Level         string                 `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQRCodeRequest) Reset() {
+	*x = GetQRCodeRequest{}
+	mi := &file_proto_url_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQRCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQRCodeRequest) ProtoMessage() {}
+
+func (x *GetQRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQRCodeRequest.ProtoReflect.Descriptor instead.
+func (*GetQRCodeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *GetQRCodeRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *GetQRCodeRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *GetQRCodeRequest) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type GetQRCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Image         []byte                 `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Etag          string                 `protobuf:"bytes,3,opt,name=etag,proto3" json:"etag,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetQRCodeResponse) Reset() {
+	*x = GetQRCodeResponse{}
+	mi := &file_proto_url_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetQRCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetQRCodeResponse) ProtoMessage() {}
+
+func (x *GetQRCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetQRCodeResponse.ProtoReflect.Descriptor instead.
+func (*GetQRCodeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetQRCodeResponse) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *GetQRCodeResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *GetQRCodeResponse) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+// REST mappings are served by the gateway under /v1. Client and bidirectional streams are gRPC only.
+// Link of any user as moderators see it
+type AdminLink struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl    string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain      string                 `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	OriginalUrl string                 `protobuf:"bytes,3,opt,name=original_url,json=originalUrl,proto3" json:"original_url,omitempty"`
+	UserId      string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	WorkspaceId string                 `protobuf:"bytes,5,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// Unix time in milliseconds
+	CreatedAt  int64 `protobuf:"varint,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Protected  bool  `protobuf:"varint,7,opt,name=protected,proto3" json:"protected,omitempty"`
+	MaxClicks  int32 `protobuf:"varint,8,opt,name=max_clicks,json=maxClicks,proto3" json:"max_clicks,omitempty"`
+	ClicksLeft int32 `protobuf:"varint,9,opt,name=clicks_left,json=clicksLeft,proto3" json:"clicks_left,omitempty"`
+	// 410 or 451 for disabled links, 0 otherwise
+	DisabledStatus int32  `protobuf:"varint,10,opt,name=disabled_status,json=disabledStatus,proto3" json:"disabled_status,omitempty"`
+	DisabledReason string `protobuf:"bytes,11,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AdminLink) Reset() {
+	*x = AdminLink{}
+	mi := &file_proto_url_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminLink) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminLink) ProtoMessage() {}
+
+func (x *AdminLink) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminLink.ProtoReflect.Descriptor instead.
+func (*AdminLink) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AdminLink) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *AdminLink) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *AdminLink) GetOriginalUrl() string {
+	if x != nil {
+		return x.OriginalUrl
+	}
+	return ""
+}
+
+func (x *AdminLink) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AdminLink) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *AdminLink) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *AdminLink) GetProtected() bool {
+	if x != nil {
+		return x.Protected
+	}
+	return false
+}
+
+func (x *AdminLink) GetMaxClicks() int32 {
+	if x != nil {
+		return x.MaxClicks
+	}
+	return 0
+}
+
+func (x *AdminLink) GetClicksLeft() int32 {
+	if x != nil {
+		return x.ClicksLeft
+	}
+	return 0
+}
+
+func (x *AdminLink) GetDisabledStatus() int32 {
+	if x != nil {
+		return x.DisabledStatus
+	}
+	return 0
+}
+
+func (x *AdminLink) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+// Find links of every user, at least one of short_url, destination, host and user_id must be set
+type FindLinksRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Short domain of the link, any if empty
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// Original URL, matched as given and in canonical form
+	Destination string `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	// Host of the original URL, subdomains included
+	Host          string `protobuf:"bytes,4,opt,name=host,proto3" json:"host,omitempty"`
+	UserId        string `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindLinksRequest) Reset() {
+	*x = FindLinksRequest{}
+	mi := &file_proto_url_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindLinksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindLinksRequest) ProtoMessage() {}
+
+func (x *FindLinksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindLinksRequest.ProtoReflect.Descriptor instead.
+func (*FindLinksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *FindLinksRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *FindLinksRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *FindLinksRequest) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *FindLinksRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *FindLinksRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type FindLinksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Links         []*AdminLink           `protobuf:"bytes,1,rep,name=links,proto3" json:"links,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindLinksResponse) Reset() {
+	*x = FindLinksResponse{}
+	mi := &file_proto_url_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindLinksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindLinksResponse) ProtoMessage() {}
+
+func (x *FindLinksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindLinksResponse.ProtoReflect.Descriptor instead.
+func (*FindLinksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *FindLinksResponse) GetLinks() []*AdminLink {
+	if x != nil {
+		return x.Links
+	}
+	return nil
+}
+
+// Disable a link, visitors get status and see reason
+type DisableLinkRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Short domain of the link, the default one if empty
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// 410 or 451, 410 if omitted
+	Status        int32  `protobuf:"varint,3,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableLinkRequest) Reset() {
+	*x = DisableLinkRequest{}
+	mi := &file_proto_url_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableLinkRequest) ProtoMessage() {}
+
+func (x *DisableLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableLinkRequest.ProtoReflect.Descriptor instead.
+func (*DisableLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DisableLinkRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *DisableLinkRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *DisableLinkRequest) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *DisableLinkRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DisableLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableLinkResponse) Reset() {
+	*x = DisableLinkResponse{}
+	mi := &file_proto_url_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableLinkResponse) ProtoMessage() {}
+
+func (x *DisableLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableLinkResponse.ProtoReflect.Descriptor instead.
+func (*DisableLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{32}
+}
+
+// Enable a disabled link again
+type EnableLinkRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	// Short domain of the link, the default one if empty
+	Domain        string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableLinkRequest) Reset() {
+	*x = EnableLinkRequest{}
+	mi := &file_proto_url_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableLinkRequest) ProtoMessage() {}
+
+func (x *EnableLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableLinkRequest.ProtoReflect.Descriptor instead.
+func (*EnableLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *EnableLinkRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *EnableLinkRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+type EnableLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableLinkResponse) Reset() {
+	*x = EnableLinkResponse{}
+	mi := &file_proto_url_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableLinkResponse) ProtoMessage() {}
+
+func (x *EnableLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableLinkResponse.ProtoReflect.Descriptor instead.
+func (*EnableLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{34}
+}
+
+// Disable every link pointing at host or its subdomains
+type DisableHostRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Host  string                 `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
+	// 410 or 451, 410 if omitted
+	Status        int32  `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableHostRequest) Reset() {
+	*x = DisableHostRequest{}
+	mi := &file_proto_url_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableHostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableHostRequest) ProtoMessage() {}
+
+func (x *DisableHostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableHostRequest.ProtoReflect.Descriptor instead.
+func (*DisableHostRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DisableHostRequest) GetHost() string {
+	if x != nil {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *DisableHostRequest) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *DisableHostRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DisableHostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Disabled      int32                  `protobuf:"varint,1,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DisableHostResponse) Reset() {
+	*x = DisableHostResponse{}
+	mi := &file_proto_url_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DisableHostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DisableHostResponse) ProtoMessage() {}
+
+func (x *DisableHostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DisableHostResponse.ProtoReflect.Descriptor instead.
+func (*DisableHostResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DisableHostResponse) GetDisabled() int32 {
+	if x != nil {
+		return x.Disabled
+	}
+	return 0
+}
+
+// Report an abusive link for moderators to review
+type ReportLinkRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ShortUrl string                 `protobuf:"bytes,1,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Reason   string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// How to reach the reporter, optional
+	Contact       string `protobuf:"bytes,3,opt,name=contact,proto3" json:"contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLinkRequest) Reset() {
+	*x = ReportLinkRequest{}
+	mi := &file_proto_url_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLinkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLinkRequest) ProtoMessage() {}
+
+func (x *ReportLinkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLinkRequest.ProtoReflect.Descriptor instead.
+func (*ReportLinkRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ReportLinkRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *ReportLinkRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReportLinkRequest) GetContact() string {
+	if x != nil {
+		return x.Contact
+	}
+	return ""
+}
+
+type ReportLinkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReportLinkResponse) Reset() {
+	*x = ReportLinkResponse{}
+	mi := &file_proto_url_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReportLinkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportLinkResponse) ProtoMessage() {}
+
+func (x *ReportLinkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportLinkResponse.ProtoReflect.Descriptor instead.
+func (*ReportLinkResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{38}
+}
+
+// Abuse report as moderators see it
+type AbuseReport struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ShortUrl string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain   string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Reason   string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	Contact  string                 `protobuf:"bytes,5,opt,name=contact,proto3" json:"contact,omitempty"`
+	// Client IP of the reporter
+	Reporter string `protobuf:"bytes,6,opt,name=reporter,proto3" json:"reporter,omitempty"`
+	// pending, approved or dismissed
+	Status string `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	// Unix time in milliseconds
+	CreatedAt     int64 `protobuf:"varint,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbuseReport) Reset() {
+	*x = AbuseReport{}
+	mi := &file_proto_url_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbuseReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbuseReport) ProtoMessage() {}
+
+func (x *AbuseReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbuseReport.ProtoReflect.Descriptor instead.
+func (*AbuseReport) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *AbuseReport) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AbuseReport) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *AbuseReport) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *AbuseReport) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AbuseReport) GetContact() string {
+	if x != nil {
+		return x.Contact
+	}
+	return ""
+}
+
+func (x *AbuseReport) GetReporter() string {
+	if x != nil {
+		return x.Reporter
+	}
+	return ""
+}
+
+func (x *AbuseReport) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AbuseReport) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// List abuse reports with status, pending if empty
+type ListReportsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsRequest) Reset() {
+	*x = ListReportsRequest{}
+	mi := &file_proto_url_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsRequest) ProtoMessage() {}
+
+func (x *ListReportsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsRequest.ProtoReflect.Descriptor instead.
+func (*ListReportsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ListReportsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListReportsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reports       []*AbuseReport         `protobuf:"bytes,1,rep,name=reports,proto3" json:"reports,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReportsResponse) Reset() {
+	*x = ListReportsResponse{}
+	mi := &file_proto_url_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReportsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReportsResponse) ProtoMessage() {}
+
+func (x *ListReportsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReportsResponse.ProtoReflect.Descriptor instead.
+func (*ListReportsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ListReportsResponse) GetReports() []*AbuseReport {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+// Approve a report: disable the link and approve every pending report about it
+type ApproveReportRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ReportId string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	// 410 or 451, 410 if omitted
+	Status int32 `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Reason shown to visitors, the reason of the report if empty
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveReportRequest) Reset() {
+	*x = ApproveReportRequest{}
+	mi := &file_proto_url_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveReportRequest) ProtoMessage() {}
+
+func (x *ApproveReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveReportRequest.ProtoReflect.Descriptor instead.
+func (*ApproveReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ApproveReportRequest) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+func (x *ApproveReportRequest) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ApproveReportRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ApproveReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveReportResponse) Reset() {
+	*x = ApproveReportResponse{}
+	mi := &file_proto_url_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveReportResponse) ProtoMessage() {}
+
+func (x *ApproveReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveReportResponse.ProtoReflect.Descriptor instead.
+func (*ApproveReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{43}
+}
+
+// Dismiss every pending report about the reported link, enabling it if reports disabled it
+type DismissReportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReportId      string                 `protobuf:"bytes,1,opt,name=report_id,json=reportId,proto3" json:"report_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DismissReportRequest) Reset() {
+	*x = DismissReportRequest{}
+	mi := &file_proto_url_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DismissReportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DismissReportRequest) ProtoMessage() {}
+
+func (x *DismissReportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DismissReportRequest.ProtoReflect.Descriptor instead.
+func (*DismissReportRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *DismissReportRequest) GetReportId() string {
+	if x != nil {
+		return x.ReportId
+	}
+	return ""
+}
+
+type DismissReportResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DismissReportResponse) Reset() {
+	*x = DismissReportResponse{}
+	mi := &file_proto_url_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DismissReportResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DismissReportResponse) ProtoMessage() {}
+
+func (x *DismissReportResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DismissReportResponse.ProtoReflect.Descriptor instead.
+func (*DismissReportResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{45}
+}
+
+// A recorded change
+type AuditEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unix time in milliseconds
+	Time    int64  `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Action  string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	ActorId string `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ActorIp string `protobuf:"bytes,5,opt,name=actor_ip,json=actorIp,proto3" json:"actor_ip,omitempty"`
+	// http or grpc
+	Transport   string `protobuf:"bytes,6,opt,name=transport,proto3" json:"transport,omitempty"`
+	RequestId   string `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	ShortUrl    string `protobuf:"bytes,8,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain      string `protobuf:"bytes,9,opt,name=domain,proto3" json:"domain,omitempty"`
+	WorkspaceId string `protobuf:"bytes,10,opt,name=workspace_id,json=workspaceId,proto3" json:"workspace_id,omitempty"`
+	// JSON objects with the values before and after the change, empty if there are none
+	Before        string `protobuf:"bytes,11,opt,name=before,proto3" json:"before,omitempty"`
+	After         string `protobuf:"bytes,12,opt,name=after,proto3" json:"after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEvent) Reset() {
+	*x = AuditEvent{}
+	mi := &file_proto_url_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEvent) ProtoMessage() {}
+
+func (x *AuditEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEvent.ProtoReflect.Descriptor instead.
+func (*AuditEvent) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *AuditEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *AuditEvent) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetActorIp() string {
+	if x != nil {
+		return x.ActorIp
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetWorkspaceId() string {
+	if x != nil {
+		return x.WorkspaceId
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *AuditEvent) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+// Query the audit log, empty fields match any event
+type QueryAuditRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ActorId  string                 `protobuf:"bytes,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	ShortUrl string                 `protobuf:"bytes,2,opt,name=short_url,json=shortUrl,proto3" json:"short_url,omitempty"`
+	Domain   string                 `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	Action   string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	// Unix time in milliseconds, inclusive
+	From int64 `protobuf:"varint,5,opt,name=from,proto3" json:"from,omitempty"`
+	// Unix time in milliseconds, exclusive
+	To int64 `protobuf:"varint,6,opt,name=to,proto3" json:"to,omitempty"`
+	// 100 if omitted, at most 1000
+	Limit         int32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditRequest) Reset() {
+	*x = QueryAuditRequest{}
+	mi := &file_proto_url_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditRequest) ProtoMessage() {}
+
+func (x *QueryAuditRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditRequest.ProtoReflect.Descriptor instead.
+func (*QueryAuditRequest) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *QueryAuditRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *QueryAuditRequest) GetShortUrl() string {
+	if x != nil {
+		return x.ShortUrl
+	}
+	return ""
+}
+
+func (x *QueryAuditRequest) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *QueryAuditRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *QueryAuditRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *QueryAuditRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *QueryAuditRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type QueryAuditResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*AuditEvent          `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditResponse) Reset() {
+	*x = QueryAuditResponse{}
+	mi := &file_proto_url_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditResponse) ProtoMessage() {}
+
+func (x *QueryAuditResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_url_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditResponse.ProtoReflect.Descriptor instead.
+func (*QueryAuditResponse) Descriptor() ([]byte, []int) {
+	return file_proto_url_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *QueryAuditResponse) GetEvents() []*AuditEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+var File_proto_url_proto protoreflect.FileDescriptor
+
+const file_proto_url_proto_rawDesc = "" +
+	"\n" +
+	"\x0fproto/url.proto\x12\tshortener\x1a\x1cgoogle/api/annotations.proto\"\xd5\x01\n" +
+	"\x15CreateShortURLRequest\x12!\n" +
+	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12!\n" +
+	"\fworkspace_id\x18\x02 \x01(\tR\vworkspaceId\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x1d\n" +
+	"\n" +
+	"max_clicks\x18\x04 \x01(\x05R\tmaxClicks\x12#\n" +
+	"\rredirect_type\x18\x05 \x01(\x05R\fredirectType\x12\x16\n" +
+	"\x06domain\x18\x06 \x01(\tR\x06domain\"X\n" +
+	"\x16CreateShortURLResponse\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"X\n" +
+	"\fBatchRequest\x12%\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12!\n" +
+	"\foriginal_url\x18\x02 \x01(\tR\voriginalUrl\"\x81\x01\n" +
+	"\rBatchResponse\x12%\n" +
+	"\x0ecorrelation_id\x18\x01 \x01(\tR\rcorrelationId\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\x86\x01\n" +
+	"\x1aCreateBatchShortURLRequest\x12-\n" +
+	"\x05batch\x18\x01 \x03(\v2\x17.shortener.BatchRequestR\x05batch\x12!\n" +
+	"\fworkspace_id\x18\x02 \x01(\tR\vworkspaceId\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"p\n" +
+	"\x1bCreateBatchShortURLResponse\x12.\n" +
+	"\x05batch\x18\x01 \x03(\v2\x18.shortener.BatchResponseR\x05batch\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"P\n" +
+	"\x15GetOriginalURLRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"`\n" +
+	"\x16GetOriginalURLResponse\x12!\n" +
+	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12#\n" +
+	"\rredirect_type\x18\x02 \x01(\x05R\fredirectType\"a\n" +
+	"\aURLPair\x12!\n" +
+	"\foriginal_url\x18\x01 \x01(\tR\voriginalUrl\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\"+\n" +
+	"\x11GetUserURLRequest\x12\x16\n" +
+	"\x06domain\x18\x01 \x01(\tR\x06domain\"a\n" +
+	"\x12GetUserURLResponse\x12(\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x12.shortener.URLPairR\x05pairs\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"\x14\n" +
+	"\x12WatchClicksRequest\"\x9d\x01\n" +
+	"\n" +
+	"ClickEvent\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x1c\n" +
+	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x12\x18\n" +
+	"\areferer\x18\x03 \x01(\tR\areferer\x12\x1d\n" +
+	"\n" +
+	"user_agent\x18\x04 \x01(\tR\tuserAgent\x12\x1b\n" +
+	"\tclient_ip\x18\x05 \x01(\tR\bclientIp\"6\n" +
+	"\x15DeleteShortURLRequest\x12\x1d\n" +
+	"\n" +
+	"short_urls\x18\x01 \x03(\tR\tshortUrls\";\n" +
+	"\x16DeleteShortURLResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"\x11\n" +
+	"\x0fGetStatsRequest\"<\n" +
+	"\x10GetStatsResponse\x12\x12\n" +
+	"\x04urls\x18\x01 \x01(\x05R\x04urls\x12\x14\n" +
+	"\x05users\x18\x02 \x01(\x05R\x05users\",\n" +
+	"\x16CreateWorkspaceRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"_\n" +
+	"\x17CreateWorkspaceResponse\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"k\n" +
+	"\x19AddWorkspaceMemberRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"?\n" +
+	"\x1aAddWorkspaceMemberResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"Z\n" +
+	"\x1cRemoveWorkspaceMemberRequest\x12!\n" +
+	"\fworkspace_id\x18\x01 \x01(\tR\vworkspaceId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"B\n" +
+	"\x1dRemoveWorkspaceMemberResponse\x12!\n" +
+	"\faccess_token\x18\x01 \x01(\tR\vaccessToken\"\x16\n" +
+	"\x14GetWorkspacesRequest\"C\n" +
+	"\tWorkspace\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\"p\n" +
+	"\x15GetWorkspacesResponse\x124\n" +
+	"\n" +
+	"workspaces\x18\x01 \x03(\v2\x14.shortener.WorkspaceR\n" +
+	"workspaces\x12!\n" +
+	"\faccess_token\x18\x02 \x01(\tR\vaccessToken\"q\n" +
+	"\x10GetQRCodeRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06format\x18\x02 \x01(\tR\x06format\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\"`\n" +
+	"\x11GetQRCodeResponse\x12\x14\n" +
+	"\x05image\x18\x01 \x01(\fR\x05image\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04etag\x18\x03 \x01(\tR\x04etag\"\xee\x02\n" +
+	"\tAdminLink\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12!\n" +
+	"\foriginal_url\x18\x03 \x01(\tR\voriginalUrl\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12!\n" +
+	"\fworkspace_id\x18\x05 \x01(\tR\vworkspaceId\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\x03R\tcreatedAt\x12\x1c\n" +
+	"\tprotected\x18\a \x01(\bR\tprotected\x12\x1d\n" +
+	"\n" +
+	"max_clicks\x18\b \x01(\x05R\tmaxClicks\x12\x1f\n" +
+	"\vclicks_left\x18\t \x01(\x05R\n" +
+	"clicksLeft\x12'\n" +
+	"\x0fdisabled_status\x18\n" +
+	" \x01(\x05R\x0edisabledStatus\x12'\n" +
+	"\x0fdisabled_reason\x18\v \x01(\tR\x0edisabledReason\"\x96\x01\n" +
+	"\x10FindLinksRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x12\n" +
+	"\x04host\x18\x04 \x01(\tR\x04host\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\"?\n" +
+	"\x11FindLinksResponse\x12*\n" +
+	"\x05links\x18\x01 \x03(\v2\x14.shortener.AdminLinkR\x05links\"y\n" +
+	"\x12DisableLinkRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\x05R\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\x15\n" +
+	"\x13DisableLinkResponse\"H\n" +
+	"\x11EnableLinkRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x02 \x01(\tR\x06domain\"\x14\n" +
+	"\x12EnableLinkResponse\"X\n" +
+	"\x12DisableHostRequest\x12\x12\n" +
+	"\x04host\x18\x01 \x01(\tR\x04host\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\x05R\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"1\n" +
+	"\x13DisableHostResponse\x12\x1a\n" +
+	"\bdisabled\x18\x01 \x01(\x05R\bdisabled\"b\n" +
+	"\x11ReportLinkRequest\x12\x1b\n" +
+	"\tshort_url\x18\x01 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x18\n" +
+	"\acontact\x18\x03 \x01(\tR\acontact\"\x14\n" +
+	"\x12ReportLinkResponse\"\xd7\x01\n" +
+	"\vAbuseReport\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12\x18\n" +
+	"\acontact\x18\x05 \x01(\tR\acontact\x12\x1a\n" +
+	"\breporter\x18\x06 \x01(\tR\breporter\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\x03R\tcreatedAt\",\n" +
+	"\x12ListReportsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"G\n" +
+	"\x13ListReportsResponse\x120\n" +
+	"\areports\x18\x01 \x03(\v2\x16.shortener.AbuseReportR\areports\"c\n" +
+	"\x14ApproveReportRequest\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\x05R\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x17\n" +
+	"\x15ApproveReportResponse\"3\n" +
+	"\x14DismissReportRequest\x12\x1b\n" +
+	"\treport_id\x18\x01 \x01(\tR\breportId\"\x17\n" +
+	"\x15DismissReportResponse\"\xc1\x02\n" +
+	"\n" +
+	"AuditEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\x03R\x04time\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x19\n" +
+	"\bactor_ip\x18\x05 \x01(\tR\aactorIp\x12\x1c\n" +
+	"\ttransport\x18\x06 \x01(\tR\ttransport\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x12\x1b\n" +
+	"\tshort_url\x18\b \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\t \x01(\tR\x06domain\x12!\n" +
+	"\fworkspace_id\x18\n" +
+	" \x01(\tR\vworkspaceId\x12\x16\n" +
+	"\x06before\x18\v \x01(\tR\x06before\x12\x14\n" +
+	"\x05after\x18\f \x01(\tR\x05after\"\xb5\x01\n" +
+	"\x11QueryAuditRequest\x12\x19\n" +
+	"\bactor_id\x18\x01 \x01(\tR\aactorId\x12\x1b\n" +
+	"\tshort_url\x18\x02 \x01(\tR\bshortUrl\x12\x16\n" +
+	"\x06domain\x18\x03 \x01(\tR\x06domain\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x12\n" +
+	"\x04from\x18\x05 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x06 \x01(\x03R\x02to\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\"C\n" +
+	"\x12QueryAuditResponse\x12-\n" +
+	"\x06events\x18\x01 \x03(\v2\x15.shortener.AuditEventR\x06events2\x9e\x0e\n" +
+	"\tShortener\x12f\n" +
+	"\n" +
+	"CreateGRPC\x12 .shortener.CreateShortURLRequest\x1a!.shortener.CreateShortURLResponse\"\x13\x82\xd3\xe4\x93\x02\r:\x01*\"\b/v1/urls\x12{\n" +
+	"\x0fCreateBatchGRPC\x12%.shortener.CreateBatchShortURLRequest\x1a&.shortener.CreateBatchShortURLResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/urls:batch\x12w\n" +
+	"\x12GetOriginalURLGRPC\x12 .shortener.GetOriginalURLRequest\x1a!.shortener.GetOriginalURLResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/urls/{short_url}\x12e\n" +
+	"\x0fGetUserURLsGRPC\x12\x1c.shortener.GetUserURLRequest\x1a\x1d.shortener.GetUserURLResponse\"\x15\x82\xd3\xe4\x93\x02\x0f\x12\r/v1/user/urls\x12r\n" +
+	"\n" +
+	"DeleteGRPC\x12 .shortener.DeleteShortURLRequest\x1a!.shortener.DeleteShortURLResponse\"\x1f\x82\xd3\xe4\x93\x02\x19:\x01*\"\x14/v1/user/urls:delete\x12c\n" +
+	"\fGetStatsGRPC\x12\x1a.shortener.GetStatsRequest\x1a\x1b.shortener.GetStatsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/internal/stats\x12w\n" +
+	"\x13CreateWorkspaceGRPC\x12!.shortener.CreateWorkspaceRequest\x1a\".shortener.CreateWorkspaceResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/workspaces\x12\x97\x01\n" +
+	"\x16AddWorkspaceMemberGRPC\x12$.shortener.AddWorkspaceMemberRequest\x1a%.shortener.AddWorkspaceMemberResponse\"0\x82\xd3\xe4\x93\x02*:\x01*\"%/v1/workspaces/{workspace_id}/members\x12\xa7\x01\n" +
+	"\x19RemoveWorkspaceMemberGRPC\x12'.shortener.RemoveWorkspaceMemberRequest\x1a(.shortener.RemoveWorkspaceMemberResponse\"7\x82\xd3\xe4\x93\x021*//v1/workspaces/{workspace_id}/members/{user_id}\x12n\n" +
+	"\x11GetWorkspacesGRPC\x12\x1f.shortener.GetWorkspacesRequest\x1a .shortener.GetWorkspacesResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/workspaces\x12k\n" +
+	"\rGetQRCodeGRPC\x12\x1b.shortener.GetQRCodeRequest\x1a\x1c.shortener.GetQRCodeResponse\"\x1f\x82\xd3\xe4\x93\x02\x19\x12\x17/v1/urls/{short_url}/qr\x12u\n" +
+	"\x0eReportLinkGRPC\x12\x1c.shortener.ReportLinkRequest\x1a\x1d.shortener.ReportLinkResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/urls/{short_url}:report\x12I\n" +
+	"\x10CreateStreamGRPC\x12\x17.shortener.BatchRequest\x1a\x18.shortener.BatchResponse(\x010\x01\x12`\n" +
+	"\fListUserURLs\x12\x1c.shortener.GetUserURLRequest\x1a\x12.shortener.URLPair\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/user/urls:stream0\x01\x12O\n" +
+	"\n" +
+	"BulkCreate\x12\x17.shortener.BatchRequest\x1a&.shortener.CreateBatchShortURLResponse(\x01\x12d\n" +
+	"\vWatchClicks\x12\x1d.shortener.WatchClicksRequest\x1a\x15.shortener.ClickEvent\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/user/clicks:watch0\x012\xf7\x04\n" +
+	"\x05Admin\x12F\n" +
+	"\tFindLinks\x12\x1b.shortener.FindLinksRequest\x1a\x1c.shortener.FindLinksResponse\x12L\n" +
+	"\vDisableLink\x12\x1d.shortener.DisableLinkRequest\x1a\x1e.shortener.DisableLinkResponse\x12I\n" +
 	"\n" +
-	"CreateGRPC\x12 .shortener.CreateShortURLRequest\x1a!.shortener.CreateShortURLResponse\x12`\n" +
-	"\x0fCreateBatchGRPC\x12%.shortener.CreateBatchShortURLRequest\x1a&.shortener.CreateBatchShortURLResponse\x12Y\n" +
-	"\x12GetOriginalURLGRPC\x12 .shortener.GetOriginalURLRequest\x1a!.shortener.GetOriginalURLResponse\x12N\n" +
-	"\x0fGetUserURLsGRPC\x12\x1c.shortener.GetUserURLRequest\x1a\x1d.shortener.GetUserURLResponse\x12Q\n" +
+	"EnableLink\x12\x1c.shortener.EnableLinkRequest\x1a\x1d.shortener.EnableLinkResponse\x12L\n" +
+	"\vDisableHost\x12\x1d.shortener.DisableHostRequest\x1a\x1e.shortener.DisableHostResponse\x12L\n" +
+	"\vListReports\x12\x1d.shortener.ListReportsRequest\x1a\x1e.shortener.ListReportsResponse\x12R\n" +
+	"\rApproveReport\x12\x1f.shortener.ApproveReportRequest\x1a .shortener.ApproveReportResponse\x12R\n" +
+	"\rDismissReport\x12\x1f.shortener.DismissReportRequest\x1a .shortener.DismissReportResponse\x12I\n" +
 	"\n" +
-	"DeleteGRPC\x12 .shortener.DeleteShortURLRequest\x1a!.shortener.DeleteShortURLResponse\x12G\n" +
-	"\fGetStatsGRPC\x12\x1a.shortener.GetStatsRequest\x1a\x1b.shortener.GetStatsResponseB$Z\"github.com/MukizuL/shortener/protob\x06proto3"
+	"QueryAudit\x12\x1c.shortener.QueryAuditRequest\x1a\x1d.shortener.QueryAuditResponseB$Z\"github.com/MukizuL/shortener/protob\x06proto3"
 
 var (
 	file_proto_url_proto_rawDescOnce sync.Once
@@ -790,45 +3067,119 @@ func file_proto_url_proto_rawDescGZIP() []byte {
 	return file_proto_url_proto_rawDescData
 }
 
-var file_proto_url_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_proto_url_proto_msgTypes = make([]protoimpl.MessageInfo, 49)
 var file_proto_url_proto_goTypes = []any{
-	(*CreateShortURLRequest)(nil),       // 0: shortener.CreateShortURLRequest
-	(*CreateShortURLResponse)(nil),      // 1: shortener.CreateShortURLResponse
-	(*BatchRequest)(nil),                // 2: shortener.BatchRequest
-	(*BatchResponse)(nil),               // 3: shortener.BatchResponse
-	(*CreateBatchShortURLRequest)(nil),  // 4: shortener.CreateBatchShortURLRequest
-	(*CreateBatchShortURLResponse)(nil), // 5: shortener.CreateBatchShortURLResponse
-	(*GetOriginalURLRequest)(nil),       // 6: shortener.GetOriginalURLRequest
-	(*GetOriginalURLResponse)(nil),      // 7: shortener.GetOriginalURLResponse
-	(*URLPair)(nil),                     // 8: shortener.URLPair
-	(*GetUserURLRequest)(nil),           // 9: shortener.GetUserURLRequest
-	(*GetUserURLResponse)(nil),          // 10: shortener.GetUserURLResponse
-	(*DeleteShortURLRequest)(nil),       // 11: shortener.DeleteShortURLRequest
-	(*DeleteShortURLResponse)(nil),      // 12: shortener.DeleteShortURLResponse
-	(*GetStatsRequest)(nil),             // 13: shortener.GetStatsRequest
-	(*GetStatsResponse)(nil),            // 14: shortener.GetStatsResponse
+	(*CreateShortURLRequest)(nil),         // 0: shortener.CreateShortURLRequest
+	(*CreateShortURLResponse)(nil),        // 1: shortener.CreateShortURLResponse
+	(*BatchRequest)(nil),                  // 2: shortener.BatchRequest
+	(*BatchResponse)(nil),                 // 3: shortener.BatchResponse
+	(*CreateBatchShortURLRequest)(nil),    // 4: shortener.CreateBatchShortURLRequest
+	(*CreateBatchShortURLResponse)(nil),   // 5: shortener.CreateBatchShortURLResponse
+	(*GetOriginalURLRequest)(nil),         // 6: shortener.GetOriginalURLRequest
+	(*GetOriginalURLResponse)(nil),        // 7: shortener.GetOriginalURLResponse
+	(*URLPair)(nil),                       // 8: shortener.URLPair
+	(*GetUserURLRequest)(nil),             // 9: shortener.GetUserURLRequest
+	(*GetUserURLResponse)(nil),            // 10: shortener.GetUserURLResponse
+	(*WatchClicksRequest)(nil),            // 11: shortener.WatchClicksRequest
+	(*ClickEvent)(nil),                    // 12: shortener.ClickEvent
+	(*DeleteShortURLRequest)(nil),         // 13: shortener.DeleteShortURLRequest
+	(*DeleteShortURLResponse)(nil),        // 14: shortener.DeleteShortURLResponse
+	(*GetStatsRequest)(nil),               // 15: shortener.GetStatsRequest
+	(*GetStatsResponse)(nil),              // 16: shortener.GetStatsResponse
+	(*CreateWorkspaceRequest)(nil),        // 17: shortener.CreateWorkspaceRequest
+	(*CreateWorkspaceResponse)(nil),       // 18: shortener.CreateWorkspaceResponse
+	(*AddWorkspaceMemberRequest)(nil),     // 19: shortener.AddWorkspaceMemberRequest
+	(*AddWorkspaceMemberResponse)(nil),    // 20: shortener.AddWorkspaceMemberResponse
+	(*RemoveWorkspaceMemberRequest)(nil),  // 21: shortener.RemoveWorkspaceMemberRequest
+	(*RemoveWorkspaceMemberResponse)(nil), // 22: shortener.RemoveWorkspaceMemberResponse
+	(*GetWorkspacesRequest)(nil),          // 23: shortener.GetWorkspacesRequest
+	(*Workspace)(nil),                     // 24: shortener.Workspace
+	(*GetWorkspacesResponse)(nil),         // 25: shortener.GetWorkspacesResponse
+	(*GetQRCodeRequest)(nil),              // 26: shortener.GetQRCodeRequest
+	(*GetQRCodeResponse)(nil),             // 27: shortener.GetQRCodeResponse
+	(*AdminLink)(nil),                     // 28: shortener.AdminLink
+	(*FindLinksRequest)(nil),              // 29: shortener.FindLinksRequest
+	(*FindLinksResponse)(nil),             // 30: shortener.FindLinksResponse
+	(*DisableLinkRequest)(nil),            // 31: shortener.DisableLinkRequest
+	(*DisableLinkResponse)(nil),           // 32: shortener.DisableLinkResponse
+	(*EnableLinkRequest)(nil),             // 33: shortener.EnableLinkRequest
+	(*EnableLinkResponse)(nil),            // 34: shortener.EnableLinkResponse
+	(*DisableHostRequest)(nil),            // 35: shortener.DisableHostRequest
+	(*DisableHostResponse)(nil),           // 36: shortener.DisableHostResponse
+	(*ReportLinkRequest)(nil),             // 37: shortener.ReportLinkRequest
+	(*ReportLinkResponse)(nil),            // 38: shortener.ReportLinkResponse
+	(*AbuseReport)(nil),                   // 39: shortener.AbuseReport
+	(*ListReportsRequest)(nil),            // 40: shortener.ListReportsRequest
+	(*ListReportsResponse)(nil),           // 41: shortener.ListReportsResponse
+	(*ApproveReportRequest)(nil),          // 42: shortener.ApproveReportRequest
+	(*ApproveReportResponse)(nil),         // 43: shortener.ApproveReportResponse
+	(*DismissReportRequest)(nil),          // 44: shortener.DismissReportRequest
+	(*DismissReportResponse)(nil),         // 45: shortener.DismissReportResponse
+	(*AuditEvent)(nil),                    // 46: shortener.AuditEvent
+	(*QueryAuditRequest)(nil),             // 47: shortener.QueryAuditRequest
+	(*QueryAuditResponse)(nil),            // 48: shortener.QueryAuditResponse
 }
 var file_proto_url_proto_depIdxs = []int32{
 	2,  // 0: shortener.CreateBatchShortURLRequest.batch:type_name -> shortener.BatchRequest
 	3,  // 1: shortener.CreateBatchShortURLResponse.batch:type_name -> shortener.BatchResponse
 	8,  // 2: shortener.GetUserURLResponse.pairs:type_name -> shortener.URLPair
-	0,  // 3: shortener.Shortener.CreateGRPC:input_type -> shortener.CreateShortURLRequest
-	4,  // 4: shortener.Shortener.CreateBatchGRPC:input_type -> shortener.CreateBatchShortURLRequest
-	6,  // 5: shortener.Shortener.GetOriginalURLGRPC:input_type -> shortener.GetOriginalURLRequest
-	9,  // 6: shortener.Shortener.GetUserURLsGRPC:input_type -> shortener.GetUserURLRequest
-	11, // 7: shortener.Shortener.DeleteGRPC:input_type -> shortener.DeleteShortURLRequest
-	13, // 8: shortener.Shortener.GetStatsGRPC:input_type -> shortener.GetStatsRequest
-	1,  // 9: shortener.Shortener.CreateGRPC:output_type -> shortener.CreateShortURLResponse
-	5,  // 10: shortener.Shortener.CreateBatchGRPC:output_type -> shortener.CreateBatchShortURLResponse
-	7,  // 11: shortener.Shortener.GetOriginalURLGRPC:output_type -> shortener.GetOriginalURLResponse
-	10, // 12: shortener.Shortener.GetUserURLsGRPC:output_type -> shortener.GetUserURLResponse
-	12, // 13: shortener.Shortener.DeleteGRPC:output_type -> shortener.DeleteShortURLResponse
-	14, // 14: shortener.Shortener.GetStatsGRPC:output_type -> shortener.GetStatsResponse
-	9,  // [9:15] is the sub-list for method output_type
-	3,  // [3:9] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	24, // 3: shortener.GetWorkspacesResponse.workspaces:type_name -> shortener.Workspace
+	28, // 4: shortener.FindLinksResponse.links:type_name -> shortener.AdminLink
+	39, // 5: shortener.ListReportsResponse.reports:type_name -> shortener.AbuseReport
+	46, // 6: shortener.QueryAuditResponse.events:type_name -> shortener.AuditEvent
+	0,  // 7: shortener.Shortener.CreateGRPC:input_type -> shortener.CreateShortURLRequest
+	4,  // 8: shortener.Shortener.CreateBatchGRPC:input_type -> shortener.CreateBatchShortURLRequest
+	6,  // 9: shortener.Shortener.GetOriginalURLGRPC:input_type -> shortener.GetOriginalURLRequest
+	9,  // 10: shortener.Shortener.GetUserURLsGRPC:input_type -> shortener.GetUserURLRequest
+	13, // 11: shortener.Shortener.DeleteGRPC:input_type -> shortener.DeleteShortURLRequest
+	15, // 12: shortener.Shortener.GetStatsGRPC:input_type -> shortener.GetStatsRequest
+	17, // 13: shortener.Shortener.CreateWorkspaceGRPC:input_type -> shortener.CreateWorkspaceRequest
+	19, // 14: shortener.Shortener.AddWorkspaceMemberGRPC:input_type -> shortener.AddWorkspaceMemberRequest
+	21, // 15: shortener.Shortener.RemoveWorkspaceMemberGRPC:input_type -> shortener.RemoveWorkspaceMemberRequest
+	23, // 16: shortener.Shortener.GetWorkspacesGRPC:input_type -> shortener.GetWorkspacesRequest
+	26, // 17: shortener.Shortener.GetQRCodeGRPC:input_type -> shortener.GetQRCodeRequest
+	37, // 18: shortener.Shortener.ReportLinkGRPC:input_type -> shortener.ReportLinkRequest
+	2,  // 19: shortener.Shortener.CreateStreamGRPC:input_type -> shortener.BatchRequest
+	9,  // 20: shortener.Shortener.ListUserURLs:input_type -> shortener.GetUserURLRequest
+	2,  // 21: shortener.Shortener.BulkCreate:input_type -> shortener.BatchRequest
+	11, // 22: shortener.Shortener.WatchClicks:input_type -> shortener.WatchClicksRequest
+	29, // 23: shortener.Admin.FindLinks:input_type -> shortener.FindLinksRequest
+	31, // 24: shortener.Admin.DisableLink:input_type -> shortener.DisableLinkRequest
+	33, // 25: shortener.Admin.EnableLink:input_type -> shortener.EnableLinkRequest
+	35, // 26: shortener.Admin.DisableHost:input_type -> shortener.DisableHostRequest
+	40, // 27: shortener.Admin.ListReports:input_type -> shortener.ListReportsRequest
+	42, // 28: shortener.Admin.ApproveReport:input_type -> shortener.ApproveReportRequest
+	44, // 29: shortener.Admin.DismissReport:input_type -> shortener.DismissReportRequest
+	47, // 30: shortener.Admin.QueryAudit:input_type -> shortener.QueryAuditRequest
+	1,  // 31: shortener.Shortener.CreateGRPC:output_type -> shortener.CreateShortURLResponse
+	5,  // 32: shortener.Shortener.CreateBatchGRPC:output_type -> shortener.CreateBatchShortURLResponse
+	7,  // 33: shortener.Shortener.GetOriginalURLGRPC:output_type -> shortener.GetOriginalURLResponse
+	10, // 34: shortener.Shortener.GetUserURLsGRPC:output_type -> shortener.GetUserURLResponse
+	14, // 35: shortener.Shortener.DeleteGRPC:output_type -> shortener.DeleteShortURLResponse
+	16, // 36: shortener.Shortener.GetStatsGRPC:output_type -> shortener.GetStatsResponse
+	18, // 37: shortener.Shortener.CreateWorkspaceGRPC:output_type -> shortener.CreateWorkspaceResponse
+	20, // 38: shortener.Shortener.AddWorkspaceMemberGRPC:output_type -> shortener.AddWorkspaceMemberResponse
+	22, // 39: shortener.Shortener.RemoveWorkspaceMemberGRPC:output_type -> shortener.RemoveWorkspaceMemberResponse
+	25, // 40: shortener.Shortener.GetWorkspacesGRPC:output_type -> shortener.GetWorkspacesResponse
+	27, // 41: shortener.Shortener.GetQRCodeGRPC:output_type -> shortener.GetQRCodeResponse
+	38, // 42: shortener.Shortener.ReportLinkGRPC:output_type -> shortener.ReportLinkResponse
+	3,  // 43: shortener.Shortener.CreateStreamGRPC:output_type -> shortener.BatchResponse
+	8,  // 44: shortener.Shortener.ListUserURLs:output_type -> shortener.URLPair
+	5,  // 45: shortener.Shortener.BulkCreate:output_type -> shortener.CreateBatchShortURLResponse
+	12, // 46: shortener.Shortener.WatchClicks:output_type -> shortener.ClickEvent
+	30, // 47: shortener.Admin.FindLinks:output_type -> shortener.FindLinksResponse
+	32, // 48: shortener.Admin.DisableLink:output_type -> shortener.DisableLinkResponse
+	34, // 49: shortener.Admin.EnableLink:output_type -> shortener.EnableLinkResponse
+	36, // 50: shortener.Admin.DisableHost:output_type -> shortener.DisableHostResponse
+	41, // 51: shortener.Admin.ListReports:output_type -> shortener.ListReportsResponse
+	43, // 52: shortener.Admin.ApproveReport:output_type -> shortener.ApproveReportResponse
+	45, // 53: shortener.Admin.DismissReport:output_type -> shortener.DismissReportResponse
+	48, // 54: shortener.Admin.QueryAudit:output_type -> shortener.QueryAuditResponse
+	31, // [31:55] is the sub-list for method output_type
+	7,  // [7:31] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_proto_url_proto_init() }
@@ -842,9 +3193,9 @@ func file_proto_url_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_url_proto_rawDesc), len(file_proto_url_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   15,
+			NumMessages:   49,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_proto_url_proto_goTypes,
 		DependencyIndexes: file_proto_url_proto_depIdxs,
@@ -19,12 +19,22 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Shortener_CreateGRPC_FullMethodName         = "/shortener.Shortener/CreateGRPC"
-	Shortener_CreateBatchGRPC_FullMethodName    = "/shortener.Shortener/CreateBatchGRPC"
-	Shortener_GetOriginalURLGRPC_FullMethodName = "/shortener.Shortener/GetOriginalURLGRPC"
-	Shortener_GetUserURLsGRPC_FullMethodName    = "/shortener.Shortener/GetUserURLsGRPC"
-	Shortener_DeleteGRPC_FullMethodName         = "/shortener.Shortener/DeleteGRPC"
-	Shortener_GetStatsGRPC_FullMethodName       = "/shortener.Shortener/GetStatsGRPC"
+	Shortener_CreateGRPC_FullMethodName                = "/shortener.Shortener/CreateGRPC"
+	Shortener_CreateBatchGRPC_FullMethodName           = "/shortener.Shortener/CreateBatchGRPC"
+	Shortener_GetOriginalURLGRPC_FullMethodName        = "/shortener.Shortener/GetOriginalURLGRPC"
+	Shortener_GetUserURLsGRPC_FullMethodName           = "/shortener.Shortener/GetUserURLsGRPC"
+	Shortener_DeleteGRPC_FullMethodName                = "/shortener.Shortener/DeleteGRPC"
+	Shortener_GetStatsGRPC_FullMethodName              = "/shortener.Shortener/GetStatsGRPC"
+	Shortener_CreateWorkspaceGRPC_FullMethodName       = "/shortener.Shortener/CreateWorkspaceGRPC"
+	Shortener_AddWorkspaceMemberGRPC_FullMethodName    = "/shortener.Shortener/AddWorkspaceMemberGRPC"
+	Shortener_RemoveWorkspaceMemberGRPC_FullMethodName = "/shortener.Shortener/RemoveWorkspaceMemberGRPC"
+	Shortener_GetWorkspacesGRPC_FullMethodName         = "/shortener.Shortener/GetWorkspacesGRPC"
+	Shortener_GetQRCodeGRPC_FullMethodName             = "/shortener.Shortener/GetQRCodeGRPC"
+	Shortener_ReportLinkGRPC_FullMethodName            = "/shortener.Shortener/ReportLinkGRPC"
+	Shortener_CreateStreamGRPC_FullMethodName          = "/shortener.Shortener/CreateStreamGRPC"
+	Shortener_ListUserURLs_FullMethodName              = "/shortener.Shortener/ListUserURLs"
+	Shortener_BulkCreate_FullMethodName                = "/shortener.Shortener/BulkCreate"
+	Shortener_WatchClicks_FullMethodName               = "/shortener.Shortener/WatchClicks"
 )
 
 // ShortenerClient is the client API for Shortener service.
@@ -37,6 +47,23 @@ type ShortenerClient interface {
 	GetUserURLsGRPC(ctx context.Context, in *GetUserURLRequest, opts ...grpc.CallOption) (*GetUserURLResponse, error)
 	DeleteGRPC(ctx context.Context, in *DeleteShortURLRequest, opts ...grpc.CallOption) (*DeleteShortURLResponse, error)
 	GetStatsGRPC(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	CreateWorkspaceGRPC(ctx context.Context, in *CreateWorkspaceRequest, opts ...grpc.CallOption) (*CreateWorkspaceResponse, error)
+	AddWorkspaceMemberGRPC(ctx context.Context, in *AddWorkspaceMemberRequest, opts ...grpc.CallOption) (*AddWorkspaceMemberResponse, error)
+	RemoveWorkspaceMemberGRPC(ctx context.Context, in *RemoveWorkspaceMemberRequest, opts ...grpc.CallOption) (*RemoveWorkspaceMemberResponse, error)
+	GetWorkspacesGRPC(ctx context.Context, in *GetWorkspacesRequest, opts ...grpc.CallOption) (*GetWorkspacesResponse, error)
+	GetQRCodeGRPC(ctx context.Context, in *GetQRCodeRequest, opts ...grpc.CallOption) (*GetQRCodeResponse, error)
+	// Files an abuse report. Enough distinct reporters disable the link until a moderator reviews the reports.
+	ReportLinkGRPC(ctx context.Context, in *ReportLinkRequest, opts ...grpc.CallOption) (*ReportLinkResponse, error)
+	// Creates short URLs from a stream of items. Results are streamed back in chunks as they are committed.
+	// Takes the workspace from workspace-id metadata and the short domain from domain metadata.
+	CreateStreamGRPC(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BatchRequest, BatchResponse], error)
+	// Streams URLs of the caller one per message. The access token is sent in the access-token header.
+	ListUserURLs(ctx context.Context, in *GetUserURLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[URLPair], error)
+	// Creates short URLs from a stream and answers with all results once the stream is closed.
+	// Takes the workspace from workspace-id metadata and the short domain from domain metadata.
+	BulkCreate(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BatchRequest, CreateBatchShortURLResponse], error)
+	// Pushes redirects of links created by the caller as they happen. Events are dropped for a client that falls behind.
+	WatchClicks(ctx context.Context, in *WatchClicksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClickEvent], error)
 }
 
 type shortenerClient struct {
@@ -107,6 +134,130 @@ func (c *shortenerClient) GetStatsGRPC(ctx context.Context, in *GetStatsRequest,
 	return out, nil
 }
 
+func (c *shortenerClient) CreateWorkspaceGRPC(ctx context.Context, in *CreateWorkspaceRequest, opts ...grpc.CallOption) (*CreateWorkspaceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateWorkspaceResponse)
+	err := c.cc.Invoke(ctx, Shortener_CreateWorkspaceGRPC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) AddWorkspaceMemberGRPC(ctx context.Context, in *AddWorkspaceMemberRequest, opts ...grpc.CallOption) (*AddWorkspaceMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddWorkspaceMemberResponse)
+	err := c.cc.Invoke(ctx, Shortener_AddWorkspaceMemberGRPC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) RemoveWorkspaceMemberGRPC(ctx context.Context, in *RemoveWorkspaceMemberRequest, opts ...grpc.CallOption) (*RemoveWorkspaceMemberResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveWorkspaceMemberResponse)
+	err := c.cc.Invoke(ctx, Shortener_RemoveWorkspaceMemberGRPC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) GetWorkspacesGRPC(ctx context.Context, in *GetWorkspacesRequest, opts ...grpc.CallOption) (*GetWorkspacesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWorkspacesResponse)
+	err := c.cc.Invoke(ctx, Shortener_GetWorkspacesGRPC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) GetQRCodeGRPC(ctx context.Context, in *GetQRCodeRequest, opts ...grpc.CallOption) (*GetQRCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetQRCodeResponse)
+	err := c.cc.Invoke(ctx, Shortener_GetQRCodeGRPC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) ReportLinkGRPC(ctx context.Context, in *ReportLinkRequest, opts ...grpc.CallOption) (*ReportLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReportLinkResponse)
+	err := c.cc.Invoke(ctx, Shortener_ReportLinkGRPC_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *shortenerClient) CreateStreamGRPC(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[BatchRequest, BatchResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Shortener_ServiceDesc.Streams[0], Shortener_CreateStreamGRPC_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchRequest, BatchResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_CreateStreamGRPCClient = grpc.BidiStreamingClient[BatchRequest, BatchResponse]
+
+func (c *shortenerClient) ListUserURLs(ctx context.Context, in *GetUserURLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[URLPair], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Shortener_ServiceDesc.Streams[1], Shortener_ListUserURLs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetUserURLRequest, URLPair]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_ListUserURLsClient = grpc.ServerStreamingClient[URLPair]
+
+func (c *shortenerClient) BulkCreate(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[BatchRequest, CreateBatchShortURLResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Shortener_ServiceDesc.Streams[2], Shortener_BulkCreate_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[BatchRequest, CreateBatchShortURLResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_BulkCreateClient = grpc.ClientStreamingClient[BatchRequest, CreateBatchShortURLResponse]
+
+func (c *shortenerClient) WatchClicks(ctx context.Context, in *WatchClicksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ClickEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Shortener_ServiceDesc.Streams[3], Shortener_WatchClicks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchClicksRequest, ClickEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_WatchClicksClient = grpc.ServerStreamingClient[ClickEvent]
+
 // ShortenerServer is the server API for Shortener service.
 // All implementations must embed UnimplementedShortenerServer
 // for forward compatibility.
@@ -117,6 +268,23 @@ type ShortenerServer interface {
 	GetUserURLsGRPC(context.Context, *GetUserURLRequest) (*GetUserURLResponse, error)
 	DeleteGRPC(context.Context, *DeleteShortURLRequest) (*DeleteShortURLResponse, error)
 	GetStatsGRPC(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	CreateWorkspaceGRPC(context.Context, *CreateWorkspaceRequest) (*CreateWorkspaceResponse, error)
+	AddWorkspaceMemberGRPC(context.Context, *AddWorkspaceMemberRequest) (*AddWorkspaceMemberResponse, error)
+	RemoveWorkspaceMemberGRPC(context.Context, *RemoveWorkspaceMemberRequest) (*RemoveWorkspaceMemberResponse, error)
+	GetWorkspacesGRPC(context.Context, *GetWorkspacesRequest) (*GetWorkspacesResponse, error)
+	GetQRCodeGRPC(context.Context, *GetQRCodeRequest) (*GetQRCodeResponse, error)
+	// Files an abuse report. Enough distinct reporters disable the link until a moderator reviews the reports.
+	ReportLinkGRPC(context.Context, *ReportLinkRequest) (*ReportLinkResponse, error)
+	// Creates short URLs from a stream of items. Results are streamed back in chunks as they are committed.
+	// Takes the workspace from workspace-id metadata and the short domain from domain metadata.
+	CreateStreamGRPC(grpc.BidiStreamingServer[BatchRequest, BatchResponse]) error
+	// Streams URLs of the caller one per message. The access token is sent in the access-token header.
+	ListUserURLs(*GetUserURLRequest, grpc.ServerStreamingServer[URLPair]) error
+	// Creates short URLs from a stream and answers with all results once the stream is closed.
+	// Takes the workspace from workspace-id metadata and the short domain from domain metadata.
+	BulkCreate(grpc.ClientStreamingServer[BatchRequest, CreateBatchShortURLResponse]) error
+	// Pushes redirects of links created by the caller as they happen. Events are dropped for a client that falls behind.
+	WatchClicks(*WatchClicksRequest, grpc.ServerStreamingServer[ClickEvent]) error
 	mustEmbedUnimplementedShortenerServer()
 }
 
@@ -145,6 +313,36 @@ func (UnimplementedShortenerServer) DeleteGRPC(context.Context, *DeleteShortURLR
 func (UnimplementedShortenerServer) GetStatsGRPC(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetStatsGRPC not implemented")
 }
+func (UnimplementedShortenerServer) CreateWorkspaceGRPC(context.Context, *CreateWorkspaceRequest) (*CreateWorkspaceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWorkspaceGRPC not implemented")
+}
+func (UnimplementedShortenerServer) AddWorkspaceMemberGRPC(context.Context, *AddWorkspaceMemberRequest) (*AddWorkspaceMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddWorkspaceMemberGRPC not implemented")
+}
+func (UnimplementedShortenerServer) RemoveWorkspaceMemberGRPC(context.Context, *RemoveWorkspaceMemberRequest) (*RemoveWorkspaceMemberResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveWorkspaceMemberGRPC not implemented")
+}
+func (UnimplementedShortenerServer) GetWorkspacesGRPC(context.Context, *GetWorkspacesRequest) (*GetWorkspacesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWorkspacesGRPC not implemented")
+}
+func (UnimplementedShortenerServer) GetQRCodeGRPC(context.Context, *GetQRCodeRequest) (*GetQRCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQRCodeGRPC not implemented")
+}
+func (UnimplementedShortenerServer) ReportLinkGRPC(context.Context, *ReportLinkRequest) (*ReportLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportLinkGRPC not implemented")
+}
+func (UnimplementedShortenerServer) CreateStreamGRPC(grpc.BidiStreamingServer[BatchRequest, BatchResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method CreateStreamGRPC not implemented")
+}
+func (UnimplementedShortenerServer) ListUserURLs(*GetUserURLRequest, grpc.ServerStreamingServer[URLPair]) error {
+	return status.Errorf(codes.Unimplemented, "method ListUserURLs not implemented")
+}
+func (UnimplementedShortenerServer) BulkCreate(grpc.ClientStreamingServer[BatchRequest, CreateBatchShortURLResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method BulkCreate not implemented")
+}
+func (UnimplementedShortenerServer) WatchClicks(*WatchClicksRequest, grpc.ServerStreamingServer[ClickEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchClicks not implemented")
+}
 func (UnimplementedShortenerServer) mustEmbedUnimplementedShortenerServer() {}
 func (UnimplementedShortenerServer) testEmbeddedByValue()                   {}
 
@@ -274,6 +472,150 @@ func _Shortener_GetStatsGRPC_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Shortener_CreateWorkspaceGRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWorkspaceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).CreateWorkspaceGRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_CreateWorkspaceGRPC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).CreateWorkspaceGRPC(ctx, req.(*CreateWorkspaceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_AddWorkspaceMemberGRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddWorkspaceMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).AddWorkspaceMemberGRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_AddWorkspaceMemberGRPC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).AddWorkspaceMemberGRPC(ctx, req.(*AddWorkspaceMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_RemoveWorkspaceMemberGRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveWorkspaceMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).RemoveWorkspaceMemberGRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_RemoveWorkspaceMemberGRPC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).RemoveWorkspaceMemberGRPC(ctx, req.(*RemoveWorkspaceMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_GetWorkspacesGRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWorkspacesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).GetWorkspacesGRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_GetWorkspacesGRPC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).GetWorkspacesGRPC(ctx, req.(*GetWorkspacesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_GetQRCodeGRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).GetQRCodeGRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_GetQRCodeGRPC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).GetQRCodeGRPC(ctx, req.(*GetQRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_ReportLinkGRPC_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ShortenerServer).ReportLinkGRPC(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Shortener_ReportLinkGRPC_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ShortenerServer).ReportLinkGRPC(ctx, req.(*ReportLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Shortener_CreateStreamGRPC_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShortenerServer).CreateStreamGRPC(&grpc.GenericServerStream[BatchRequest, BatchResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_CreateStreamGRPCServer = grpc.BidiStreamingServer[BatchRequest, BatchResponse]
+
+func _Shortener_ListUserURLs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetUserURLRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShortenerServer).ListUserURLs(m, &grpc.GenericServerStream[GetUserURLRequest, URLPair]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_ListUserURLsServer = grpc.ServerStreamingServer[URLPair]
+
+func _Shortener_BulkCreate_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ShortenerServer).BulkCreate(&grpc.GenericServerStream[BatchRequest, CreateBatchShortURLResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_BulkCreateServer = grpc.ClientStreamingServer[BatchRequest, CreateBatchShortURLResponse]
+
+func _Shortener_WatchClicks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchClicksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ShortenerServer).WatchClicks(m, &grpc.GenericServerStream[WatchClicksRequest, ClickEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Shortener_WatchClicksServer = grpc.ServerStreamingServer[ClickEvent]
+
 // Shortener_ServiceDesc is the grpc.ServiceDesc for Shortener service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -305,6 +647,430 @@ var Shortener_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStatsGRPC",
 			Handler:    _Shortener_GetStatsGRPC_Handler,
 		},
+		{
+			MethodName: "CreateWorkspaceGRPC",
+			Handler:    _Shortener_CreateWorkspaceGRPC_Handler,
+		},
+		{
+			MethodName: "AddWorkspaceMemberGRPC",
+			Handler:    _Shortener_AddWorkspaceMemberGRPC_Handler,
+		},
+		{
+			MethodName: "RemoveWorkspaceMemberGRPC",
+			Handler:    _Shortener_RemoveWorkspaceMemberGRPC_Handler,
+		},
+		{
+			MethodName: "GetWorkspacesGRPC",
+			Handler:    _Shortener_GetWorkspacesGRPC_Handler,
+		},
+		{
+			MethodName: "GetQRCodeGRPC",
+			Handler:    _Shortener_GetQRCodeGRPC_Handler,
+		},
+		{
+			MethodName: "ReportLinkGRPC",
+			Handler:    _Shortener_ReportLinkGRPC_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "CreateStreamGRPC",
+			Handler:       _Shortener_CreateStreamGRPC_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ListUserURLs",
+			Handler:       _Shortener_ListUserURLs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "BulkCreate",
+			Handler:       _Shortener_BulkCreate_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchClicks",
+			Handler:       _Shortener_WatchClicks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/url.proto",
+}
+
+const (
+	Admin_FindLinks_FullMethodName     = "/shortener.Admin/FindLinks"
+	Admin_DisableLink_FullMethodName   = "/shortener.Admin/DisableLink"
+	Admin_EnableLink_FullMethodName    = "/shortener.Admin/EnableLink"
+	Admin_DisableHost_FullMethodName   = "/shortener.Admin/DisableHost"
+	Admin_ListReports_FullMethodName   = "/shortener.Admin/ListReports"
+	Admin_ApproveReport_FullMethodName = "/shortener.Admin/ApproveReport"
+	Admin_DismissReport_FullMethodName = "/shortener.Admin/DismissReport"
+	Admin_QueryAudit_FullMethodName    = "/shortener.Admin/QueryAudit"
+)
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Moderation of links of every user. Callable with a trusted client certificate, from the trusted subnet
+// or with an access token of an admin user in access-token metadata.
+type AdminClient interface {
+	FindLinks(ctx context.Context, in *FindLinksRequest, opts ...grpc.CallOption) (*FindLinksResponse, error)
+	DisableLink(ctx context.Context, in *DisableLinkRequest, opts ...grpc.CallOption) (*DisableLinkResponse, error)
+	EnableLink(ctx context.Context, in *EnableLinkRequest, opts ...grpc.CallOption) (*EnableLinkResponse, error)
+	DisableHost(ctx context.Context, in *DisableHostRequest, opts ...grpc.CallOption) (*DisableHostResponse, error)
+	// Returns the abuse report queue, oldest first.
+	ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error)
+	ApproveReport(ctx context.Context, in *ApproveReportRequest, opts ...grpc.CallOption) (*ApproveReportResponse, error)
+	DismissReport(ctx context.Context, in *DismissReportRequest, opts ...grpc.CallOption) (*DismissReportResponse, error)
+	// Returns audit events, newest first.
+	QueryAudit(ctx context.Context, in *QueryAuditRequest, opts ...grpc.CallOption) (*QueryAuditResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) FindLinks(ctx context.Context, in *FindLinksRequest, opts ...grpc.CallOption) (*FindLinksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindLinksResponse)
+	err := c.cc.Invoke(ctx, Admin_FindLinks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DisableLink(ctx context.Context, in *DisableLinkRequest, opts ...grpc.CallOption) (*DisableLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableLinkResponse)
+	err := c.cc.Invoke(ctx, Admin_DisableLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) EnableLink(ctx context.Context, in *EnableLinkRequest, opts ...grpc.CallOption) (*EnableLinkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnableLinkResponse)
+	err := c.cc.Invoke(ctx, Admin_EnableLink_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DisableHost(ctx context.Context, in *DisableHostRequest, opts ...grpc.CallOption) (*DisableHostResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DisableHostResponse)
+	err := c.cc.Invoke(ctx, Admin_DisableHost_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListReports(ctx context.Context, in *ListReportsRequest, opts ...grpc.CallOption) (*ListReportsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListReportsResponse)
+	err := c.cc.Invoke(ctx, Admin_ListReports_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ApproveReport(ctx context.Context, in *ApproveReportRequest, opts ...grpc.CallOption) (*ApproveReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveReportResponse)
+	err := c.cc.Invoke(ctx, Admin_ApproveReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DismissReport(ctx context.Context, in *DismissReportRequest, opts ...grpc.CallOption) (*DismissReportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DismissReportResponse)
+	err := c.cc.Invoke(ctx, Admin_DismissReport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) QueryAudit(ctx context.Context, in *QueryAuditRequest, opts ...grpc.CallOption) (*QueryAuditResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryAuditResponse)
+	err := c.cc.Invoke(ctx, Admin_QueryAudit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility.
+//
+// Moderation of links of every user. Callable with a trusted client certificate, from the trusted subnet
+// or with an access token of an admin user in access-token metadata.
+type AdminServer interface {
+	FindLinks(context.Context, *FindLinksRequest) (*FindLinksResponse, error)
+	DisableLink(context.Context, *DisableLinkRequest) (*DisableLinkResponse, error)
+	EnableLink(context.Context, *EnableLinkRequest) (*EnableLinkResponse, error)
+	DisableHost(context.Context, *DisableHostRequest) (*DisableHostResponse, error)
+	// Returns the abuse report queue, oldest first.
+	ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error)
+	ApproveReport(context.Context, *ApproveReportRequest) (*ApproveReportResponse, error)
+	DismissReport(context.Context, *DismissReportRequest) (*DismissReportResponse, error)
+	// Returns audit events, newest first.
+	QueryAudit(context.Context, *QueryAuditRequest) (*QueryAuditResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServer struct{}
+
+func (UnimplementedAdminServer) FindLinks(context.Context, *FindLinksRequest) (*FindLinksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindLinks not implemented")
+}
+func (UnimplementedAdminServer) DisableLink(context.Context, *DisableLinkRequest) (*DisableLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableLink not implemented")
+}
+func (UnimplementedAdminServer) EnableLink(context.Context, *EnableLinkRequest) (*EnableLinkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableLink not implemented")
+}
+func (UnimplementedAdminServer) DisableHost(context.Context, *DisableHostRequest) (*DisableHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableHost not implemented")
+}
+func (UnimplementedAdminServer) ListReports(context.Context, *ListReportsRequest) (*ListReportsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListReports not implemented")
+}
+func (UnimplementedAdminServer) ApproveReport(context.Context, *ApproveReportRequest) (*ApproveReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveReport not implemented")
+}
+func (UnimplementedAdminServer) DismissReport(context.Context, *DismissReportRequest) (*DismissReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DismissReport not implemented")
+}
+func (UnimplementedAdminServer) QueryAudit(context.Context, *QueryAuditRequest) (*QueryAuditResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAudit not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+func (UnimplementedAdminServer) testEmbeddedByValue()               {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_FindLinks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindLinksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).FindLinks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_FindLinks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).FindLinks(ctx, req.(*FindLinksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DisableLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DisableLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DisableLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DisableLink(ctx, req.(*DisableLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_EnableLink_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableLinkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).EnableLink(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_EnableLink_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).EnableLink(ctx, req.(*EnableLinkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DisableHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DisableHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DisableHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DisableHost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DisableHost(ctx, req.(*DisableHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListReports_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListReportsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListReports(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListReports_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListReports(ctx, req.(*ListReportsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ApproveReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ApproveReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ApproveReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ApproveReport(ctx, req.(*ApproveReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DismissReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DismissReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DismissReport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DismissReport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DismissReport(ctx, req.(*DismissReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_QueryAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).QueryAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_QueryAudit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).QueryAudit(ctx, req.(*QueryAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "shortener.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindLinks",
+			Handler:    _Admin_FindLinks_Handler,
+		},
+		{
+			MethodName: "DisableLink",
+			Handler:    _Admin_DisableLink_Handler,
+		},
+		{
+			MethodName: "EnableLink",
+			Handler:    _Admin_EnableLink_Handler,
+		},
+		{
+			MethodName: "DisableHost",
+			Handler:    _Admin_DisableHost_Handler,
+		},
+		{
+			MethodName: "ListReports",
+			Handler:    _Admin_ListReports_Handler,
+		},
+		{
+			MethodName: "ApproveReport",
+			Handler:    _Admin_ApproveReport_Handler,
+		},
+		{
+			MethodName: "DismissReport",
+			Handler:    _Admin_DismissReport_Handler,
+		},
+		{
+			MethodName: "QueryAudit",
+			Handler:    _Admin_QueryAudit_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/url.proto",